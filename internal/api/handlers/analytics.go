@@ -274,7 +274,22 @@ func (h *Handler) AnalyticsTimeHandler(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, data)
+
+	online, err := h.DB.GetInstagramOnlineFollowersByHour(c.Request.Context(), database.GetInstagramOnlineFollowersByHourParams{
+		UserID:    user.ID,
+		StartDate: f.StartDate,
+		EndDate:   f.EndDate,
+	})
+	if err != nil {
+		log.Printf("Error getting Instagram online followers: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"posts":            data,
+		"online_followers": online,
+	})
 }
 
 func (h *Handler) AnalyticsPostTypesHandler(c *gin.Context) {
@@ -441,6 +456,197 @@ func (h *Handler) AnalyticsGSCTopPagesHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, data)
 }
 
+func (h *Handler) AnalyticsYouTubeChannelHandler(c *gin.Context) {
+	user, loggedIn := h.GetAuthenticatedUser(c)
+	if !loggedIn {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	f := parseAnalyticsFilters(c, user.ID)
+	data, err := h.DB.GetYouTubeChannelDailyStats(c.Request.Context(), database.GetYouTubeChannelDailyStatsParams{
+		UserID:    f.UserID,
+		StartDate: f.StartDate,
+		EndDate:   f.EndDate,
+	})
+	if err != nil {
+		log.Printf("Error getting YouTube channel stats: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+func (h *Handler) AnalyticsYouTubeTopVideosHandler(c *gin.Context) {
+	user, loggedIn := h.GetAuthenticatedUser(c)
+	if !loggedIn {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	f := parseAnalyticsFilters(c, user.ID)
+	data, err := h.DB.GetYouTubeTopVideosByNetSubscribers(c.Request.Context(), database.GetYouTubeTopVideosByNetSubscribersParams{
+		UserID:    f.UserID,
+		StartDate: f.StartDate,
+		EndDate:   f.EndDate,
+	})
+	if err != nil {
+		log.Printf("Error getting YouTube top videos: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+func (h *Handler) AnalyticsYouTubeVideoHandler(c *gin.Context) {
+	user, loggedIn := h.GetAuthenticatedUser(c)
+	if !loggedIn {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	postID, err := uuid.Parse(c.Param("post_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	data, err := h.DB.GetYouTubeVideoDailyStats(c.Request.Context(), database.GetYouTubeVideoDailyStatsParams{
+		PostID: postID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		log.Printf("Error getting YouTube video stats: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+func (h *Handler) AnalyticsRedditSubredditsHandler(c *gin.Context) {
+	user, loggedIn := h.GetAuthenticatedUser(c)
+	if !loggedIn {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	f := parseAnalyticsFilters(c, user.ID)
+	data, err := h.DB.GetRedditSubredditBreakdown(c.Request.Context(), database.GetRedditSubredditBreakdownParams{
+		UserID:    f.UserID,
+		StartDate: f.StartDate,
+		EndDate:   f.EndDate,
+	})
+	if err != nil {
+		log.Printf("Error getting Reddit subreddit breakdown: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+func (h *Handler) AnalyticsReactionsHandler(c *gin.Context) {
+	user, loggedIn := h.GetAuthenticatedUser(c)
+	if !loggedIn {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	f := parseAnalyticsFilters(c, user.ID)
+	data, err := h.DB.GetTopReactionsBySource(c.Request.Context(), database.GetTopReactionsBySourceParams{
+		UserID:    f.UserID,
+		StartDate: f.StartDate,
+		EndDate:   f.EndDate,
+	})
+	if err != nil {
+		log.Printf("Error getting top reactions: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+func (h *Handler) AnalyticsPostReactionsHandler(c *gin.Context) {
+	user, loggedIn := h.GetAuthenticatedUser(c)
+	if !loggedIn {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	postID, err := uuid.Parse(c.Param("post_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	data, err := h.DB.GetPostReactionBreakdown(c.Request.Context(), database.GetPostReactionBreakdownParams{
+		PostID: postID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		log.Printf("Error getting post reaction breakdown: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+func (h *Handler) AnalyticsInstagramStoryHandler(c *gin.Context) {
+	user, loggedIn := h.GetAuthenticatedUser(c)
+	if !loggedIn {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	postID, err := uuid.Parse(c.Param("post_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	data, err := h.DB.GetInstagramStorySnapshots(c.Request.Context(), database.GetInstagramStorySnapshotsParams{
+		PostID: postID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		log.Printf("Error getting Instagram story snapshots: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+func (h *Handler) AnalyticsInstagramInsightsHandler(c *gin.Context) {
+	user, loggedIn := h.GetAuthenticatedUser(c)
+	if !loggedIn {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	f := parseAnalyticsFilters(c, user.ID)
+	daily, err := h.DB.GetInstagramAccountInsights(c.Request.Context(), database.GetInstagramAccountInsightsParams{
+		UserID:    user.ID,
+		StartDate: f.StartDate,
+		EndDate:   f.EndDate,
+	})
+	if err != nil {
+		log.Printf("Error getting Instagram account insights: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	demographics, err := h.DB.GetLatestInstagramFollowerDemographics(c.Request.Context(), user.ID)
+	if err != nil {
+		log.Printf("Error getting Instagram follower demographics: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"daily":        daily,
+		"demographics": demographics,
+	})
+}
+
 func (h *Handler) AnalyticsPostingConsistencyHandler(c *gin.Context) {
 	user, loggedIn := h.GetAuthenticatedUser(c)
 	if !loggedIn {
@@ -15,6 +15,7 @@ import (
 
 	"github.com/fluffyriot/rpsync/internal/backup"
 	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/storage"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -73,10 +74,19 @@ func (h *Handler) BackupExportHandler(c *gin.Context) {
 
 		filename := filepath.Base(zipPath)
 		downloadUrl := fmt.Sprintf("/outputs/%s", filename)
+		message := "Full backup created successfully"
+		remoteUrl, err := storage.Publish(h.DB, h.Config.TokenEncryptionKey, userID, zipPath)
+		if err != nil {
+			log.Printf("backup upload failed: %v", err)
+			message = "Full backup created, but upload to remote storage failed: " + err.Error()
+		} else if storage.IsRemote(remoteUrl) {
+			downloadUrl = remoteUrl
+			message = "Full backup created and uploaded to remote storage"
+		}
 		h.DB.ChangeExportStatusById(bgCtx, database.ChangeExportStatusByIdParams{
 			ID:            exportID,
 			ExportStatus:  "Completed",
-			StatusMessage: sql.NullString{String: "Full backup created successfully", Valid: true},
+			StatusMessage: sql.NullString{String: message, Valid: true},
 			DownloadUrl:   sql.NullString{String: downloadUrl, Valid: true},
 			CompletedAt:   time.Now(),
 		})
@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package handlers
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/fetcher"
+	"github.com/fluffyriot/rpsync/internal/fetcher/cookies"
+	"github.com/fluffyriot/rpsync/internal/fetcher/sources"
+	"github.com/gin-gonic/gin"
+)
+
+const cookieTestTimeout = 90 * time.Second
+
+// CookieInfo describes a stored cookie without its value.
+type CookieInfo struct {
+	Name     string     `json:"name"`
+	Domain   string     `json:"domain"`
+	Expires  *time.Time `json:"expires,omitempty"`
+	Session  bool       `json:"session"`
+	Required bool       `json:"required"`
+	Expired  bool       `json:"expired"`
+}
+
+func (h *Handler) GetSourceCookiesHandler(c *gin.Context) {
+	source, ok := h.getOwnedSource(c)
+	if !ok {
+		return
+	}
+
+	if !cookies.IsSupported(source.Network) {
+		c.JSON(400, gin.H{"error": "Cookies are not used for " + source.Network})
+		return
+	}
+
+	jar, err := sources.LoadSourceCookies(c.Request.Context(), h.DB, h.Config.TokenEncryptionKey, *source)
+	if err != nil {
+		c.JSON(404, gin.H{"error": "Failed to load cookies: " + err.Error()})
+		return
+	}
+
+	now := time.Now()
+	info := make([]CookieInfo, 0, len(jar))
+	for _, ck := range jar {
+		ci := CookieInfo{
+			Name:     ck.Name,
+			Domain:   ck.Domain,
+			Session:  ck.Expires.IsZero(),
+			Required: cookies.IsRequired(source.Network, ck.Name),
+		}
+		if !ck.Expires.IsZero() {
+			expires := ck.Expires
+			ci.Expires = &expires
+			ci.Expired = expires.Before(now)
+		}
+		info = append(info, ci)
+	}
+
+	sort.SliceStable(info, func(i, j int) bool {
+		if info[i].Required != info[j].Required {
+			return info[i].Required
+		}
+		return info[i].Name < info[j].Name
+	})
+
+	resp := gin.H{
+		"network": source.Network,
+		"cookies": info,
+	}
+	if expires, ok := cookies.SessionExpiry(source.Network, jar); ok {
+		resp["session_expires"] = expires
+	}
+	if err := cookies.Validate(source.Network, jar); err != nil {
+		resp["problem"] = err.Error()
+	}
+
+	c.JSON(200, resp)
+}
+
+func (h *Handler) TestSourceCookiesHandler(c *gin.Context) {
+	source, ok := h.getOwnedSource(c)
+	if !ok {
+		return
+	}
+
+	if !cookies.IsSupported(source.Network) {
+		c.JSON(400, gin.H{"error": "Cookies are not used for " + source.Network})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), cookieTestTimeout)
+	defer cancel()
+
+	jar, err := sources.LoadSourceCookies(ctx, h.DB, h.Config.TokenEncryptionKey, *source)
+	if err != nil {
+		c.JSON(404, gin.H{"error": "Failed to load cookies: " + err.Error()})
+		return
+	}
+
+	client, err := fetcher.ClientForSource(ctx, h.DB, h.Fetcher, h.Config.TokenEncryptionKey, source.ID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to apply network settings: " + err.Error()})
+		return
+	}
+
+	if err := cookies.Test(ctx, client, source.Network, jar); err != nil {
+		c.JSON(200, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"success": true, "message": "Cookies are valid; " + source.Network + " accepted the session."})
+}
+
+// cookieWarnings lists the active sources whose session cookies have expired
+// or will within cookies.ExpiryWarning.
+func (h *Handler) cookieWarnings(ctx context.Context, activeSources []database.Source) []CookieWarning {
+	warnings := []CookieWarning{}
+	now := time.Now()
+
+	for _, s := range activeSources {
+		if !cookies.IsSupported(s.Network) {
+			continue
+		}
+		jar, err := sources.LoadSourceCookies(ctx, h.DB, h.Config.TokenEncryptionKey, s)
+		if err != nil {
+			// Missing or unreadable cookies already fail the sync with a
+			// clearer message.
+			continue
+		}
+		expires, ok := cookies.SessionExpiry(s.Network, jar)
+		if !ok || expires.Sub(now) > cookies.ExpiryWarning {
+			continue
+		}
+		warnings = append(warnings, CookieWarning{
+			SourceID: s.ID.String(),
+			Network:  s.Network,
+			UserName: s.UserName,
+			Expires:  expires,
+			Expired:  expires.Before(now),
+		})
+	}
+
+	sort.Slice(warnings, func(i, j int) bool { return warnings[i].Expires.Before(warnings[j].Expires) })
+	return warnings
+}
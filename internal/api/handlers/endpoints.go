@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package handlers
+
+import (
+	"strings"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	fetcher_common "github.com/fluffyriot/rpsync/internal/fetcher/common"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SourceEndpoint struct {
+	DefaultBaseURL string `json:"default_base_url"`
+	BaseURL        string `json:"base_url"`
+}
+
+type UpdateSourceEndpointsRequest struct {
+	Endpoints []SourceEndpoint `json:"endpoints"`
+}
+
+func (h *Handler) getOwnedSource(c *gin.Context) (*database.Source, bool) {
+	user, loggedIn := h.GetAuthenticatedUser(c)
+	if !loggedIn {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+
+	sourceID, err := uuid.Parse(c.Param("source_id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid source ID"})
+		return nil, false
+	}
+
+	source, err := h.DB.GetSourceById(c.Request.Context(), sourceID)
+	if err != nil || source.UserID != user.ID {
+		c.JSON(404, gin.H{"error": "Source not found"})
+		return nil, false
+	}
+
+	return &source, true
+}
+
+func (h *Handler) GetSourceEndpointsHandler(c *gin.Context) {
+	source, ok := h.getOwnedSource(c)
+	if !ok {
+		return
+	}
+
+	defaults := fetcher_common.SourceBaseURLs(source.Network, source.UserName)
+	if len(defaults) == 0 {
+		c.JSON(400, gin.H{"error": "Endpoint overrides are not supported for this network"})
+		return
+	}
+
+	rows, err := h.DB.GetSourceEndpoints(c.Request.Context(), source.ID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to get endpoints: " + err.Error()})
+		return
+	}
+
+	overrides := make(map[string]string, len(rows))
+	for _, r := range rows {
+		overrides[r.DefaultBaseUrl] = r.BaseUrl
+	}
+
+	endpoints := make([]SourceEndpoint, 0, len(defaults))
+	for _, d := range defaults {
+		endpoints = append(endpoints, SourceEndpoint{DefaultBaseURL: d, BaseURL: overrides[d]})
+	}
+
+	c.JSON(200, gin.H{"network": source.Network, "endpoints": endpoints})
+}
+
+func (h *Handler) UpdateSourceEndpointsHandler(c *gin.Context) {
+	source, ok := h.getOwnedSource(c)
+	if !ok {
+		return
+	}
+
+	defaults := fetcher_common.SourceBaseURLs(source.Network, source.UserName)
+	if len(defaults) == 0 {
+		c.JSON(400, gin.H{"error": "Endpoint overrides are not supported for this network"})
+		return
+	}
+
+	var req UpdateSourceEndpointsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	known := make(map[string]bool, len(defaults))
+	for _, d := range defaults {
+		known[d] = true
+	}
+
+	cleaned := make([]SourceEndpoint, 0, len(req.Endpoints))
+	for _, e := range req.Endpoints {
+		base := strings.TrimRight(strings.TrimSpace(e.BaseURL), "/")
+		if base == "" {
+			continue
+		}
+		if !known[e.DefaultBaseURL] {
+			c.JSON(400, gin.H{"error": "Unknown endpoint: " + e.DefaultBaseURL})
+			return
+		}
+		if _, err := fetcher_common.ParseBaseURL(base); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		cleaned = append(cleaned, SourceEndpoint{DefaultBaseURL: e.DefaultBaseURL, BaseURL: base})
+	}
+
+	ctx := c.Request.Context()
+	tx, err := h.DBConn.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to update endpoints: " + err.Error()})
+		return
+	}
+	defer tx.Rollback()
+	qtx := h.DB.WithTx(tx)
+
+	if err := qtx.DeleteSourceEndpoints(ctx, source.ID); err != nil {
+		c.JSON(500, gin.H{"error": "Failed to update endpoints: " + err.Error()})
+		return
+	}
+
+	for _, e := range cleaned {
+		if err := qtx.CreateSourceEndpoint(ctx, database.CreateSourceEndpointParams{
+			ID:             uuid.New(),
+			SourceID:       source.ID,
+			DefaultBaseUrl: e.DefaultBaseURL,
+			BaseUrl:        e.BaseURL,
+		}); err != nil {
+			c.JSON(500, gin.H{"error": "Failed to update endpoints: " + err.Error()})
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(500, gin.H{"error": "Failed to update endpoints: " + err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"success": true, "endpoints": cleaned})
+}
@@ -3,12 +3,15 @@ package handlers
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"path/filepath"
 	"strings"
 
 	"github.com/fluffyriot/rpsync/internal/exports"
+	"github.com/fluffyriot/rpsync/internal/storage"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -39,11 +42,22 @@ func (h *Handler) ExportsHandler(c *gin.Context) {
 		}))
 		return
 	}
+
+	deliveries, err := h.DB.GetRecentWebhookDeliveriesForUser(ctx, user.ID)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", h.CommonData(c, gin.H{
+			"error": err.Error(),
+			"title": "Error",
+		}))
+		return
+	}
+
 	c.HTML(http.StatusOK, "exports.html", h.CommonData(c, gin.H{
-		"username": user.Username,
-		"user_id":  user.ID,
-		"exports":  exports,
-		"title":    "Exports",
+		"username":   user.Username,
+		"user_id":    user.ID,
+		"exports":    exports,
+		"deliveries": deliveries,
+		"title":      "Exports",
 	}))
 }
 
@@ -63,7 +77,7 @@ func (h *Handler) ExportDeleteAllHandler(c *gin.Context) {
 				log.Printf("panic in background sync: %v", r)
 			}
 		}()
-		exports.DeleteAllExports(uid, h.DB)
+		exports.DeleteAllExports(uid, h.DB, h.Config.TokenEncryptionKey)
 	}(userId)
 
 	c.Redirect(http.StatusSeeOther, "/")
@@ -142,6 +156,11 @@ func (h *Handler) DownloadExportHandler(c *gin.Context) {
 		return
 	}
 
+	if storage.IsRemote(storedPath) {
+		h.streamRemoteExport(c, user.ID, storedPath, requestedFilename)
+		return
+	}
+
 	baseDir, err := filepath.Abs("./outputs")
 	if err != nil {
 		c.HTML(http.StatusInternalServerError, "error.html", h.CommonData(c, gin.H{
@@ -163,3 +182,42 @@ func (h *Handler) DownloadExportHandler(c *gin.Context) {
 
 	c.FileAttachment(fullPath, requestedFilename)
 }
+
+// streamRemoteExport serves an export uploaded to the user's remote storage,
+// so downloads need neither public buckets nor storage credentials.
+func (h *Handler) streamRemoteExport(c *gin.Context, userID uuid.UUID, downloadURL, filename string) {
+	ctx := c.Request.Context()
+	remote, err := storage.Load(ctx, h.DB, h.Config.TokenEncryptionKey, userID)
+	if err == nil && remote == nil {
+		err = errors.New("remote storage is no longer set up")
+	}
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", h.CommonData(c, gin.H{
+			"error": err.Error(),
+			"title": "Error",
+		}))
+		return
+	}
+
+	key, ok := remote.Key(downloadURL)
+	if !ok {
+		c.HTML(http.StatusNotFound, "error.html", h.CommonData(c, gin.H{
+			"error": "Export is stored in a different remote storage",
+			"title": "Error",
+		}))
+		return
+	}
+
+	body, err := remote.Open(ctx, key)
+	if err != nil {
+		c.HTML(http.StatusBadGateway, "error.html", h.CommonData(c, gin.H{
+			"error": "Failed to download export from remote storage: " + err.Error(),
+			"title": "Error",
+		}))
+		return
+	}
+	defer body.Close()
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.DataFromReader(http.StatusOK, -1, "application/octet-stream", body, nil)
+}
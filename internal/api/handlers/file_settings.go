@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package handlers
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/pusher/targets"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type FileSettingsResponse struct {
+	Datasets         []targets.DatasetInfo `json:"datasets"`
+	AvailableFormats []string              `json:"available_formats"`
+	targets.FileSettings
+}
+
+// getOwnedFileTarget loads a target of the user that writes files.
+func (h *Handler) getOwnedFileTarget(c *gin.Context) (*database.Target, bool) {
+	user, loggedIn := h.GetAuthenticatedUser(c)
+	if !loggedIn {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+
+	targetID, err := uuid.Parse(c.Param("target_id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid target ID"})
+		return nil, false
+	}
+
+	target, err := h.DB.GetTargetById(c.Request.Context(), targetID)
+	if err != nil || target.UserID != user.ID {
+		c.JSON(404, gin.H{"error": "Target not found"})
+		return nil, false
+	}
+	if target.TargetType != "CSV" {
+		c.JSON(400, gin.H{"error": "File settings are not supported for this target"})
+		return nil, false
+	}
+
+	return &target, true
+}
+
+func (h *Handler) GetFileSettingsHandler(c *gin.Context) {
+	target, ok := h.getOwnedFileTarget(c)
+	if !ok {
+		return
+	}
+
+	settings, err := targets.GetFileSettings(h.DB, target.ID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, FileSettingsResponse{
+		Datasets:         targets.Datasets(),
+		AvailableFormats: targets.FileFormats(),
+		FileSettings:     settings,
+	})
+}
+
+func (h *Handler) UpdateFileSettingsHandler(c *gin.Context) {
+	target, ok := h.getOwnedFileTarget(c)
+	if !ok {
+		return
+	}
+
+	var req targets.FileSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Files with every column are left out, so columns added later are
+	// exported too.
+	columns := make(map[string][]string, len(req.Columns))
+	for _, d := range targets.Datasets() {
+		cols, ok := req.Columns[d.Name]
+		if ok && len(cols) < len(d.Columns) {
+			columns[d.Name] = cols
+		}
+	}
+	columnsJSON, err := json.Marshal(columns)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to update file settings: " + err.Error()})
+		return
+	}
+	var formats []string
+	for _, f := range targets.FileFormats() {
+		if slices.Contains(req.Formats, f) {
+			formats = append(formats, f)
+		}
+	}
+	formatsJSON, err := json.Marshal(formats)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to update file settings: " + err.Error()})
+		return
+	}
+
+	if err := h.DB.UpsertFileTargetSettings(c.Request.Context(), database.UpsertFileTargetSettingsParams{
+		TargetID:   target.ID,
+		Columns:    columnsJSON,
+		ZipArchive: req.ZipArchive,
+		Formats:    formatsJSON,
+	}); err != nil {
+		c.JSON(500, gin.H{"error": "Failed to update file settings: " + err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"success": true})
+}
@@ -148,6 +148,15 @@ func (h *Handler) DashboardStatsHandler(c *gin.Context) {
 		return err
 	})
 
+	g.Go(func() error {
+		activeSources, err := h.DB.GetUserActiveSources(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		resp.CookieWarnings = h.cookieWarnings(ctx, activeSources)
+		return nil
+	})
+
 	if err := g.Wait(); err != nil {
 		log.Printf("Error getting dashboard stats: %v", err)
 	}
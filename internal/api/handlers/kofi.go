@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/fluffyriot/rpsync/internal/fetcher/sources"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) KofiWebhookHandler(c *gin.Context) {
+	if h.Config.DBInitErr != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": h.Config.DBInitErr.Error()})
+		return
+	}
+
+	sourceID, err := uuid.Parse(c.Param("source_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid source ID"})
+		return
+	}
+
+	data := c.PostForm("data")
+	if data == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing data field"})
+		return
+	}
+
+	var payload sources.KofiPayload
+	if err := json.Unmarshal([]byte(data), &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payload"})
+		return
+	}
+
+	if err := sources.ProcessKofiWebhook(h.DB, h.Config.TokenEncryptionKey, sourceID, payload); err != nil {
+		if errors.Is(err, sources.ErrKofiInvalidToken) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+		log.Printf("Ko-fi: failed to process webhook for source %s: %v", sourceID, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to process webhook"})
+		return
+	}
+
+	if err := sources.FetchKofiStats(h.DB, sourceID); err != nil {
+		log.Printf("Ko-fi: failed to refresh stats for source %s: %v", sourceID, err)
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -1,7 +1,11 @@
 // SPDX-License-Identifier: AGPL-3.0-only
 package handlers
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type TopSourceViewModel struct {
 	ID                  uuid.UUID `json:"id"`
@@ -37,4 +41,14 @@ type DashboardStatsResponse struct {
 	AverageWebsiteSession int64                `json:"average_website_session"`
 	SyncErrors30d         int64                `json:"sync_errors_30d"`
 	TopSources            []TopSourceViewModel `json:"top_sources"`
+	CookieWarnings        []CookieWarning      `json:"cookie_warnings"`
+}
+
+// CookieWarning flags a source whose login session cookie expires soon.
+type CookieWarning struct {
+	SourceID string    `json:"source_id"`
+	Network  string    `json:"network"`
+	UserName string    `json:"username"`
+	Expires  time.Time `json:"expires"`
+	Expired  bool      `json:"expired"`
 }
@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/fluffyriot/rpsync/internal/fetcher"
+	fetcher_common "github.com/fluffyriot/rpsync/internal/fetcher/common"
+	"github.com/gin-gonic/gin"
+)
+
+type SourceNetworkSettings struct {
+	ProxyURL  string            `json:"proxy_url"`
+	UserAgent string            `json:"user_agent"`
+	Headers   map[string]string `json:"headers"`
+}
+
+// redactProxyURL hides the proxy password. Saving the redacted value back
+// keeps the stored one.
+func redactProxyURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := fetcher_common.ParseProxyURL(raw)
+	if err != nil {
+		return raw
+	}
+	return u.Redacted()
+}
+
+func (h *Handler) GetSourceNetworkSettingsHandler(c *gin.Context) {
+	source, ok := h.getOwnedSource(c)
+	if !ok {
+		return
+	}
+
+	settings, err := fetcher.LoadNetworkSettings(c.Request.Context(), h.DB, h.Config.TokenEncryptionKey, source.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(200, SourceNetworkSettings{Headers: map[string]string{}})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to get network settings: " + err.Error()})
+		return
+	}
+
+	headers := settings.Headers
+	if headers == nil {
+		headers = map[string]string{}
+	}
+
+	c.JSON(200, SourceNetworkSettings{
+		ProxyURL:  redactProxyURL(settings.ProxyURL),
+		UserAgent: settings.UserAgent,
+		Headers:   headers,
+	})
+}
+
+func (h *Handler) UpdateSourceNetworkSettingsHandler(c *gin.Context) {
+	source, ok := h.getOwnedSource(c)
+	if !ok {
+		return
+	}
+
+	var req SourceNetworkSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	proxyURL := strings.TrimSpace(req.ProxyURL)
+	if proxyURL != "" {
+		existing, err := fetcher.LoadNetworkSettings(ctx, h.DB, h.Config.TokenEncryptionKey, source.ID)
+		if err == nil && proxyURL == redactProxyURL(existing.ProxyURL) {
+			proxyURL = existing.ProxyURL
+		}
+		if _, err := fetcher_common.ParseProxyURL(proxyURL); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	userAgent := strings.TrimSpace(req.UserAgent)
+	if strings.ContainsAny(userAgent, "\r\n") {
+		c.JSON(400, gin.H{"error": "User-Agent must be a single line"})
+		return
+	}
+
+	headers := make(map[string]string, len(req.Headers))
+	for name, value := range req.Headers {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if strings.ContainsAny(name, ":\r\n ") || strings.ContainsAny(value, "\r\n") {
+			c.JSON(400, gin.H{"error": "Invalid header: " + name})
+			return
+		}
+		headers[name] = strings.TrimSpace(value)
+	}
+
+	if proxyURL == "" && userAgent == "" && len(headers) == 0 {
+		if err := h.DB.DeleteSourceNetworkSettings(ctx, source.ID); err != nil {
+			c.JSON(500, gin.H{"error": "Failed to update network settings: " + err.Error()})
+			return
+		}
+		c.JSON(200, gin.H{"success": true})
+		return
+	}
+
+	if err := fetcher.SaveNetworkSettings(ctx, h.DB, h.Config.TokenEncryptionKey, source.ID, fetcher_common.NetworkSettings{
+		ProxyURL:  proxyURL,
+		UserAgent: userAgent,
+		Headers:   headers,
+	}); err != nil {
+		c.JSON(500, gin.H{"error": "Failed to update network settings: " + err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"success": true})
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/fetcher"
 	"github.com/fluffyriot/rpsync/internal/fetcher/sources"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -246,6 +247,12 @@ func (h *Handler) HandleDeleteRedirect(c *gin.Context) {
 			return
 		}
 
+		client, err := fetcher.ClientForSource(bgCtx, h.DB, h.Fetcher, h.Config.TokenEncryptionKey, source.ID)
+		if err != nil {
+			log.Printf("Error preparing client for restore: %v", err)
+			return
+		}
+
 		daysSinceCreation := int(time.Since(source.CreatedAt).Hours() / 24)
 		totalDays := 730 + daysSinceCreation
 
@@ -253,11 +260,11 @@ func (h *Handler) HandleDeleteRedirect(c *gin.Context) {
 		if source.Network == "Google Search Console" {
 			startDate := time.Now().AddDate(0, 0, -totalDays).Format("2006-01-02")
 			endDate := time.Now().Format("2006-01-02")
-			fetchErr = sources.FetchGoogleSearchConsoleStatsWithRange(h.DB, redirect.SourceID, h.Config.TokenEncryptionKey, startDate, endDate)
+			fetchErr = sources.FetchGoogleSearchConsoleStatsWithRange(h.DB, redirect.SourceID, h.Config.TokenEncryptionKey, startDate, endDate, client)
 		} else {
 			startDate := fmt.Sprintf("%ddaysAgo", totalDays)
 			endDate := "today"
-			fetchErr = sources.FetchGoogleAnalyticsStatsWithRange(h.DB, redirect.SourceID, h.Config.TokenEncryptionKey, startDate, endDate)
+			fetchErr = sources.FetchGoogleAnalyticsStatsWithRange(h.DB, redirect.SourceID, h.Config.TokenEncryptionKey, startDate, endDate, client)
 		}
 		if fetchErr != nil {
 			log.Printf("Error re-fetching stats after redirect deletion: %v", fetchErr)
@@ -83,6 +83,13 @@ func (h *Handler) SyncSettingsHandler(c *gin.Context) {
 		c.SetCookie("backup_import_success", "", -1, "/", "", true, true)
 	}
 
+	var exportStorage *database.ExportStorage
+	if cfg, err := h.DB.GetExportStorage(c.Request.Context(), user.ID); err == nil {
+		cfg.EncryptedSecret = nil
+		cfg.Nonce = nil
+		exportStorage = &cfg
+	}
+
 	c.HTML(http.StatusOK, "sync-settings.html", h.CommonData(c, gin.H{
 		"sync_period":              user.SyncPeriod,
 		"allow_new_user_creation":  allowCreateUser,
@@ -94,6 +101,7 @@ func (h *Handler) SyncSettingsHandler(c *gin.Context) {
 		"is_secure_context":        isSecure,
 		"is_passkey_supported":     isPasskeySupported,
 		"import_success":           importSuccess,
+		"export_storage":           exportStorage,
 	}))
 }
 
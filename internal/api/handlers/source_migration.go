@@ -0,0 +1,265 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/pusher"
+	"github.com/fluffyriot/rpsync/internal/pusher/targets"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RenameSourceHandler points a source at the account's new handle. Posts the
+// account authored under the old handle are rewritten so they keep matching
+// the source; posts, stats and target mappings are left in place.
+func (h *Handler) RenameSourceHandler(c *gin.Context) {
+	source, ok := h.getOwnedSourceForm(c, "source_id")
+	if !ok {
+		return
+	}
+
+	userName := strings.TrimSpace(c.PostForm("username"))
+	if userName == "" {
+		h.sourceError(c, http.StatusBadRequest, "New username is required")
+		return
+	}
+	if userName == source.UserName {
+		c.Redirect(http.StatusSeeOther, "/sources")
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := h.DBConn.BeginTx(ctx, nil)
+	if err != nil {
+		h.sourceError(c, http.StatusInternalServerError, "Failed to rename source: "+err.Error())
+		return
+	}
+	defer tx.Rollback()
+	qtx := h.DB.WithTx(tx)
+
+	if _, err := qtx.UpdateSourceUserName(ctx, database.UpdateSourceUserNameParams{
+		ID:       source.ID,
+		UserName: userName,
+	}); err != nil {
+		h.sourceError(c, http.StatusInternalServerError, "Failed to rename source: "+err.Error())
+		return
+	}
+
+	if err := qtx.RenamePostAuthor(ctx, database.RenamePostAuthorParams{
+		NewAuthor: userName,
+		SourceID:  source.ID,
+		OldAuthor: source.UserName,
+	}); err != nil {
+		h.sourceError(c, http.StatusInternalServerError, "Failed to rename post authors: "+err.Error())
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		h.sourceError(c, http.StatusInternalServerError, "Failed to rename source: "+err.Error())
+		return
+	}
+
+	h.resetSourceSyncStatus(ctx, source.ID)
+
+	c.Redirect(http.StatusSeeOther, "/sources")
+}
+
+// MergeSourceHandler moves a source's posts, stats and logs into another
+// source of the same network and deletes it. The surviving source keeps its
+// own credentials, settings and target mappings.
+func (h *Handler) MergeSourceHandler(c *gin.Context) {
+	from, ok := h.getOwnedSourceForm(c, "source_id")
+	if !ok {
+		return
+	}
+	into, ok := h.getOwnedSourceForm(c, "into_source_id")
+	if !ok {
+		return
+	}
+
+	if from.ID == into.ID {
+		h.sourceError(c, http.StatusBadRequest, "A source cannot be merged into itself")
+		return
+	}
+	if from.Network != into.Network {
+		h.sourceError(c, http.StatusBadRequest, "Only sources of the same network can be merged")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	syncedTargets, err := h.DB.GetSourcesOfTarget(ctx, from.ID)
+	if err != nil {
+		h.sourceError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Targets that do not follow posts across a merge get the merged posts'
+	// mappings detached, so their next sync drops the old rows and the
+	// surviving source pushes the posts again.
+	var detachTargets []uuid.UUID
+	for _, synced := range syncedTargets {
+		target, err := h.DB.GetTargetById(ctx, synced.TargetID)
+		if err != nil {
+			h.sourceError(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		provider, err := targets.Get(target.TargetType)
+		if err != nil || !provider.KeepsMergedPosts {
+			detachTargets = append(detachTargets, target.ID)
+		}
+	}
+
+	tx, err := h.DBConn.BeginTx(ctx, nil)
+	if err != nil {
+		h.sourceError(c, http.StatusInternalServerError, "Failed to merge sources: "+err.Error())
+		return
+	}
+	defer tx.Rollback()
+
+	if err := mergeSources(ctx, h.DB.WithTx(tx), from.ID, into.ID, detachTargets); err != nil {
+		h.sourceError(c, http.StatusInternalServerError, "Failed to merge sources: "+err.Error())
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		h.sourceError(c, http.StatusInternalServerError, "Failed to merge sources: "+err.Error())
+		return
+	}
+
+	// Targets are only cleaned up once the merge has committed. The merged
+	// source is kept until then, as the targets look up its rows by source;
+	// a failed cleanup leaves it empty and the merge can be run again.
+	for _, target := range syncedTargets {
+		if err := pusher.RemoveByTarget(target.TargetID, from.ID, h.DB, h.Puller, h.Config.TokenEncryptionKey); err != nil {
+			h.sourceError(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	if err := h.DB.DeleteSource(ctx, from.ID); err != nil {
+		h.sourceError(c, http.StatusInternalServerError, "Failed to delete merged source: "+err.Error())
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, "/sources")
+}
+
+// mergeSources moves the history and posts of one source into another. Posts
+// the surviving source already has stay with the merged source and are
+// deleted with it, so their reaction history, per-post stats, details, tags
+// and stream sessions are moved onto the surviving copies first.
+func mergeSources(ctx context.Context, qtx *database.Queries, fromID, intoID uuid.UUID, detachTargets []uuid.UUID) error {
+	steps := []func() error{
+		func() error {
+			return qtx.MergeSourceStats(ctx, database.MergeSourceStatsParams{IntoSourceID: intoID, FromSourceID: fromID})
+		},
+		func() error {
+			return qtx.MergeSourceLogs(ctx, database.MergeSourceLogsParams{IntoSourceID: intoID, FromSourceID: fromID})
+		},
+		func() error {
+			return qtx.MergeSourceExclusions(ctx, database.MergeSourceExclusionsParams{IntoSourceID: intoID, FromSourceID: fromID})
+		},
+		func() error {
+			return qtx.MergeSourceAnalyticsPageStats(ctx, database.MergeSourceAnalyticsPageStatsParams{IntoSourceID: intoID, FromSourceID: fromID})
+		},
+		func() error {
+			return qtx.MergeSourceAnalyticsSiteStats(ctx, database.MergeSourceAnalyticsSiteStatsParams{IntoSourceID: intoID, FromSourceID: fromID})
+		},
+		func() error {
+			return qtx.MergeSourceInstagramAccountInsights(ctx, database.MergeSourceInstagramAccountInsightsParams{IntoSourceID: intoID, FromSourceID: fromID})
+		},
+		func() error {
+			return qtx.MergeSourceInstagramOnlineFollowers(ctx, database.MergeSourceInstagramOnlineFollowersParams{IntoSourceID: intoID, FromSourceID: fromID})
+		},
+		func() error {
+			return qtx.MergeSourceYoutubeChannelDailyStats(ctx, database.MergeSourceYoutubeChannelDailyStatsParams{IntoSourceID: intoID, FromSourceID: fromID})
+		},
+		func() error {
+			return qtx.MergeSourceRedirects(ctx, database.MergeSourceRedirectsParams{IntoSourceID: intoID, FromSourceID: fromID})
+		},
+		func() error {
+			return qtx.MergeSourceStreamSessions(ctx, database.MergeSourceStreamSessionsParams{IntoSourceID: intoID, FromSourceID: fromID})
+		},
+		func() error {
+			return qtx.MergeSourceSupporterEvents(ctx, database.MergeSourceSupporterEventsParams{IntoSourceID: intoID, FromSourceID: fromID})
+		},
+		func() error {
+			return qtx.MergeSourceSupporterTierStats(ctx, database.MergeSourceSupporterTierStatsParams{IntoSourceID: intoID, FromSourceID: fromID})
+		},
+		func() error {
+			return qtx.MergeSourceDuplicateReactionHistory(ctx, database.MergeSourceDuplicateReactionHistoryParams{IntoSourceID: intoID, FromSourceID: fromID})
+		},
+		func() error {
+			return qtx.MergeSourceDuplicateReactionBreakdown(ctx, database.MergeSourceDuplicateReactionBreakdownParams{IntoSourceID: intoID, FromSourceID: fromID})
+		},
+		func() error {
+			return qtx.MergeSourceDuplicateYoutubeVideoDailyStats(ctx, database.MergeSourceDuplicateYoutubeVideoDailyStatsParams{IntoSourceID: intoID, FromSourceID: fromID})
+		},
+		func() error {
+			return qtx.MergeSourceDuplicateInstagramStorySnapshots(ctx, database.MergeSourceDuplicateInstagramStorySnapshotsParams{IntoSourceID: intoID, FromSourceID: fromID})
+		},
+		func() error {
+			return qtx.MergeSourceDuplicateRedditPostDetails(ctx, database.MergeSourceDuplicateRedditPostDetailsParams{IntoSourceID: intoID, FromSourceID: fromID})
+		},
+		func() error {
+			return qtx.MergeSourceDuplicatePostTags(ctx, database.MergeSourceDuplicatePostTagsParams{IntoSourceID: intoID, FromSourceID: fromID})
+		},
+		func() error {
+			return qtx.MergeSourceDuplicateStreamSessionPosts(ctx, database.MergeSourceDuplicateStreamSessionPostsParams{IntoSourceID: intoID, FromSourceID: fromID})
+		},
+	}
+	for _, targetID := range detachTargets {
+		steps = append(steps, func() error {
+			return qtx.MergeSourceDetachPostsFromTarget(ctx, database.MergeSourceDetachPostsFromTargetParams{TargetID: targetID, FromSourceID: fromID})
+		})
+	}
+	steps = append(steps, func() error {
+		return qtx.MergeSourcePosts(ctx, database.MergeSourcePostsParams{IntoSourceID: intoID, FromSourceID: fromID})
+	})
+
+	return runMergeSteps(steps)
+}
+
+func runMergeSteps(steps []func() error) error {
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getOwnedSourceForm is getOwnedSource for form posts that render the error
+// page instead of JSON.
+func (h *Handler) getOwnedSourceForm(c *gin.Context, field string) (database.Source, bool) {
+	user, loggedIn := h.GetAuthenticatedUser(c)
+	if !loggedIn {
+		c.Redirect(http.StatusFound, "/login")
+		return database.Source{}, false
+	}
+
+	sourceID, err := uuid.Parse(c.PostForm(field))
+	if err != nil {
+		h.sourceError(c, http.StatusBadRequest, "Invalid source ID")
+		return database.Source{}, false
+	}
+
+	source, err := h.DB.GetSourceById(c.Request.Context(), sourceID)
+	if err != nil || source.UserID != user.ID {
+		h.sourceError(c, http.StatusNotFound, "Source not found")
+		return database.Source{}, false
+	}
+
+	return source, true
+}
+
+func (h *Handler) sourceError(c *gin.Context, status int, message string) {
+	c.HTML(status, "error.html", h.CommonData(c, gin.H{
+		"error": message,
+		"title": "Error",
+	}))
+}
@@ -4,12 +4,20 @@ package handlers
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"os"
+	"strings"
 
 	"github.com/fluffyriot/rpsync/internal/authhelp"
 	"github.com/fluffyriot/rpsync/internal/config"
 	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/fetcher/archive"
+	fetcher_common "github.com/fluffyriot/rpsync/internal/fetcher/common"
+	"github.com/fluffyriot/rpsync/internal/fetcher/cookies"
+	"github.com/fluffyriot/rpsync/internal/fetcher/sources"
 	"github.com/fluffyriot/rpsync/internal/helpers"
 	"github.com/fluffyriot/rpsync/internal/pusher"
 	"github.com/gin-contrib/sessions"
@@ -48,6 +56,8 @@ func (h *Handler) SourcesHandler(c *gin.Context) {
 		"user_id":           user.ID,
 		"sources":           sources,
 		"available_sources": helpers.AvailableSources,
+		"endpoint_networks": fetcher_common.EndpointNetworks(),
+		"base_url":          h.Config.BaseURL,
 		"title":             "Sources",
 	}))
 }
@@ -125,7 +135,7 @@ func (h *Handler) SourcesSetupHandler(c *gin.Context) {
 	}
 
 	if network == "TikTok" {
-		c.Redirect(http.StatusSeeOther, "/auth/tiktok/login?username="+username)
+		c.Redirect(http.StatusSeeOther, "/auth/tiktok/login?username="+username+"&source_id="+sid)
 		return
 	}
 
@@ -292,6 +302,18 @@ func (h *Handler) UpdateSourceTokenHandler(c *gin.Context) {
 		return
 	}
 
+	if cookies.IsSupported(source.Network) {
+		if err := h.replaceSourceCookies(ctx, source, []byte(newToken)); err != nil {
+			c.HTML(http.StatusBadRequest, "error.html", h.CommonData(c, gin.H{
+				"error": "Failed to update cookies: " + err.Error(),
+				"title": "Error",
+			}))
+			return
+		}
+		c.Redirect(http.StatusSeeOther, "/sources")
+		return
+	}
+
 	if err := authhelp.ReplaceSourceToken(ctx, h.DB, h.Config.TokenEncryptionKey, sourceID, newToken); err != nil {
 		c.HTML(http.StatusInternalServerError, "error.html", h.CommonData(c, gin.H{
 			"error": "Failed to update token: " + err.Error(),
@@ -300,14 +322,39 @@ func (h *Handler) UpdateSourceTokenHandler(c *gin.Context) {
 		return
 	}
 
+	h.resetSourceSyncStatus(ctx, sourceID)
+
+	c.Redirect(http.StatusSeeOther, "/sources")
+}
+
+// replaceSourceCookies stores a cookie export in any supported format as the
+// source's session, keeping only cookies for the source's network.
+func (h *Handler) replaceSourceCookies(ctx context.Context, source database.Source, data []byte) error {
+	jar, _, err := cookies.Parse(data)
+	if err != nil {
+		return err
+	}
+
+	jar = cookies.Filter(source.Network, jar)
+	if err := cookies.Validate(source.Network, jar); err != nil {
+		return err
+	}
+
+	if err := cookies.Store(ctx, h.DB, h.Config.TokenEncryptionKey, source.ID, jar); err != nil {
+		return err
+	}
+
+	h.resetSourceSyncStatus(ctx, source.ID)
+	return nil
+}
+
+func (h *Handler) resetSourceSyncStatus(ctx context.Context, sourceID uuid.UUID) {
 	_, _ = h.DB.UpdateSourceSyncStatusById(ctx, database.UpdateSourceSyncStatusByIdParams{
 		ID:           sourceID,
 		SyncStatus:   "Initialized",
 		StatusReason: sql.NullString{},
 		LastSynced:   sql.NullTime{},
 	})
-
-	c.Redirect(http.StatusSeeOther, "/sources")
 }
 
 func (h *Handler) HandleExportCookies(c *gin.Context) {
@@ -320,8 +367,16 @@ func (h *Handler) HandleExportCookies(c *gin.Context) {
 		return
 	}
 
-	source, err := h.DB.GetSourceById(context.Background(), sourceID)
-	if err != nil {
+	user, loggedIn := h.GetAuthenticatedUser(c)
+	if !loggedIn {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	source, err := h.DB.GetSourceById(ctx, sourceID)
+	if err != nil || source.UserID != user.ID {
 		c.HTML(http.StatusNotFound, "error.html", h.CommonData(c, gin.H{
 			"error": "Source not found",
 			"title": "Error",
@@ -329,18 +384,34 @@ func (h *Handler) HandleExportCookies(c *gin.Context) {
 		return
 	}
 
-	if source.Network != "TikTok" {
+	if !cookies.IsSupported(source.Network) {
 		c.HTML(http.StatusBadRequest, "error.html", h.CommonData(c, gin.H{
-			"error": "Cookie export only supported for TikTok",
+			"error": "Cookie export is not supported for " + source.Network,
 			"title": "Error",
 		}))
 		return
 	}
 
-	filename := "tiktok_" + source.UserName + ".json"
-	filepath := "outputs/tiktok_cookies/" + filename
+	jar, err := sources.LoadSourceCookies(ctx, h.DB, h.Config.TokenEncryptionKey, source)
+	if err != nil {
+		c.HTML(http.StatusNotFound, "error.html", h.CommonData(c, gin.H{
+			"error": "Failed to export cookies: " + err.Error(),
+			"title": "Error",
+		}))
+		return
+	}
+
+	data, err := cookies.Marshal(jar)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", h.CommonData(c, gin.H{
+			"error": "Failed to export cookies: " + err.Error(),
+			"title": "Error",
+		}))
+		return
+	}
 
-	c.FileAttachment(filepath, filename)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s_%s.json"`, strings.ToLower(source.Network), source.UserName))
+	c.Data(http.StatusOK, "application/json", data)
 }
 
 func (h *Handler) HandleImportCookies(c *gin.Context) {
@@ -350,28 +421,149 @@ func (h *Handler) HandleImportCookies(c *gin.Context) {
 		return
 	}
 
-	file, err := c.FormFile("cookie_file")
+	user, loggedIn := h.GetAuthenticatedUser(c)
+	if !loggedIn {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	file, _, err := c.Request.FormFile("cookie_file")
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "No file uploaded"})
 		return
 	}
+	defer file.Close()
 
-	source, err := h.DB.GetSourceById(context.Background(), sourceID)
-	if err != nil {
+	ctx := c.Request.Context()
+
+	source, err := h.DB.GetSourceById(ctx, sourceID)
+	if err != nil || source.UserID != user.ID {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Source not found"})
 		return
 	}
 
-	if source.Network != "TikTok" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Cookie import only supported for TikTok"})
+	if !cookies.IsSupported(source.Network) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cookie import is not supported for " + source.Network})
 		return
 	}
 
-	dst := "outputs/tiktok_cookies/tiktok_" + source.UserName + ".json"
-	if err := c.SaveUploadedFile(file, dst); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file: " + err.Error()})
+	data, err := io.ReadAll(io.LimitReader(file, 32<<20))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file: " + err.Error()})
 		return
 	}
 
+	if err := h.replaceSourceCookies(ctx, source, data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to import cookies: " + err.Error()})
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, "/sources")
+}
+
+func (h *Handler) ImportSourceArchiveHandler(c *gin.Context) {
+	sourceID, err := uuid.Parse(c.PostForm("source_id"))
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "error.html", h.CommonData(c, gin.H{
+			"error": "Invalid source ID",
+			"title": "Error",
+		}))
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	user, loggedIn := h.GetAuthenticatedUser(c)
+	if !loggedIn {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	source, err := h.DB.GetSourceById(ctx, sourceID)
+	if err != nil || source.UserID != user.ID {
+		c.HTML(http.StatusNotFound, "error.html", h.CommonData(c, gin.H{
+			"error": "Source not found",
+			"title": "Error",
+		}))
+		return
+	}
+
+	if !archive.IsSupported(source.Network) {
+		c.HTML(http.StatusBadRequest, "error.html", h.CommonData(c, gin.H{
+			"error": "Archive import is not supported for " + source.Network,
+			"title": "Error",
+		}))
+		return
+	}
+
+	file, header, err := c.Request.FormFile("archive_file")
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "error.html", h.CommonData(c, gin.H{
+			"error": "Please select an archive file to upload.",
+			"title": "Error",
+		}))
+		return
+	}
+	defer file.Close()
+
+	// Exports can run into gigabytes, so the upload is spooled to disk and
+	// the zip is read from there instead of from memory.
+	tmp, err := os.CreateTemp("", "rpsync-archive-*")
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", h.CommonData(c, gin.H{
+			"error": "Failed to read uploaded file.",
+			"title": "Error",
+		}))
+		return
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := io.Copy(tmp, file)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", h.CommonData(c, gin.H{
+			"error": "Failed to read uploaded file.",
+			"title": "Error",
+		}))
+		return
+	}
+
+	tx, err := h.DBConn.BeginTx(ctx, nil)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", h.CommonData(c, gin.H{
+			"error": "Failed to start archive import.",
+			"title": "Error",
+		}))
+		return
+	}
+	defer tx.Rollback()
+
+	result, err := archive.Import(ctx, h.DB.WithTx(tx), source, header.Filename, tmp, size)
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "error.html", h.CommonData(c, gin.H{
+			"error": fmt.Sprintf("Archive import failed: %v", err),
+			"title": "Error",
+		}))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", h.CommonData(c, gin.H{
+			"error": "Failed to save imported posts.",
+			"title": "Error",
+		}))
+		return
+	}
+
+	_, _ = h.DB.UpdateSourceSyncStatusById(ctx, database.UpdateSourceSyncStatusByIdParams{
+		ID:         source.ID,
+		SyncStatus: source.SyncStatus,
+		StatusReason: sql.NullString{
+			String: fmt.Sprintf("Archive import: %d posts imported, %d already present or excluded", result.Imported, result.Skipped),
+			Valid:  true,
+		},
+		LastSynced: source.LastSynced,
+	})
+
 	c.Redirect(http.StatusSeeOther, "/sources")
 }
@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/fluffyriot/rpsync/internal/authhelp"
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateExportStorageHandler saves the remote storage of the user after a
+// test upload. A blank secret keeps the saved one, as long as it would still
+// be sent to the same server and bucket.
+func (h *Handler) UpdateExportStorageHandler(c *gin.Context) {
+	user, loggedIn := h.GetAuthenticatedUser(c)
+	if !loggedIn {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+	ctx := c.Request.Context()
+
+	retentionDays, err := strconv.Atoi(strings.TrimSpace(c.DefaultPostForm("retention_days", "0")))
+	if err != nil || retentionDays < 0 {
+		h.storageSettingsError(c, http.StatusBadRequest, "Retention must be a number of days, 0 to keep files forever")
+		return
+	}
+
+	cfg := database.ExportStorage{
+		UserID:        user.ID,
+		StorageType:   c.PostForm("storage_type"),
+		Endpoint:      strings.TrimSpace(c.PostForm("endpoint")),
+		Bucket:        strings.TrimSpace(c.PostForm("bucket")),
+		Region:        strings.TrimSpace(c.PostForm("region")),
+		PathPrefix:    strings.Trim(strings.TrimSpace(c.PostForm("path_prefix")), "/"),
+		AccessKey:     strings.TrimSpace(c.PostForm("access_key")),
+		RetentionDays: int32(retentionDays),
+		KeepLocal:     c.PostForm("keep_local") == "on",
+	}
+	if cfg.StorageType == storage.TypeWebDAV {
+		cfg.Bucket = ""
+		cfg.Region = ""
+	}
+
+	secret := c.PostForm("secret")
+	if secret == "" {
+		existing, err := h.DB.GetExportStorage(ctx, user.ID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			h.storageSettingsError(c, http.StatusInternalServerError, "Failed to get remote storage: "+err.Error())
+			return
+		}
+		if err == nil {
+			// Otherwise anyone with the session could point the storage at a
+			// server of their own and receive the saved secret.
+			if existing.StorageType != cfg.StorageType || existing.Endpoint != cfg.Endpoint || existing.Bucket != cfg.Bucket {
+				h.storageSettingsError(c, http.StatusBadRequest, "Enter the secret again when changing the storage type, endpoint or bucket")
+				return
+			}
+			secret, err = authhelp.DecryptSecret(existing.EncryptedSecret, existing.Nonce, h.Config.TokenEncryptionKey)
+			if err != nil {
+				h.storageSettingsError(c, http.StatusInternalServerError, "Failed to decrypt saved secret: "+err.Error())
+				return
+			}
+		}
+	}
+
+	dest, err := storage.New(cfg, secret)
+	if err != nil {
+		h.storageSettingsError(c, http.StatusBadRequest, "Invalid remote storage: "+err.Error())
+		return
+	}
+	if err := dest.Test(ctx); err != nil {
+		h.storageSettingsError(c, http.StatusBadRequest, "Could not connect to remote storage: "+err.Error())
+		return
+	}
+
+	encrypted, nonce, err := authhelp.EncryptSecret(secret, h.Config.TokenEncryptionKey)
+	if err != nil {
+		h.storageSettingsError(c, http.StatusInternalServerError, "Failed to encrypt secret: "+err.Error())
+		return
+	}
+
+	if err := h.DB.UpsertExportStorage(ctx, database.UpsertExportStorageParams{
+		UserID:          cfg.UserID,
+		StorageType:     cfg.StorageType,
+		Endpoint:        cfg.Endpoint,
+		Bucket:          cfg.Bucket,
+		Region:          cfg.Region,
+		PathPrefix:      cfg.PathPrefix,
+		AccessKey:       cfg.AccessKey,
+		EncryptedSecret: encrypted,
+		Nonce:           nonce,
+		RetentionDays:   cfg.RetentionDays,
+		KeepLocal:       cfg.KeepLocal,
+	}); err != nil {
+		h.storageSettingsError(c, http.StatusInternalServerError, "Failed to save remote storage: "+err.Error())
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, "/settings/sync?tab=backup")
+}
+
+// DeleteExportStorageHandler stops uploading exports. Files already uploaded
+// stay in the remote storage.
+func (h *Handler) DeleteExportStorageHandler(c *gin.Context) {
+	user, loggedIn := h.GetAuthenticatedUser(c)
+	if !loggedIn {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	if err := h.DB.DeleteExportStorage(c.Request.Context(), user.ID); err != nil {
+		h.storageSettingsError(c, http.StatusInternalServerError, "Failed to remove remote storage: "+err.Error())
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, "/settings/sync?tab=backup")
+}
+
+func (h *Handler) storageSettingsError(c *gin.Context, status int, message string) {
+	c.HTML(status, "error.html", h.CommonData(c, gin.H{
+		"error": message,
+		"title": "Error",
+	}))
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/fluffyriot/rpsync/internal/database"
 	"github.com/fluffyriot/rpsync/internal/helpers"
 	"github.com/fluffyriot/rpsync/internal/pusher"
+	"github.com/fluffyriot/rpsync/internal/pusher/pacer"
+	"github.com/fluffyriot/rpsync/internal/pusher/targets"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -66,6 +68,14 @@ func (h *Handler) TargetsSetupHandler(c *gin.Context) {
 		return
 	}
 
+	if _, err := targets.Get(target); err != nil {
+		c.HTML(http.StatusBadRequest, "error.html", h.CommonData(c, gin.H{
+			"error": err.Error(),
+			"title": "Error",
+		}))
+		return
+	}
+
 	_, _, err := config.CreateTargetFromForm(
 		h.DB,
 		userID,
@@ -165,6 +175,7 @@ func (h *Handler) DeleteTargetHandler(c *gin.Context) {
 		}))
 		return
 	}
+	pacer.Forget(targetID)
 
 	c.Redirect(http.StatusSeeOther, "/targets")
 }
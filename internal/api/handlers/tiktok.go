@@ -3,10 +3,13 @@ package handlers
 
 import (
 	"encoding/base64"
+	"log"
 	"net/http"
 
+	"github.com/fluffyriot/rpsync/internal/fetcher"
 	"github.com/fluffyriot/rpsync/internal/fetcher/sources"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 func (h *Handler) TikTokLoginHandler(c *gin.Context) {
@@ -19,7 +22,41 @@ func (h *Handler) TikTokLoginHandler(c *gin.Context) {
 		return
 	}
 
-	qrCode, err := sources.GlobalTikTokManager.StartLoginSession(username)
+	user, loggedIn := h.GetAuthenticatedUser(c)
+	if !loggedIn {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	sourceID, err := uuid.Parse(c.Query("source_id"))
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "error.html", h.CommonData(c, gin.H{
+			"error": "Invalid source ID",
+			"title": "Error",
+		}))
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	source, err := h.DB.GetSourceById(ctx, sourceID)
+	if err != nil || source.UserID != user.ID || source.Network != "TikTok" {
+		c.HTML(http.StatusNotFound, "error.html", h.CommonData(c, gin.H{
+			"error": "Source not found",
+			"title": "Error",
+		}))
+		return
+	}
+
+	// Apply the source's network settings, so the login browser goes out
+	// through the same proxy as syncs.
+	client, err := fetcher.ClientForSource(ctx, h.DB, h.Fetcher, h.Config.TokenEncryptionKey, sourceID)
+	if err != nil {
+		log.Printf("TikTok: Failed to apply network settings for source %s: %v", sourceID, err)
+		client = h.Fetcher
+	}
+
+	qrCode, err := sources.GlobalTikTokManager.StartLoginSession(ctx, h.DB, h.Config.TokenEncryptionKey, sourceID, username, client)
 	if err != nil {
 		c.HTML(http.StatusInternalServerError, "error.html", h.CommonData(c, gin.H{
 			"error": "Failed to start TikTok login session: " + err.Error(),
@@ -58,6 +58,20 @@ func decrypt(ciphertext, nonce, key []byte) ([]byte, error) {
 	return gcm.Open(nil, nonce, ciphertext, nil)
 }
 
+// EncryptSecret encrypts a credential kept outside the tokens table.
+func EncryptSecret(secret string, encryptionKey []byte) (ciphertext, nonce []byte, err error) {
+	return encrypt([]byte(secret), encryptionKey)
+}
+
+// DecryptSecret decrypts a credential sealed with EncryptSecret.
+func DecryptSecret(ciphertext, nonce, encryptionKey []byte) (string, error) {
+	plaintext, err := decrypt(ciphertext, nonce, encryptionKey)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
 func insertToken(
 	ctx context.Context,
 	db *database.Queries,
@@ -164,6 +164,38 @@ func ExportUserData(ctx context.Context, db *database.Queries, userID uuid.UUID,
 		return "", err
 	}
 
+	streamSessions, err := db.BackupGetStreamSessionsForUser(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get stream sessions: %w", err)
+	}
+	if err := writeJSON(w, "stream_sessions.json", convertStreamSessions(streamSessions)); err != nil {
+		return "", err
+	}
+
+	streamSamples, err := db.BackupGetStreamViewerSamplesForUser(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get stream viewer samples: %w", err)
+	}
+	if err := writeJSON(w, "stream_viewer_samples.json", convertStreamViewerSamples(streamSamples)); err != nil {
+		return "", err
+	}
+
+	supporterEvents, err := db.BackupGetSupporterEventsForUser(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get supporter events: %w", err)
+	}
+	if err := writeJSON(w, "supporter_events.json", convertSupporterEvents(supporterEvents)); err != nil {
+		return "", err
+	}
+
+	tierStats, err := db.BackupGetSupporterTierStatsForUser(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get supporter tier stats: %w", err)
+	}
+	if err := writeJSON(w, "supporter_tier_stats.json", convertSupporterTierStats(tierStats)); err != nil {
+		return "", err
+	}
+
 	return fullPath, nil
 }
 
@@ -320,6 +352,9 @@ func convertReactions(reactions []database.PostsReactionsHistory) []BackupReacti
 		if r.Views.Valid {
 			br.Views = &r.Views.Int64
 		}
+		if r.Comments.Valid {
+			br.Comments = &r.Comments.Int64
+		}
 		result = append(result, br)
 	}
 	return result
@@ -424,6 +459,15 @@ func convertSourcesStats(stats []database.SourcesStat) []BackupSourcesStat {
 		if s.AverageViews.Valid {
 			bs.AverageViews = &s.AverageViews.Float64
 		}
+		if s.Karma.Valid {
+			bs.Karma = &s.Karma.Int64
+		}
+		if s.FollowersDelta.Valid {
+			bs.FollowersDelta = &s.FollowersDelta.Int64
+		}
+		if s.FollowingDelta.Valid {
+			bs.FollowingDelta = &s.FollowingDelta.Int64
+		}
 		result = append(result, bs)
 	}
 	return result
@@ -466,3 +510,92 @@ func convertSiteStats(stats []database.AnalyticsSiteStat) []BackupAnalyticsSiteS
 	}
 	return result
 }
+
+func convertStreamSessions(sessions []database.StreamSession) []BackupStreamSession {
+	result := make([]BackupStreamSession, 0, len(sessions))
+	for _, s := range sessions {
+		bs := BackupStreamSession{
+			ID:             s.ID.String(),
+			SourceID:       s.SourceID.String(),
+			StreamID:       s.StreamID,
+			Title:          s.Title,
+			Category:       s.Category,
+			StartedAt:      s.StartedAt.Format(timeFormat),
+			LastSeenAt:     s.LastSeenAt.Format(timeFormat),
+			PeakViewers:    s.PeakViewers,
+			AverageViewers: s.AverageViewers,
+			SamplesCount:   s.SamplesCount,
+		}
+		if s.PostID.Valid {
+			id := s.PostID.UUID.String()
+			bs.PostID = &id
+		}
+		if s.EndedAt.Valid {
+			t := s.EndedAt.Time.Format(timeFormat)
+			bs.EndedAt = &t
+		}
+		result = append(result, bs)
+	}
+	return result
+}
+
+func convertStreamViewerSamples(samples []database.StreamViewerSample) []BackupStreamViewerSample {
+	result := make([]BackupStreamViewerSample, 0, len(samples))
+	for _, s := range samples {
+		result = append(result, BackupStreamViewerSample{
+			ID:          s.ID.String(),
+			SessionID:   s.SessionID.String(),
+			SampledAt:   s.SampledAt.Format(timeFormat),
+			ViewerCount: s.ViewerCount,
+		})
+	}
+	return result
+}
+
+func convertSupporterEvents(events []database.SupporterEvent) []BackupSupporterEvent {
+	result := make([]BackupSupporterEvent, 0, len(events))
+	for _, e := range events {
+		be := BackupSupporterEvent{
+			ID:             e.ID.String(),
+			CreatedAt:      e.CreatedAt.Format(timeFormat),
+			OccurredAt:     e.OccurredAt.Format(timeFormat),
+			SourceID:       e.SourceID.String(),
+			ExternalID:     e.ExternalID,
+			EventType:      e.EventType,
+			AmountCents:    e.AmountCents,
+			Currency:       e.Currency,
+			IsPublic:       e.IsPublic,
+			IsSubscription: e.IsSubscription,
+		}
+		if e.FromName.Valid {
+			be.FromName = &e.FromName.String
+		}
+		if e.FromEmail.Valid {
+			be.FromEmail = &e.FromEmail.String
+		}
+		if e.Message.Valid {
+			be.Message = &e.Message.String
+		}
+		if e.TierName.Valid {
+			be.TierName = &e.TierName.String
+		}
+		result = append(result, be)
+	}
+	return result
+}
+
+func convertSupporterTierStats(stats []database.SupporterTierStat) []BackupSupporterTierStat {
+	result := make([]BackupSupporterTierStat, 0, len(stats))
+	for _, s := range stats {
+		result = append(result, BackupSupporterTierStat{
+			ID:              s.ID.String(),
+			Date:            s.Date.Format(timeFormat),
+			SourceID:        s.SourceID.String(),
+			TierID:          s.TierID,
+			TierTitle:       s.TierTitle,
+			AmountCents:     s.AmountCents,
+			SupportersCount: s.SupportersCount,
+		})
+	}
+	return result
+}
@@ -115,6 +115,28 @@ func ImportUserData(ctx context.Context, db *database.Queries, dbConn *sql.DB, z
 		return nil, fmt.Errorf("invalid site stats data: %w", err)
 	}
 
+	// Files added after the first backup version are missing from older
+	// backups, which restore without them.
+	var streamSessions []BackupStreamSession
+	if err := parseOptionalJSON(files, "stream_sessions.json", &streamSessions); err != nil {
+		return nil, fmt.Errorf("invalid stream sessions data: %w", err)
+	}
+
+	var streamSamples []BackupStreamViewerSample
+	if err := parseOptionalJSON(files, "stream_viewer_samples.json", &streamSamples); err != nil {
+		return nil, fmt.Errorf("invalid stream viewer samples data: %w", err)
+	}
+
+	var supporterEvents []BackupSupporterEvent
+	if err := parseOptionalJSON(files, "supporter_events.json", &supporterEvents); err != nil {
+		return nil, fmt.Errorf("invalid supporter events data: %w", err)
+	}
+
+	var tierStats []BackupSupporterTierStat
+	if err := parseOptionalJSON(files, "supporter_tier_stats.json", &tierStats); err != nil {
+		return nil, fmt.Errorf("invalid supporter tier stats data: %w", err)
+	}
+
 	// Build ID remap tables
 	idMap := make(map[string]uuid.UUID)
 	targetUserID := currentUserID
@@ -165,6 +187,18 @@ func ImportUserData(ctx context.Context, db *database.Queries, dbConn *sql.DB, z
 	for _, s := range siteStats {
 		idMap[s.ID] = uuid.New()
 	}
+	for _, s := range streamSessions {
+		idMap[s.ID] = uuid.New()
+	}
+	for _, s := range streamSamples {
+		idMap[s.ID] = uuid.New()
+	}
+	for _, e := range supporterEvents {
+		idMap[e.ID] = uuid.New()
+	}
+	for _, s := range tierStats {
+		idMap[s.ID] = uuid.New()
+	}
 
 	remap := func(old string) uuid.UUID {
 		if v, ok := idMap[old]; ok {
@@ -355,7 +389,7 @@ func ImportUserData(ctx context.Context, db *database.Queries, dbConn *sql.DB, z
 
 	for _, r := range reactions {
 		syncedAt, _ := time.Parse(timeFormat, r.SyncedAt)
-		var likes, reposts, views sql.NullInt64
+		var likes, reposts, views, comments sql.NullInt64
 		if r.Likes != nil {
 			likes = sql.NullInt64{Int64: *r.Likes, Valid: true}
 		}
@@ -365,6 +399,9 @@ func ImportUserData(ctx context.Context, db *database.Queries, dbConn *sql.DB, z
 		if r.Views != nil {
 			views = sql.NullInt64{Int64: *r.Views, Valid: true}
 		}
+		if r.Comments != nil {
+			comments = sql.NullInt64{Int64: *r.Comments, Valid: true}
+		}
 		_, err := qtx.SyncReactions(ctx, database.SyncReactionsParams{
 			ID:       remap(r.ID),
 			SyncedAt: syncedAt,
@@ -372,6 +409,7 @@ func ImportUserData(ctx context.Context, db *database.Queries, dbConn *sql.DB, z
 			Likes:    likes,
 			Reposts:  reposts,
 			Views:    views,
+			Comments: comments,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to import reaction: %w", err)
@@ -461,7 +499,7 @@ func ImportUserData(ctx context.Context, db *database.Queries, dbConn *sql.DB, z
 
 	for _, s := range sourcesStats {
 		date, _ := time.Parse(timeFormat, s.Date)
-		var followers, following, postsCount sql.NullInt64
+		var followers, following, postsCount, karma, followersDelta, followingDelta sql.NullInt64
 		var avgLikes, avgReposts, avgViews sql.NullFloat64
 		if s.FollowersCount != nil {
 			followers = sql.NullInt64{Int64: *s.FollowersCount, Valid: true}
@@ -481,6 +519,15 @@ func ImportUserData(ctx context.Context, db *database.Queries, dbConn *sql.DB, z
 		if s.AverageViews != nil {
 			avgViews = sql.NullFloat64{Float64: *s.AverageViews, Valid: true}
 		}
+		if s.Karma != nil {
+			karma = sql.NullInt64{Int64: *s.Karma, Valid: true}
+		}
+		if s.FollowersDelta != nil {
+			followersDelta = sql.NullInt64{Int64: *s.FollowersDelta, Valid: true}
+		}
+		if s.FollowingDelta != nil {
+			followingDelta = sql.NullInt64{Int64: *s.FollowingDelta, Valid: true}
+		}
 		_, err := qtx.CreateSourceStat(ctx, database.CreateSourceStatParams{
 			ID:             remap(s.ID),
 			Date:           date,
@@ -491,6 +538,9 @@ func ImportUserData(ctx context.Context, db *database.Queries, dbConn *sql.DB, z
 			AverageLikes:   avgLikes,
 			AverageReposts: avgReposts,
 			AverageViews:   avgViews,
+			Karma:          karma,
+			FollowersDelta: followersDelta,
+			FollowingDelta: followingDelta,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to import sources stat: %w", err)
@@ -540,6 +590,94 @@ func ImportUserData(ctx context.Context, db *database.Queries, dbConn *sql.DB, z
 		result.SiteStats++
 	}
 
+	for _, s := range streamSessions {
+		startedAt, _ := time.Parse(timeFormat, s.StartedAt)
+		lastSeenAt, _ := time.Parse(timeFormat, s.LastSeenAt)
+		var postID uuid.NullUUID
+		if s.PostID != nil {
+			postID = uuid.NullUUID{UUID: remap(*s.PostID), Valid: true}
+		}
+		var endedAt sql.NullTime
+		if s.EndedAt != nil {
+			t, _ := time.Parse(timeFormat, *s.EndedAt)
+			endedAt = sql.NullTime{Time: t, Valid: true}
+		}
+		err := qtx.BackupCreateStreamSession(ctx, database.BackupCreateStreamSessionParams{
+			ID:             remap(s.ID),
+			SourceID:       remap(s.SourceID),
+			PostID:         postID,
+			StreamID:       s.StreamID,
+			Title:          s.Title,
+			Category:       s.Category,
+			StartedAt:      startedAt,
+			EndedAt:        endedAt,
+			LastSeenAt:     lastSeenAt,
+			PeakViewers:    s.PeakViewers,
+			AverageViewers: s.AverageViewers,
+			SamplesCount:   s.SamplesCount,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to import stream session: %w", err)
+		}
+		result.StreamSessions++
+	}
+
+	for _, s := range streamSamples {
+		sampledAt, _ := time.Parse(timeFormat, s.SampledAt)
+		err := qtx.CreateStreamViewerSample(ctx, database.CreateStreamViewerSampleParams{
+			ID:          remap(s.ID),
+			SessionID:   remap(s.SessionID),
+			SampledAt:   sampledAt,
+			ViewerCount: s.ViewerCount,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to import stream viewer sample: %w", err)
+		}
+		result.StreamSamples++
+	}
+
+	for _, e := range supporterEvents {
+		createdAt, _ := time.Parse(timeFormat, e.CreatedAt)
+		occurredAt, _ := time.Parse(timeFormat, e.OccurredAt)
+		err := qtx.CreateSupporterEvent(ctx, database.CreateSupporterEventParams{
+			ID:             remap(e.ID),
+			CreatedAt:      createdAt,
+			OccurredAt:     occurredAt,
+			SourceID:       remap(e.SourceID),
+			ExternalID:     e.ExternalID,
+			EventType:      e.EventType,
+			FromName:       nullString(e.FromName),
+			FromEmail:      nullString(e.FromEmail),
+			Message:        nullString(e.Message),
+			AmountCents:    e.AmountCents,
+			Currency:       e.Currency,
+			IsPublic:       e.IsPublic,
+			IsSubscription: e.IsSubscription,
+			TierName:       nullString(e.TierName),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to import supporter event: %w", err)
+		}
+		result.SupporterEvents++
+	}
+
+	for _, s := range tierStats {
+		date, _ := time.Parse(timeFormat, s.Date)
+		err := qtx.UpsertSupporterTierStat(ctx, database.UpsertSupporterTierStatParams{
+			ID:              remap(s.ID),
+			Date:            date,
+			SourceID:        remap(s.SourceID),
+			TierID:          s.TierID,
+			TierTitle:       s.TierTitle,
+			AmountCents:     s.AmountCents,
+			SupportersCount: s.SupportersCount,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to import supporter tier stat: %w", err)
+		}
+		result.SupporterTierStats++
+	}
+
 	if err := tx.Commit(); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -592,3 +730,18 @@ func parseJSON(files map[string][]byte, name string, dest any) error {
 	}
 	return json.Unmarshal(data, dest)
 }
+
+// parseOptionalJSON is parseJSON for files that older backups do not have.
+func parseOptionalJSON(files map[string][]byte, name string, dest any) error {
+	if _, ok := files[name]; !ok {
+		return nil
+	}
+	return parseJSON(files, name, dest)
+}
+
+func nullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
@@ -86,6 +86,7 @@ type BackupReaction struct {
 	Likes    *int64 `json:"likes,omitempty"`
 	Reposts  *int64 `json:"reposts,omitempty"`
 	Views    *int64 `json:"views,omitempty"`
+	Comments *int64 `json:"comments,omitempty"`
 }
 
 type BackupTagClassification struct {
@@ -137,6 +138,9 @@ type BackupSourcesStat struct {
 	AverageLikes   *float64 `json:"average_likes,omitempty"`
 	AverageReposts *float64 `json:"average_reposts,omitempty"`
 	AverageViews   *float64 `json:"average_views,omitempty"`
+	Karma          *int64   `json:"karma,omitempty"`
+	FollowersDelta *int64   `json:"followers_delta,omitempty"`
+	FollowingDelta *int64   `json:"following_delta,omitempty"`
 }
 
 type BackupAnalyticsPageStat struct {
@@ -159,6 +163,55 @@ type BackupAnalyticsSiteStat struct {
 	Impressions        *int64  `json:"impressions,omitempty"`
 }
 
+type BackupStreamSession struct {
+	ID             string  `json:"id"`
+	SourceID       string  `json:"source_id"`
+	PostID         *string `json:"post_id,omitempty"`
+	StreamID       string  `json:"stream_id"`
+	Title          string  `json:"title"`
+	Category       string  `json:"category"`
+	StartedAt      string  `json:"started_at"`
+	EndedAt        *string `json:"ended_at,omitempty"`
+	LastSeenAt     string  `json:"last_seen_at"`
+	PeakViewers    int64   `json:"peak_viewers"`
+	AverageViewers float64 `json:"average_viewers"`
+	SamplesCount   int64   `json:"samples_count"`
+}
+
+type BackupStreamViewerSample struct {
+	ID          string `json:"id"`
+	SessionID   string `json:"session_id"`
+	SampledAt   string `json:"sampled_at"`
+	ViewerCount int64  `json:"viewer_count"`
+}
+
+type BackupSupporterEvent struct {
+	ID             string  `json:"id"`
+	CreatedAt      string  `json:"created_at"`
+	OccurredAt     string  `json:"occurred_at"`
+	SourceID       string  `json:"source_id"`
+	ExternalID     string  `json:"external_id"`
+	EventType      string  `json:"event_type"`
+	FromName       *string `json:"from_name,omitempty"`
+	FromEmail      *string `json:"from_email,omitempty"`
+	Message        *string `json:"message,omitempty"`
+	AmountCents    int64   `json:"amount_cents"`
+	Currency       string  `json:"currency"`
+	IsPublic       bool    `json:"is_public"`
+	IsSubscription bool    `json:"is_subscription"`
+	TierName       *string `json:"tier_name,omitempty"`
+}
+
+type BackupSupporterTierStat struct {
+	ID              string `json:"id"`
+	Date            string `json:"date"`
+	SourceID        string `json:"source_id"`
+	TierID          string `json:"tier_id"`
+	TierTitle       string `json:"tier_title"`
+	AmountCents     int64  `json:"amount_cents"`
+	SupportersCount int64  `json:"supporters_count"`
+}
+
 type ImportResult struct {
 	Sources            int    `json:"sources"`
 	Targets            int    `json:"targets"`
@@ -173,5 +226,9 @@ type ImportResult struct {
 	SourcesStats       int    `json:"sources_stats"`
 	PageStats          int    `json:"page_stats"`
 	SiteStats          int    `json:"site_stats"`
+	StreamSessions     int    `json:"stream_sessions"`
+	StreamSamples      int    `json:"stream_samples"`
+	SupporterEvents    int    `json:"supporter_events"`
+	SupporterTierStats int    `json:"supporter_tier_stats"`
 	GeneratedUsername  string `json:"generated_username"`
 }
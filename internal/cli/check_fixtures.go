@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/config"
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/fetcher"
+	fetcher_common "github.com/fluffyriot/rpsync/internal/fetcher/common"
+	"github.com/fluffyriot/rpsync/internal/fetcher/fixtures"
+	"github.com/google/uuid"
+)
+
+// fixtureSource is one entry of a fixture directory's manifest, holding the
+// same fields as the add-source form.
+type fixtureSource struct {
+	Network   string `json:"network"`
+	Username  string `json:"username"`
+	Field1    string `json:"field1"`
+	Field2    string `json:"field2"`
+	Field3    string `json:"field3"`
+	Field4    string `json:"field4"`
+	Field5    string `json:"field5"`
+	FieldLong string `json:"field_long"`
+}
+
+// HandleCheckFixtures syncs every source in the fixture manifest against a
+// stub server holding the fixtures, using a throwaway user that is deleted
+// again afterwards. It exits non-zero if any source fails or saves no posts.
+func HandleCheckFixtures(dbQueries *database.Queries, c *fetcher_common.Client, encryptionKey []byte, dir string) {
+	ctx := context.Background()
+
+	loaded, err := fixtures.Load(dir)
+	if err != nil {
+		log.Fatalf("Failed to load fixtures from %s: %v", dir, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, fixtures.ManifestFile))
+	if err != nil {
+		log.Fatalf("Failed to read fixture manifest: %v", err)
+	}
+	var manifest []fixtureSource
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		log.Fatalf("Invalid fixture manifest: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		log.Fatalf("Failed to start fixture server: %v", err)
+	}
+	go http.Serve(listener, fixtures.NewServer(loaded)) //nolint:errcheck
+	stubURL := "http://" + listener.Addr().String()
+
+	user, err := dbQueries.CreateUser(ctx, database.CreateUserParams{
+		ID:         uuid.New(),
+		Username:   "fixtures-" + uuid.NewString()[:8],
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+		SyncPeriod: "30m",
+	})
+	if err != nil {
+		log.Fatalf("Failed to create fixture user: %v", err)
+	}
+
+	failed := 0
+	for _, s := range manifest {
+		count, err := checkFixtureSource(ctx, dbQueries, c, encryptionKey, user.ID, stubURL, s)
+		if err == nil && count == 0 {
+			err = fmt.Errorf("no posts saved")
+		}
+		if err != nil {
+			failed++
+			fmt.Printf("FAIL %-12s %s: %v\n", s.Network, s.Username, err)
+			continue
+		}
+		fmt.Printf("ok   %-12s %s: %d posts\n", s.Network, s.Username, count)
+	}
+
+	if err := dbQueries.DeleteUserByID(ctx, user.ID); err != nil {
+		log.Printf("Failed to delete fixture user %s: %v", user.Username, err)
+	}
+
+	if failed > 0 {
+		log.Fatalf("%d of %d fixture sources failed", failed, len(manifest))
+	}
+	fmt.Printf("All %d fixture sources synced\n", len(manifest))
+}
+
+func checkFixtureSource(ctx context.Context, dbQueries *database.Queries, c *fetcher_common.Client, encryptionKey []byte, userID uuid.UUID, stubURL string, s fixtureSource) (int, error) {
+	id, _, err := config.CreateSourceFromForm(dbQueries, config.SourceCreationParams{
+		UserID:        userID.String(),
+		Network:       s.Network,
+		Username:      s.Username,
+		Field1:        s.Field1,
+		Field2:        s.Field2,
+		Field3:        s.Field3,
+		Field4:        s.Field4,
+		Field5:        s.Field5,
+		FieldLong:     s.FieldLong,
+		EncryptionKey: encryptionKey,
+	})
+	if err != nil {
+		return 0, err
+	}
+	sourceID, err := uuid.Parse(id)
+	if err != nil {
+		return 0, err
+	}
+
+	bases := fetcher_common.SourceBaseURLs(s.Network, s.Username)
+	if len(bases) == 0 {
+		return 0, fmt.Errorf("endpoint overrides are not supported for %s", s.Network)
+	}
+	for _, base := range bases {
+		u, err := url.Parse(base)
+		if err != nil {
+			return 0, err
+		}
+		if err := dbQueries.CreateSourceEndpoint(ctx, database.CreateSourceEndpointParams{
+			ID:             uuid.New(),
+			SourceID:       sourceID,
+			DefaultBaseUrl: base,
+			BaseUrl:        stubURL + "/" + u.Host,
+		}); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := fetcher.SyncBySource(sourceID, dbQueries, c, config.AppVersion, encryptionKey, true); err != nil {
+		return 0, err
+	}
+
+	if slices.Contains(fetcher.LiveNetworks, s.Network) {
+		source, err := dbQueries.GetSourceById(ctx, sourceID)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := fetcher.PollLiveBySource(source, dbQueries, c, encryptionKey); err != nil {
+			return 0, fmt.Errorf("live poll: %w", err)
+		}
+	}
+
+	posts, err := dbQueries.GetNetworkIdsAndContentBySource(ctx, sourceID)
+	if err != nil {
+		return 0, err
+	}
+	return len(posts), nil
+}
@@ -6,10 +6,12 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"net/http"
 	"syscall"
 
 	"github.com/fluffyriot/rpsync/internal/authhelp"
 	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/fetcher/fixtures"
 	"golang.org/x/term"
 )
 
@@ -78,3 +80,20 @@ func HandleReset2FA(dbQueries *database.Queries, username string) {
 
 	fmt.Printf("2FA successfully disabled for user '%s'\n", username)
 }
+
+func HandleServeFixtures(dir, addr string) {
+	loaded, err := fixtures.Load(dir)
+	if err != nil {
+		log.Fatalf("Failed to load fixtures from %s: %v", dir, err)
+	}
+	if len(loaded) == 0 {
+		log.Fatalf("No fixtures found in %s", dir)
+	}
+
+	fmt.Printf("Serving %d fixtures from %s on %s\n", len(loaded), dir, addr)
+	fmt.Printf("Point a source's API endpoint at http://%s/<host>, e.g. http://%s/www.weasyl.com\n", addr, addr)
+
+	if err := http.ListenAndServe(addr, fixtures.NewServer(loaded)); err != nil {
+		log.Fatalf("Fixture server failed: %v", err)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -22,15 +23,6 @@ import (
 	_ "github.com/lib/pq"
 )
 
-type SyncMethodEnum string
-
-const (
-	Dev    SyncMethodEnum = "None / Dev"
-	Csv    SyncMethodEnum = "CSV"
-	Notion SyncMethodEnum = "Notion"
-	NocoDb SyncMethodEnum = "NocoDb"
-)
-
 var AppVersion string = "unknown"
 
 type User struct {
@@ -55,6 +47,7 @@ type AppConfig struct {
 	SessionKey          []byte
 	WebAuthn            *webauthn.WebAuthn
 	GinMode             string
+	BrowserPoolSize     int
 }
 
 func LoadConfig() (*AppConfig, error) {
@@ -128,6 +121,15 @@ func LoadConfig() (*AppConfig, error) {
 		cfg.GinMode = "release"
 	}
 
+	cfg.BrowserPoolSize = 2
+	if v := os.Getenv("BROWSER_POOL_SIZE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("BROWSER_POOL_SIZE must be a positive number, got %q", v)
+		}
+		cfg.BrowserPoolSize = n
+	}
+
 	return cfg, nil
 }
 
@@ -236,6 +238,10 @@ func CreateSourceFromForm(dbQueries *database.Queries, params SourceCreationPara
 		return "", "", fmt.Errorf("API Key and API Username are required for e621")
 	}
 
+	if params.Network == "Reddit" && (params.Field2 != "" || params.Field3 != "" || params.Field4 != "") && (params.Field2 == "" || params.Field3 == "" || params.Field4 == "") {
+		return "", "", fmt.Errorf("Client ID, Client Secret and Password are all required for Reddit OAuth mode")
+	}
+
 	if params.Network == "Twitch" && (params.Field1 == "" || params.Field2 == "") {
 		return "", "", fmt.Errorf("Client ID and Client Secret are required for Twitch")
 	}
@@ -256,6 +262,14 @@ func CreateSourceFromForm(dbQueries *database.Queries, params SourceCreationPara
 		return "", "", fmt.Errorf("Access Token is required for Threads")
 	}
 
+	if params.Network == "Patreon" && params.Field1 == "" {
+		return "", "", fmt.Errorf("Creator Access Token is required for Patreon")
+	}
+
+	if params.Network == "Ko-fi" && params.Field1 == "" {
+		return "", "", fmt.Errorf("Verification Token is required for Ko-fi")
+	}
+
 	if params.Network == "Murrtube" && params.FieldLong == "" {
 		return "", "", fmt.Errorf("Cookie JSON is required for Murrtube")
 	}
@@ -340,8 +354,21 @@ func CreateSourceFromForm(dbQueries *database.Queries, params SourceCreationPara
 	case "e621":
 		err = authhelp.InsertSourceToken(context.Background(), dbQueries, s.ID, params.Field2, params.Field1, nil, params.EncryptionKey)
 
+	case "Bluesky":
+		if params.Field1 != "" || params.Field2 != "" || params.Field3 != "" {
+			tokenFormatted = "public"
+			if params.Field1 != "" {
+				tokenFormatted = params.Field1
+			}
+			err = authhelp.InsertSourceToken(context.Background(), dbQueries, s.ID, tokenFormatted, params.Field2, map[string]any{"include": params.Field3}, params.EncryptionKey)
+		}
+
 	case "Reddit":
-		err = authhelp.InsertSourceToken(context.Background(), dbQueries, s.ID, "public", params.Field1, nil, params.EncryptionKey)
+		tokenFormatted = "public"
+		if params.Field2 != "" {
+			tokenFormatted = params.Field2 + ":::" + params.Field3 + ":::" + params.Field4
+		}
+		err = authhelp.InsertSourceToken(context.Background(), dbQueries, s.ID, tokenFormatted, params.Field1, nil, params.EncryptionKey)
 
 	case "Twitch":
 		err = authhelp.InsertSourceToken(context.Background(), dbQueries, s.ID, params.Field2, params.Field1, nil, params.EncryptionKey)
@@ -358,6 +385,12 @@ func CreateSourceFromForm(dbQueries *database.Queries, params SourceCreationPara
 	case "Threads":
 		err = authhelp.InsertSourceToken(context.Background(), dbQueries, s.ID, params.Field1, "", nil, params.EncryptionKey)
 
+	case "Patreon":
+		err = authhelp.InsertSourceToken(context.Background(), dbQueries, s.ID, params.Field1, "", nil, params.EncryptionKey)
+
+	case "Ko-fi":
+		err = authhelp.InsertSourceToken(context.Background(), dbQueries, s.ID, params.Field1, "", nil, params.EncryptionKey)
+
 	case "Murrtube":
 		err = authhelp.InsertSourceToken(context.Background(), dbQueries, s.ID, params.FieldLong, "", nil, params.EncryptionKey)
 
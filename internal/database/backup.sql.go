@@ -13,6 +13,47 @@ import (
 	"github.com/google/uuid"
 )
 
+const backupCreateStreamSession = `-- name: BackupCreateStreamSession :exec
+INSERT INTO stream_sessions (
+    id, source_id, post_id, stream_id, title, category, started_at,
+    ended_at, last_seen_at, peak_viewers, average_viewers, samples_count
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+`
+
+type BackupCreateStreamSessionParams struct {
+	ID             uuid.UUID     `json:"id"`
+	SourceID       uuid.UUID     `json:"source_id"`
+	PostID         uuid.NullUUID `json:"post_id"`
+	StreamID       string        `json:"stream_id"`
+	Title          string        `json:"title"`
+	Category       string        `json:"category"`
+	StartedAt      time.Time     `json:"started_at"`
+	EndedAt        sql.NullTime  `json:"ended_at"`
+	LastSeenAt     time.Time     `json:"last_seen_at"`
+	PeakViewers    int64         `json:"peak_viewers"`
+	AverageViewers float64       `json:"average_viewers"`
+	SamplesCount   int64         `json:"samples_count"`
+}
+
+func (q *Queries) BackupCreateStreamSession(ctx context.Context, arg BackupCreateStreamSessionParams) error {
+	_, err := q.db.ExecContext(ctx, backupCreateStreamSession,
+		arg.ID,
+		arg.SourceID,
+		arg.PostID,
+		arg.StreamID,
+		arg.Title,
+		arg.Category,
+		arg.StartedAt,
+		arg.EndedAt,
+		arg.LastSeenAt,
+		arg.PeakViewers,
+		arg.AverageViewers,
+		arg.SamplesCount,
+	)
+	return err
+}
+
 const backupDeleteAnalyticsPageStatsForUser = `-- name: BackupDeleteAnalyticsPageStatsForUser :exec
 DELETE FROM analytics_page_stats WHERE id IN (
     SELECT aps.id FROM analytics_page_stats aps
@@ -453,7 +494,7 @@ func (q *Queries) BackupGetPostsForUser(ctx context.Context, userID uuid.UUID) (
 }
 
 const backupGetReactionsForUser = `-- name: BackupGetReactionsForUser :many
-SELECT prh.id, prh.synced_at, prh.post_id, prh.likes, prh.reposts, prh.views FROM posts_reactions_history prh
+SELECT prh.id, prh.synced_at, prh.post_id, prh.likes, prh.reposts, prh.views, prh.comments FROM posts_reactions_history prh
 JOIN posts p ON prh.post_id = p.id
 JOIN sources s ON p.source_id = s.id
 WHERE s.user_id = $1
@@ -476,6 +517,7 @@ func (q *Queries) BackupGetReactionsForUser(ctx context.Context, userID uuid.UUI
 			&i.Likes,
 			&i.Reposts,
 			&i.Views,
+			&i.Comments,
 		); err != nil {
 			return nil, err
 		}
@@ -564,7 +606,7 @@ func (q *Queries) BackupGetSourcesForUser(ctx context.Context, userID uuid.UUID)
 }
 
 const backupGetSourcesStatsForUser = `-- name: BackupGetSourcesStatsForUser :many
-SELECT ss.id, ss.date, ss.source_id, ss.followers_count, ss.following_count, ss.posts_count, ss.average_likes, ss.average_reposts, ss.average_views FROM sources_stats ss
+SELECT ss.id, ss.date, ss.source_id, ss.followers_count, ss.following_count, ss.posts_count, ss.average_likes, ss.average_reposts, ss.average_views, ss.karma, ss.followers_delta, ss.following_delta FROM sources_stats ss
 JOIN sources s ON ss.source_id = s.id
 WHERE s.user_id = $1
 ORDER BY ss.date
@@ -589,6 +631,171 @@ func (q *Queries) BackupGetSourcesStatsForUser(ctx context.Context, userID uuid.
 			&i.AverageLikes,
 			&i.AverageReposts,
 			&i.AverageViews,
+			&i.Karma,
+			&i.FollowersDelta,
+			&i.FollowingDelta,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const backupGetStreamSessionsForUser = `-- name: BackupGetStreamSessionsForUser :many
+SELECT ss.id, ss.source_id, ss.post_id, ss.stream_id, ss.title, ss.category, ss.started_at, ss.ended_at, ss.last_seen_at, ss.peak_viewers, ss.average_viewers, ss.samples_count FROM stream_sessions ss
+JOIN sources s ON ss.source_id = s.id
+WHERE s.user_id = $1
+ORDER BY ss.started_at
+`
+
+func (q *Queries) BackupGetStreamSessionsForUser(ctx context.Context, userID uuid.UUID) ([]StreamSession, error) {
+	rows, err := q.db.QueryContext(ctx, backupGetStreamSessionsForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []StreamSession
+	for rows.Next() {
+		var i StreamSession
+		if err := rows.Scan(
+			&i.ID,
+			&i.SourceID,
+			&i.PostID,
+			&i.StreamID,
+			&i.Title,
+			&i.Category,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.LastSeenAt,
+			&i.PeakViewers,
+			&i.AverageViewers,
+			&i.SamplesCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const backupGetStreamViewerSamplesForUser = `-- name: BackupGetStreamViewerSamplesForUser :many
+SELECT svs.id, svs.session_id, svs.sampled_at, svs.viewer_count FROM stream_viewer_samples svs
+JOIN stream_sessions ss ON svs.session_id = ss.id
+JOIN sources s ON ss.source_id = s.id
+WHERE s.user_id = $1
+ORDER BY svs.sampled_at
+`
+
+func (q *Queries) BackupGetStreamViewerSamplesForUser(ctx context.Context, userID uuid.UUID) ([]StreamViewerSample, error) {
+	rows, err := q.db.QueryContext(ctx, backupGetStreamViewerSamplesForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []StreamViewerSample
+	for rows.Next() {
+		var i StreamViewerSample
+		if err := rows.Scan(
+			&i.ID,
+			&i.SessionID,
+			&i.SampledAt,
+			&i.ViewerCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const backupGetSupporterEventsForUser = `-- name: BackupGetSupporterEventsForUser :many
+SELECT se.id, se.created_at, se.occurred_at, se.source_id, se.external_id, se.event_type, se.from_name, se.from_email, se.message, se.amount_cents, se.currency, se.is_public, se.is_subscription, se.tier_name FROM supporter_events se
+JOIN sources s ON se.source_id = s.id
+WHERE s.user_id = $1
+ORDER BY se.occurred_at
+`
+
+func (q *Queries) BackupGetSupporterEventsForUser(ctx context.Context, userID uuid.UUID) ([]SupporterEvent, error) {
+	rows, err := q.db.QueryContext(ctx, backupGetSupporterEventsForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SupporterEvent
+	for rows.Next() {
+		var i SupporterEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.OccurredAt,
+			&i.SourceID,
+			&i.ExternalID,
+			&i.EventType,
+			&i.FromName,
+			&i.FromEmail,
+			&i.Message,
+			&i.AmountCents,
+			&i.Currency,
+			&i.IsPublic,
+			&i.IsSubscription,
+			&i.TierName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const backupGetSupporterTierStatsForUser = `-- name: BackupGetSupporterTierStatsForUser :many
+SELECT sts.id, sts.date, sts.source_id, sts.tier_id, sts.tier_title, sts.amount_cents, sts.supporters_count FROM supporter_tier_stats sts
+JOIN sources s ON sts.source_id = s.id
+WHERE s.user_id = $1
+ORDER BY sts.date
+`
+
+func (q *Queries) BackupGetSupporterTierStatsForUser(ctx context.Context, userID uuid.UUID) ([]SupporterTierStat, error) {
+	rows, err := q.db.QueryContext(ctx, backupGetSupporterTierStatsForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SupporterTierStat
+	for rows.Next() {
+		var i SupporterTierStat
+		if err := rows.Scan(
+			&i.ID,
+			&i.Date,
+			&i.SourceID,
+			&i.TierID,
+			&i.TierTitle,
+			&i.AmountCents,
+			&i.SupportersCount,
 		); err != nil {
 			return nil, err
 		}
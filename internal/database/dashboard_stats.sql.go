@@ -188,6 +188,7 @@ SELECT calendar.date::date as period_date,
                     FROM sources_stats ss
                         JOIN sources s ON ss.source_id = s.id
                     WHERE s.user_id = $1
+                        AND s.network NOT IN ('Patreon', 'Ko-fi')
                         AND ss.date >= $2 - INTERVAL '1 day'
                         AND ss.date < calendar.date + INTERVAL '1 day'
                     ORDER BY ss.source_id,
@@ -238,6 +239,67 @@ func (q *Queries) GetTotalDailyFollowerStats(ctx context.Context, arg GetTotalDa
 	return items, nil
 }
 
+const getTotalDailySupporterStats = `-- name: GetTotalDailySupporterStats :many
+SELECT calendar.date::date as period_date,
+    COALESCE(
+        (
+            SELECT SUM(COALESCE(followers_count, 0))
+            FROM (
+                    SELECT DISTINCT ON (ss.source_id) ss.followers_count
+                    FROM sources_stats ss
+                        JOIN sources s ON ss.source_id = s.id
+                    WHERE s.user_id = $1
+                        AND s.network IN ('Patreon', 'Ko-fi')
+                        AND ss.date >= $2 - INTERVAL '1 day'
+                        AND ss.date < calendar.date + INTERVAL '1 day'
+                    ORDER BY ss.source_id,
+                        ss.date DESC
+                ) as distinct_sources
+        ),
+        0
+    )::BIGINT as total_supporters
+FROM generate_series(
+        date_trunc('day', $2::timestamp),
+        date_trunc('day', $3::timestamp),
+        '1 day'::interval
+    ) as calendar (date)
+ORDER BY calendar.date ASC
+`
+
+type GetTotalDailySupporterStatsParams struct {
+	UserID  uuid.UUID   `json:"user_id"`
+	Column2 interface{} `json:"column_2"`
+	Column3 time.Time   `json:"column_3"`
+}
+
+type GetTotalDailySupporterStatsRow struct {
+	PeriodDate      time.Time `json:"period_date"`
+	TotalSupporters int64     `json:"total_supporters"`
+}
+
+func (q *Queries) GetTotalDailySupporterStats(ctx context.Context, arg GetTotalDailySupporterStatsParams) ([]GetTotalDailySupporterStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTotalDailySupporterStats, arg.UserID, arg.Column2, arg.Column3)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTotalDailySupporterStatsRow
+	for rows.Next() {
+		var i GetTotalDailySupporterStatsRow
+		if err := rows.Scan(&i.PeriodDate, &i.TotalSupporters); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTotalPostsCount = `-- name: GetTotalPostsCount :one
 SELECT COUNT(*)
 FROM posts
@@ -133,6 +133,15 @@ func (q *Queries) DeleteAllExportsByUserId(ctx context.Context, userID uuid.UUID
 	return err
 }
 
+const deleteExportById = `-- name: DeleteExportById :exec
+DELETE FROM exports WHERE id = $1
+`
+
+func (q *Queries) DeleteExportById(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteExportById, id)
+	return err
+}
+
 const getAllExportsByUserId = `-- name: GetAllExportsByUserId :many
 SELECT id, created_at, completed_at, export_status, status_message, user_id, download_url, export_method, target_id FROM exports where user_id = $1 ORDER BY created_at DESC
 `
@@ -191,6 +200,55 @@ func (q *Queries) GetExportById(ctx context.Context, id uuid.UUID) (Export, erro
 	return i, err
 }
 
+const getExportsWithDownloadPrefixBefore = `-- name: GetExportsWithDownloadPrefixBefore :many
+SELECT id, created_at, completed_at, export_status, status_message, user_id, download_url, export_method, target_id
+FROM exports
+WHERE
+    user_id = $1
+    AND created_at < $2
+    AND starts_with(download_url, $3::text)
+ORDER BY created_at
+`
+
+type GetExportsWithDownloadPrefixBeforeParams struct {
+	UserID    uuid.UUID `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UrlPrefix string    `json:"url_prefix"`
+}
+
+func (q *Queries) GetExportsWithDownloadPrefixBefore(ctx context.Context, arg GetExportsWithDownloadPrefixBeforeParams) ([]Export, error) {
+	rows, err := q.db.QueryContext(ctx, getExportsWithDownloadPrefixBefore, arg.UserID, arg.CreatedAt, arg.UrlPrefix)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Export
+	for rows.Next() {
+		var i Export
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.CompletedAt,
+			&i.ExportStatus,
+			&i.StatusMessage,
+			&i.UserID,
+			&i.DownloadUrl,
+			&i.ExportMethod,
+			&i.TargetID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getLast20ExportsByUserId = `-- name: GetLast20ExportsByUserId :many
 SELECT id, created_at, completed_at, export_status, status_message, user_id, download_url, export_method, target_id
 FROM exports
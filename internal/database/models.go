@@ -89,6 +89,63 @@ type Export struct {
 	TargetID      uuid.NullUUID  `json:"target_id"`
 }
 
+type ExportStorage struct {
+	UserID          uuid.UUID `json:"user_id"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	StorageType     string    `json:"storage_type"`
+	Endpoint        string    `json:"endpoint"`
+	Bucket          string    `json:"bucket"`
+	Region          string    `json:"region"`
+	PathPrefix      string    `json:"path_prefix"`
+	AccessKey       string    `json:"access_key"`
+	EncryptedSecret []byte    `json:"encrypted_secret"`
+	Nonce           []byte    `json:"nonce"`
+	RetentionDays   int32     `json:"retention_days"`
+	KeepLocal       bool      `json:"keep_local"`
+}
+
+type FileTargetSetting struct {
+	TargetID   uuid.UUID       `json:"target_id"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+	Columns    json.RawMessage `json:"columns"`
+	ZipArchive bool            `json:"zip_archive"`
+	Formats    json.RawMessage `json:"formats"`
+}
+
+type InstagramAccountInsight struct {
+	ID                   uuid.UUID       `json:"id"`
+	Date                 time.Time       `json:"date"`
+	SourceID             uuid.UUID       `json:"source_id"`
+	UpdatedAt            time.Time       `json:"updated_at"`
+	Reach                int64           `json:"reach"`
+	ProfileViews         int64           `json:"profile_views"`
+	WebsiteClicks        int64           `json:"website_clicks"`
+	AccountsEngaged      int64           `json:"accounts_engaged"`
+	FollowerDemographics json.RawMessage `json:"follower_demographics"`
+}
+
+type InstagramOnlineFollower struct {
+	ID        uuid.UUID `json:"id"`
+	Date      time.Time `json:"date"`
+	SourceID  uuid.UUID `json:"source_id"`
+	HourOfDay int32     `json:"hour_of_day"`
+	Followers int64     `json:"followers"`
+}
+
+type InstagramStorySnapshot struct {
+	ID                uuid.UUID `json:"id"`
+	SyncedAt          time.Time `json:"synced_at"`
+	PostID            uuid.UUID `json:"post_id"`
+	Reach             int64     `json:"reach"`
+	Views             int64     `json:"views"`
+	Replies           int64     `json:"replies"`
+	Shares            int64     `json:"shares"`
+	TotalInteractions int64     `json:"total_interactions"`
+	TapsForward       int64     `json:"taps_forward"`
+	TapsBack          int64     `json:"taps_back"`
+	Exits             int64     `json:"exits"`
+}
+
 type Log struct {
 	ID          uuid.UUID     `json:"id"`
 	CreatedAt   time.Time     `json:"created_at"`
@@ -110,6 +167,20 @@ type Post struct {
 	Content           sql.NullString `json:"content"`
 }
 
+type PostImport struct {
+	PostID        uuid.UUID `json:"post_id"`
+	ImportedAt    time.Time `json:"imported_at"`
+	ArchiveFormat string    `json:"archive_format"`
+}
+
+type PostReactionBreakdown struct {
+	ID          uuid.UUID `json:"id"`
+	SyncedAt    time.Time `json:"synced_at"`
+	PostID      uuid.UUID `json:"post_id"`
+	ReactionKey string    `json:"reaction_key"`
+	Count       int64     `json:"count"`
+}
+
 type PostTag struct {
 	ID        uuid.UUID `json:"id"`
 	CreatedAt time.Time `json:"created_at"`
@@ -132,6 +203,15 @@ type PostsReactionsHistory struct {
 	Likes    sql.NullInt64 `json:"likes"`
 	Reposts  sql.NullInt64 `json:"reposts"`
 	Views    sql.NullInt64 `json:"views"`
+	Comments sql.NullInt64 `json:"comments"`
+}
+
+type RedditPostDetail struct {
+	PostID        uuid.UUID `json:"post_id"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	Subreddit     string    `json:"subreddit"`
+	UpvoteRatio   float64   `json:"upvote_ratio"`
+	NumCrossposts int64     `json:"num_crossposts"`
 }
 
 type Redirect struct {
@@ -155,6 +235,21 @@ type Source struct {
 	LastSynced   sql.NullTime   `json:"last_synced"`
 }
 
+type SourceEndpoint struct {
+	ID             uuid.UUID `json:"id"`
+	SourceID       uuid.UUID `json:"source_id"`
+	DefaultBaseUrl string    `json:"default_base_url"`
+	BaseUrl        string    `json:"base_url"`
+}
+
+type SourceNetworkSetting struct {
+	SourceID          uuid.UUID `json:"source_id"`
+	UpdatedAt         time.Time `json:"updated_at"`
+	UserAgent         string    `json:"user_agent"`
+	EncryptedSettings []byte    `json:"encrypted_settings"`
+	Nonce             []byte    `json:"nonce"`
+}
+
 type SourcesOnTarget struct {
 	ID             uuid.UUID `json:"id"`
 	SourceID       uuid.UUID `json:"source_id"`
@@ -172,6 +267,9 @@ type SourcesStat struct {
 	AverageLikes   sql.NullFloat64 `json:"average_likes"`
 	AverageReposts sql.NullFloat64 `json:"average_reposts"`
 	AverageViews   sql.NullFloat64 `json:"average_views"`
+	Karma          sql.NullInt64   `json:"karma"`
+	FollowersDelta sql.NullInt64   `json:"followers_delta"`
+	FollowingDelta sql.NullInt64   `json:"following_delta"`
 }
 
 type SourcesStatsOnTarget struct {
@@ -182,6 +280,55 @@ type SourcesStatsOnTarget struct {
 	TargetRecordID string    `json:"target_record_id"`
 }
 
+type StreamSession struct {
+	ID             uuid.UUID     `json:"id"`
+	SourceID       uuid.UUID     `json:"source_id"`
+	PostID         uuid.NullUUID `json:"post_id"`
+	StreamID       string        `json:"stream_id"`
+	Title          string        `json:"title"`
+	Category       string        `json:"category"`
+	StartedAt      time.Time     `json:"started_at"`
+	EndedAt        sql.NullTime  `json:"ended_at"`
+	LastSeenAt     time.Time     `json:"last_seen_at"`
+	PeakViewers    int64         `json:"peak_viewers"`
+	AverageViewers float64       `json:"average_viewers"`
+	SamplesCount   int64         `json:"samples_count"`
+}
+
+type StreamViewerSample struct {
+	ID          uuid.UUID `json:"id"`
+	SessionID   uuid.UUID `json:"session_id"`
+	SampledAt   time.Time `json:"sampled_at"`
+	ViewerCount int64     `json:"viewer_count"`
+}
+
+type SupporterEvent struct {
+	ID             uuid.UUID      `json:"id"`
+	CreatedAt      time.Time      `json:"created_at"`
+	OccurredAt     time.Time      `json:"occurred_at"`
+	SourceID       uuid.UUID      `json:"source_id"`
+	ExternalID     string         `json:"external_id"`
+	EventType      string         `json:"event_type"`
+	FromName       sql.NullString `json:"from_name"`
+	FromEmail      sql.NullString `json:"from_email"`
+	Message        sql.NullString `json:"message"`
+	AmountCents    int64          `json:"amount_cents"`
+	Currency       string         `json:"currency"`
+	IsPublic       bool           `json:"is_public"`
+	IsSubscription bool           `json:"is_subscription"`
+	TierName       sql.NullString `json:"tier_name"`
+}
+
+type SupporterTierStat struct {
+	ID              uuid.UUID `json:"id"`
+	Date            time.Time `json:"date"`
+	SourceID        uuid.UUID `json:"source_id"`
+	TierID          string    `json:"tier_id"`
+	TierTitle       string    `json:"tier_title"`
+	AmountCents     int64     `json:"amount_cents"`
+	SupportersCount int64     `json:"supporters_count"`
+}
+
 type TableMapping struct {
 	ID              uuid.UUID      `json:"id"`
 	CreatedAt       time.Time      `json:"created_at"`
@@ -223,6 +370,13 @@ type Target struct {
 	HostUrl       sql.NullString `json:"host_url"`
 }
 
+type TimeseriesWatermark struct {
+	TargetID       uuid.UUID `json:"target_id"`
+	SourceID       uuid.UUID `json:"source_id"`
+	Series         string    `json:"series"`
+	LastExportedAt time.Time `json:"last_exported_at"`
+}
+
 type Token struct {
 	ID                   uuid.UUID       `json:"id"`
 	EncryptedAccessToken []byte          `json:"encrypted_access_token"`
@@ -260,3 +414,46 @@ type WebauthnCredential struct {
 	CreatedAt       time.Time     `json:"created_at"`
 	UpdatedAt       time.Time     `json:"updated_at"`
 }
+
+type WebhookDelivery struct {
+	ID           uuid.UUID       `json:"id"`
+	TargetID     uuid.UUID       `json:"target_id"`
+	CreatedAt    time.Time       `json:"created_at"`
+	EventType    string          `json:"event_type"`
+	Payload      json.RawMessage `json:"payload"`
+	Status       string          `json:"status"`
+	Attempts     int32           `json:"attempts"`
+	ResponseCode sql.NullInt32   `json:"response_code"`
+	Error        sql.NullString  `json:"error"`
+}
+
+type WebhookMilestone struct {
+	TargetID  uuid.UUID `json:"target_id"`
+	SubjectID uuid.UUID `json:"subject_id"`
+	Metric    string    `json:"metric"`
+	Milestone int64     `json:"milestone"`
+}
+
+type YoutubeChannelDailyStat struct {
+	ID                      uuid.UUID `json:"id"`
+	Date                    time.Time `json:"date"`
+	SourceID                uuid.UUID `json:"source_id"`
+	Views                   int64     `json:"views"`
+	EstimatedMinutesWatched int64     `json:"estimated_minutes_watched"`
+	AverageViewDuration     float64   `json:"average_view_duration"`
+	SubscribersGained       int64     `json:"subscribers_gained"`
+	SubscribersLost         int64     `json:"subscribers_lost"`
+	ShortsViews             int64     `json:"shorts_views"`
+	LongFormViews           int64     `json:"long_form_views"`
+}
+
+type YoutubeVideoDailyStat struct {
+	ID                      uuid.UUID `json:"id"`
+	Date                    time.Time `json:"date"`
+	PostID                  uuid.UUID `json:"post_id"`
+	Views                   int64     `json:"views"`
+	EstimatedMinutesWatched int64     `json:"estimated_minutes_watched"`
+	AverageViewDuration     float64   `json:"average_view_duration"`
+	SubscribersGained       int64     `json:"subscribers_gained"`
+	SubscribersLost         int64     `json:"subscribers_lost"`
+}
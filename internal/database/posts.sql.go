@@ -20,6 +20,14 @@ SET
 WHERE
     source_id = $1
     AND last_synced_at < $2
+    AND post_type <> 'story'
+    AND NOT EXISTS (
+        SELECT 1
+        FROM post_imports pi
+        WHERE
+            pi.post_id = posts.id
+            AND posts.last_synced_at <= pi.imported_at
+    )
 `
 
 type ArchiveUnsyncedPostsParams struct {
@@ -387,6 +395,26 @@ func (q *Queries) GetRecentPostsForUser(ctx context.Context, userID uuid.UUID) (
 	return items, nil
 }
 
+const renamePostAuthor = `-- name: RenamePostAuthor :exec
+UPDATE posts
+SET
+    author = $1
+WHERE
+    source_id = $2
+    AND author = $3
+`
+
+type RenamePostAuthorParams struct {
+	NewAuthor string    `json:"new_author"`
+	SourceID  uuid.UUID `json:"source_id"`
+	OldAuthor string    `json:"old_author"`
+}
+
+func (q *Queries) RenamePostAuthor(ctx context.Context, arg RenamePostAuthorParams) error {
+	_, err := q.db.ExecContext(ctx, renamePostAuthor, arg.NewAuthor, arg.SourceID, arg.OldAuthor)
+	return err
+}
+
 const updatePost = `-- name: UpdatePost :one
 UPDATE posts
 SET
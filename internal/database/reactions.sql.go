@@ -94,6 +94,129 @@ func (q *Queries) GetDailyEngagementStats(ctx context.Context, userID uuid.UUID)
 	return items, nil
 }
 
+const getReactionsForSourceSince = `-- name: GetReactionsForSourceSince :many
+SELECT prh.id, prh.synced_at, prh.post_id, prh.likes, prh.reposts, prh.views, prh.comments, p.post_type
+FROM posts_reactions_history prh
+JOIN posts p ON prh.post_id = p.id
+WHERE p.source_id = $1 AND prh.synced_at > $2
+ORDER BY prh.synced_at
+`
+
+type GetReactionsForSourceSinceParams struct {
+	SourceID uuid.UUID `json:"source_id"`
+	SyncedAt time.Time `json:"synced_at"`
+}
+
+type GetReactionsForSourceSinceRow struct {
+	ID       uuid.UUID     `json:"id"`
+	SyncedAt time.Time     `json:"synced_at"`
+	PostID   uuid.UUID     `json:"post_id"`
+	Likes    sql.NullInt64 `json:"likes"`
+	Reposts  sql.NullInt64 `json:"reposts"`
+	Views    sql.NullInt64 `json:"views"`
+	Comments sql.NullInt64 `json:"comments"`
+	PostType string        `json:"post_type"`
+}
+
+func (q *Queries) GetReactionsForSourceSince(ctx context.Context, arg GetReactionsForSourceSinceParams) ([]GetReactionsForSourceSinceRow, error) {
+	rows, err := q.db.QueryContext(ctx, getReactionsForSourceSince, arg.SourceID, arg.SyncedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetReactionsForSourceSinceRow
+	for rows.Next() {
+		var i GetReactionsForSourceSinceRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SyncedAt,
+			&i.PostID,
+			&i.Likes,
+			&i.Reposts,
+			&i.Views,
+			&i.Comments,
+			&i.PostType,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getReactionsForUserSince = `-- name: GetReactionsForUserSince :many
+SELECT prh.id, prh.synced_at, prh.post_id, prh.likes, prh.reposts, prh.views, prh.comments FROM posts_reactions_history prh
+JOIN posts p ON prh.post_id = p.id
+JOIN sources s ON p.source_id = s.id
+WHERE s.user_id = $1 AND prh.synced_at >= $2
+ORDER BY prh.synced_at
+`
+
+type GetReactionsForUserSinceParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	SyncedAt time.Time `json:"synced_at"`
+}
+
+func (q *Queries) GetReactionsForUserSince(ctx context.Context, arg GetReactionsForUserSinceParams) ([]PostsReactionsHistory, error) {
+	rows, err := q.db.QueryContext(ctx, getReactionsForUserSince, arg.UserID, arg.SyncedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PostsReactionsHistory
+	for rows.Next() {
+		var i PostsReactionsHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.SyncedAt,
+			&i.PostID,
+			&i.Likes,
+			&i.Reposts,
+			&i.Views,
+			&i.Comments,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSourceReactionMaxima = `-- name: GetSourceReactionMaxima :one
+SELECT
+    COALESCE(MAX(prh.likes), 0)::BIGINT AS max_likes,
+    COALESCE(MAX(prh.views), 0)::BIGINT AS max_views
+FROM
+    posts_reactions_history prh
+    JOIN posts p ON prh.post_id = p.id
+WHERE
+    p.source_id = $1
+`
+
+type GetSourceReactionMaximaRow struct {
+	MaxLikes int64 `json:"max_likes"`
+	MaxViews int64 `json:"max_views"`
+}
+
+func (q *Queries) GetSourceReactionMaxima(ctx context.Context, sourceID uuid.UUID) (GetSourceReactionMaximaRow, error) {
+	row := q.db.QueryRowContext(ctx, getSourceReactionMaxima, sourceID)
+	var i GetSourceReactionMaximaRow
+	err := row.Scan(&i.MaxLikes, &i.MaxViews)
+	return i, err
+}
+
 const syncReactions = `-- name: SyncReactions :one
 INSERT INTO
     posts_reactions_history (
@@ -102,9 +225,10 @@ INSERT INTO
         post_id,
         likes,
         reposts,
-        views
+        views,
+        comments
     )
-VALUES ($1, $2, $3, $4, $5, $6)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
 ON CONFLICT (
     post_id,
     (CAST(synced_at AS DATE))
@@ -114,9 +238,10 @@ SET
     likes = EXCLUDED.likes,
     reposts = EXCLUDED.reposts,
     views = EXCLUDED.views,
+    comments = EXCLUDED.comments,
     synced_at = EXCLUDED.synced_at
 RETURNING
-    id, synced_at, post_id, likes, reposts, views
+    id, synced_at, post_id, likes, reposts, views, comments
 `
 
 type SyncReactionsParams struct {
@@ -126,6 +251,7 @@ type SyncReactionsParams struct {
 	Likes    sql.NullInt64 `json:"likes"`
 	Reposts  sql.NullInt64 `json:"reposts"`
 	Views    sql.NullInt64 `json:"views"`
+	Comments sql.NullInt64 `json:"comments"`
 }
 
 func (q *Queries) SyncReactions(ctx context.Context, arg SyncReactionsParams) (PostsReactionsHistory, error) {
@@ -136,6 +262,7 @@ func (q *Queries) SyncReactions(ctx context.Context, arg SyncReactionsParams) (P
 		arg.Likes,
 		arg.Reposts,
 		arg.Views,
+		arg.Comments,
 	)
 	var i PostsReactionsHistory
 	err := row.Scan(
@@ -145,6 +272,7 @@ func (q *Queries) SyncReactions(ctx context.Context, arg SyncReactionsParams) (P
 		&i.Likes,
 		&i.Reposts,
 		&i.Views,
+		&i.Comments,
 	)
 	return i, err
 }
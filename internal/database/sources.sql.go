@@ -119,6 +119,45 @@ func (q *Queries) DeleteSource(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const getActiveSourcesByNetwork = `-- name: GetActiveSourcesByNetwork :many
+SELECT id, created_at, updated_at, network, user_name, user_id, is_active, sync_status, status_reason, last_synced FROM sources
+where network = $1 and is_active = TRUE
+`
+
+func (q *Queries) GetActiveSourcesByNetwork(ctx context.Context, network string) ([]Source, error) {
+	rows, err := q.db.QueryContext(ctx, getActiveSourcesByNetwork, network)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Source
+	for rows.Next() {
+		var i Source
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Network,
+			&i.UserName,
+			&i.UserID,
+			&i.IsActive,
+			&i.SyncStatus,
+			&i.StatusReason,
+			&i.LastSynced,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSourceById = `-- name: GetSourceById :one
 SELECT id, created_at, updated_at, network, user_name, user_id, is_active, sync_status, status_reason, last_synced FROM sources
 where id = $1
@@ -293,3 +332,33 @@ func (q *Queries) UpdateSourceSyncStatusById(ctx context.Context, arg UpdateSour
 	)
 	return i, err
 }
+
+const updateSourceUserName = `-- name: UpdateSourceUserName :one
+UPDATE sources
+SET user_name = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING id, created_at, updated_at, network, user_name, user_id, is_active, sync_status, status_reason, last_synced
+`
+
+type UpdateSourceUserNameParams struct {
+	ID       uuid.UUID `json:"id"`
+	UserName string    `json:"user_name"`
+}
+
+func (q *Queries) UpdateSourceUserName(ctx context.Context, arg UpdateSourceUserNameParams) (Source, error) {
+	row := q.db.QueryRowContext(ctx, updateSourceUserName, arg.ID, arg.UserName)
+	var i Source
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Network,
+		&i.UserName,
+		&i.UserID,
+		&i.IsActive,
+		&i.SyncStatus,
+		&i.StatusReason,
+		&i.LastSynced,
+	)
+	return i, err
+}
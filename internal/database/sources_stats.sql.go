@@ -24,7 +24,10 @@ INSERT INTO
         posts_count,
         average_likes,
         average_reposts,
-        average_views
+        average_views,
+        karma,
+        followers_delta,
+        following_delta
     )
 VALUES (
         $1,
@@ -35,10 +38,13 @@ VALUES (
         $6,
         $7,
         $8,
-        $9
+        $9,
+        $10,
+        $11,
+        $12
     )
 RETURNING
-    id, date, source_id, followers_count, following_count, posts_count, average_likes, average_reposts, average_views
+    id, date, source_id, followers_count, following_count, posts_count, average_likes, average_reposts, average_views, karma, followers_delta, following_delta
 `
 
 type CreateSourceStatParams struct {
@@ -51,6 +57,9 @@ type CreateSourceStatParams struct {
 	AverageLikes   sql.NullFloat64 `json:"average_likes"`
 	AverageReposts sql.NullFloat64 `json:"average_reposts"`
 	AverageViews   sql.NullFloat64 `json:"average_views"`
+	Karma          sql.NullInt64   `json:"karma"`
+	FollowersDelta sql.NullInt64   `json:"followers_delta"`
+	FollowingDelta sql.NullInt64   `json:"following_delta"`
 }
 
 func (q *Queries) CreateSourceStat(ctx context.Context, arg CreateSourceStatParams) (SourcesStat, error) {
@@ -64,6 +73,9 @@ func (q *Queries) CreateSourceStat(ctx context.Context, arg CreateSourceStatPara
 		arg.AverageLikes,
 		arg.AverageReposts,
 		arg.AverageViews,
+		arg.Karma,
+		arg.FollowersDelta,
+		arg.FollowingDelta,
 	)
 	var i SourcesStat
 	err := row.Scan(
@@ -76,12 +88,50 @@ func (q *Queries) CreateSourceStat(ctx context.Context, arg CreateSourceStatPara
 		&i.AverageLikes,
 		&i.AverageReposts,
 		&i.AverageViews,
+		&i.Karma,
+		&i.FollowersDelta,
+		&i.FollowingDelta,
+	)
+	return i, err
+}
+
+const getLatestSourceStatBeforeDate = `-- name: GetLatestSourceStatBeforeDate :one
+SELECT id, date, source_id, followers_count, following_count, posts_count, average_likes, average_reposts, average_views, karma, followers_delta, following_delta
+FROM sources_stats
+WHERE
+    source_id = $1
+    AND date < $2
+ORDER BY date DESC
+LIMIT 1
+`
+
+type GetLatestSourceStatBeforeDateParams struct {
+	SourceID uuid.UUID `json:"source_id"`
+	Date     time.Time `json:"date"`
+}
+
+func (q *Queries) GetLatestSourceStatBeforeDate(ctx context.Context, arg GetLatestSourceStatBeforeDateParams) (SourcesStat, error) {
+	row := q.db.QueryRowContext(ctx, getLatestSourceStatBeforeDate, arg.SourceID, arg.Date)
+	var i SourcesStat
+	err := row.Scan(
+		&i.ID,
+		&i.Date,
+		&i.SourceID,
+		&i.FollowersCount,
+		&i.FollowingCount,
+		&i.PostsCount,
+		&i.AverageLikes,
+		&i.AverageReposts,
+		&i.AverageViews,
+		&i.Karma,
+		&i.FollowersDelta,
+		&i.FollowingDelta,
 	)
 	return i, err
 }
 
 const getSourceStatsByDate = `-- name: GetSourceStatsByDate :one
-SELECT id, date, source_id, followers_count, following_count, posts_count, average_likes, average_reposts, average_views
+SELECT id, date, source_id, followers_count, following_count, posts_count, average_likes, average_reposts, average_views, karma, followers_delta, following_delta
 FROM sources_stats
 WHERE
     source_id = $1
@@ -107,10 +157,63 @@ func (q *Queries) GetSourceStatsByDate(ctx context.Context, arg GetSourceStatsBy
 		&i.AverageLikes,
 		&i.AverageReposts,
 		&i.AverageViews,
+		&i.Karma,
+		&i.FollowersDelta,
+		&i.FollowingDelta,
 	)
 	return i, err
 }
 
+const getSourceStatsSince = `-- name: GetSourceStatsSince :many
+SELECT id, date, source_id, followers_count, following_count, posts_count, average_likes, average_reposts, average_views, karma, followers_delta, following_delta
+FROM sources_stats
+WHERE
+    source_id = $1
+    AND date >= $2
+ORDER BY date
+`
+
+type GetSourceStatsSinceParams struct {
+	SourceID uuid.UUID `json:"source_id"`
+	Date     time.Time `json:"date"`
+}
+
+func (q *Queries) GetSourceStatsSince(ctx context.Context, arg GetSourceStatsSinceParams) ([]SourcesStat, error) {
+	rows, err := q.db.QueryContext(ctx, getSourceStatsSince, arg.SourceID, arg.Date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SourcesStat
+	for rows.Next() {
+		var i SourcesStat
+		if err := rows.Scan(
+			&i.ID,
+			&i.Date,
+			&i.SourceID,
+			&i.FollowersCount,
+			&i.FollowingCount,
+			&i.PostsCount,
+			&i.AverageLikes,
+			&i.AverageReposts,
+			&i.AverageViews,
+			&i.Karma,
+			&i.FollowersDelta,
+			&i.FollowingDelta,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSourceTotals = `-- name: GetSourceTotals :one
 SELECT
     COUNT(DISTINCT p.id)::BIGINT AS total_posts,
@@ -155,12 +258,15 @@ SET
     posts_count = $3,
     average_likes = $4,
     average_reposts = $5,
-    average_views = $6
+    average_views = $6,
+    karma = $7,
+    followers_delta = $8,
+    following_delta = $9
 WHERE
-    source_id = $7
-    AND date = $8
+    source_id = $10
+    AND date = $11
 RETURNING
-    id, date, source_id, followers_count, following_count, posts_count, average_likes, average_reposts, average_views
+    id, date, source_id, followers_count, following_count, posts_count, average_likes, average_reposts, average_views, karma, followers_delta, following_delta
 `
 
 type UpdateSourceDayStatsParams struct {
@@ -170,6 +276,9 @@ type UpdateSourceDayStatsParams struct {
 	AverageLikes   sql.NullFloat64 `json:"average_likes"`
 	AverageReposts sql.NullFloat64 `json:"average_reposts"`
 	AverageViews   sql.NullFloat64 `json:"average_views"`
+	Karma          sql.NullInt64   `json:"karma"`
+	FollowersDelta sql.NullInt64   `json:"followers_delta"`
+	FollowingDelta sql.NullInt64   `json:"following_delta"`
 	SourceID       uuid.UUID       `json:"source_id"`
 	Date           time.Time       `json:"date"`
 }
@@ -182,6 +291,9 @@ func (q *Queries) UpdateSourceDayStats(ctx context.Context, arg UpdateSourceDayS
 		arg.AverageLikes,
 		arg.AverageReposts,
 		arg.AverageViews,
+		arg.Karma,
+		arg.FollowersDelta,
+		arg.FollowingDelta,
 		arg.SourceID,
 		arg.Date,
 	)
@@ -196,6 +308,9 @@ func (q *Queries) UpdateSourceDayStats(ctx context.Context, arg UpdateSourceDayS
 		&i.AverageLikes,
 		&i.AverageReposts,
 		&i.AverageViews,
+		&i.Karma,
+		&i.FollowersDelta,
+		&i.FollowingDelta,
 	)
 	return i, err
 }
@@ -60,7 +60,7 @@ func (q *Queries) AddSourcesStatToTarget(ctx context.Context, arg AddSourcesStat
 }
 
 const getAllSourcesStatsForUser = `-- name: GetAllSourcesStatsForUser :many
-SELECT ss.id, ss.date, ss.source_id, ss.followers_count, ss.following_count, ss.posts_count, ss.average_likes, ss.average_reposts, ss.average_views
+SELECT ss.id, ss.date, ss.source_id, ss.followers_count, ss.following_count, ss.posts_count, ss.average_likes, ss.average_reposts, ss.average_views, ss.karma, ss.followers_delta, ss.following_delta
 FROM sources_stats ss
     LEFT JOIN sources s ON ss.source_id = s.id
 WHERE
@@ -87,6 +87,9 @@ func (q *Queries) GetAllSourcesStatsForUser(ctx context.Context, userID uuid.UUI
 			&i.AverageLikes,
 			&i.AverageReposts,
 			&i.AverageViews,
+			&i.Karma,
+			&i.FollowersDelta,
+			&i.FollowingDelta,
 		); err != nil {
 			return nil, err
 		}
@@ -102,7 +105,7 @@ func (q *Queries) GetAllSourcesStatsForUser(ctx context.Context, userID uuid.UUI
 }
 
 const getAllSourcesStatsWithTargetInfo = `-- name: GetAllSourcesStatsWithTargetInfo :many
-SELECT ss.id, ss.date, ss.source_id, ss.followers_count, ss.following_count, ss.posts_count, ss.average_likes, ss.average_reposts, ss.average_views, map.target_record_id
+SELECT ss.id, ss.date, ss.source_id, ss.followers_count, ss.following_count, ss.posts_count, ss.average_likes, ss.average_reposts, ss.average_views, ss.karma, ss.followers_delta, ss.following_delta, map.target_record_id
 FROM
     sources_stats ss
     LEFT JOIN sources_stats_on_target map ON ss.id = map.stat_id
@@ -126,6 +129,9 @@ type GetAllSourcesStatsWithTargetInfoRow struct {
 	AverageLikes   sql.NullFloat64 `json:"average_likes"`
 	AverageReposts sql.NullFloat64 `json:"average_reposts"`
 	AverageViews   sql.NullFloat64 `json:"average_views"`
+	Karma          sql.NullInt64   `json:"karma"`
+	FollowersDelta sql.NullInt64   `json:"followers_delta"`
+	FollowingDelta sql.NullInt64   `json:"following_delta"`
 	TargetRecordID sql.NullString  `json:"target_record_id"`
 }
 
@@ -148,6 +154,9 @@ func (q *Queries) GetAllSourcesStatsWithTargetInfo(ctx context.Context, arg GetA
 			&i.AverageLikes,
 			&i.AverageReposts,
 			&i.AverageViews,
+			&i.Karma,
+			&i.FollowersDelta,
+			&i.FollowingDelta,
 			&i.TargetRecordID,
 		); err != nil {
 			return nil, err
@@ -164,7 +173,7 @@ func (q *Queries) GetAllSourcesStatsWithTargetInfo(ctx context.Context, arg GetA
 }
 
 const getSyncedSourcesStatsForUpdate = `-- name: GetSyncedSourcesStatsForUpdate :many
-SELECT ss.id, ss.date, ss.source_id, ss.followers_count, ss.following_count, ss.posts_count, ss.average_likes, ss.average_reposts, ss.average_views, map.target_record_id
+SELECT ss.id, ss.date, ss.source_id, ss.followers_count, ss.following_count, ss.posts_count, ss.average_likes, ss.average_reposts, ss.average_views, ss.karma, ss.followers_delta, ss.following_delta, map.target_record_id
 FROM
     sources_stats ss
     JOIN sources_stats_on_target map ON ss.id = map.stat_id
@@ -190,6 +199,9 @@ type GetSyncedSourcesStatsForUpdateRow struct {
 	AverageLikes   sql.NullFloat64 `json:"average_likes"`
 	AverageReposts sql.NullFloat64 `json:"average_reposts"`
 	AverageViews   sql.NullFloat64 `json:"average_views"`
+	Karma          sql.NullInt64   `json:"karma"`
+	FollowersDelta sql.NullInt64   `json:"followers_delta"`
+	FollowingDelta sql.NullInt64   `json:"following_delta"`
 	TargetRecordID string          `json:"target_record_id"`
 }
 
@@ -212,6 +224,9 @@ func (q *Queries) GetSyncedSourcesStatsForUpdate(ctx context.Context, arg GetSyn
 			&i.AverageLikes,
 			&i.AverageReposts,
 			&i.AverageViews,
+			&i.Karma,
+			&i.FollowersDelta,
+			&i.FollowingDelta,
 			&i.TargetRecordID,
 		); err != nil {
 			return nil, err
@@ -228,7 +243,7 @@ func (q *Queries) GetSyncedSourcesStatsForUpdate(ctx context.Context, arg GetSyn
 }
 
 const getUnsyncedSourcesStatsForTarget = `-- name: GetUnsyncedSourcesStatsForTarget :many
-SELECT ss.id, ss.date, ss.source_id, ss.followers_count, ss.following_count, ss.posts_count, ss.average_likes, ss.average_reposts, ss.average_views
+SELECT ss.id, ss.date, ss.source_id, ss.followers_count, ss.following_count, ss.posts_count, ss.average_likes, ss.average_reposts, ss.average_views, ss.karma, ss.followers_delta, ss.following_delta
 FROM sources_stats ss
 WHERE
     ss.source_id = $1
@@ -265,6 +280,9 @@ func (q *Queries) GetUnsyncedSourcesStatsForTarget(ctx context.Context, arg GetU
 			&i.AverageLikes,
 			&i.AverageReposts,
 			&i.AverageViews,
+			&i.Karma,
+			&i.FollowersDelta,
+			&i.FollowingDelta,
 		); err != nil {
 			return nil, err
 		}
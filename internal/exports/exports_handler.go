@@ -9,18 +9,34 @@ import (
 	"time"
 
 	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/storage"
 	"github.com/google/uuid"
 )
 
-func DeleteAllExports(userID uuid.UUID, dbQueries *database.Queries) error {
+func DeleteAllExports(userID uuid.UUID, dbQueries *database.Queries, encryptionKey []byte) error {
 
 	exports, err := dbQueries.GetAllExportsByUserId(context.Background(), userID)
 	if err != nil {
 		log.Printf("Error getting all exports records: %v", err)
 		return err
 	}
+
+	remote, err := storage.Load(context.Background(), dbQueries, encryptionKey, userID)
+	if err != nil {
+		log.Printf("Error loading remote storage, uploaded exports are kept: %v", err)
+	}
+
 	for _, exp := range exports {
-		if exp.DownloadUrl.Valid {
+		if exp.DownloadUrl.Valid && storage.IsRemote(exp.DownloadUrl.String) {
+			if remote == nil {
+				continue
+			}
+			if key, ok := remote.Key(exp.DownloadUrl.String); ok {
+				if err := remote.Delete(context.Background(), key); err != nil {
+					log.Printf("Error deleting remote export file %s: %v", exp.DownloadUrl.String, err)
+				}
+			}
+		} else if exp.DownloadUrl.Valid {
 			err := os.Remove(exp.DownloadUrl.String)
 			if err != nil {
 				log.Printf("Error deleting export file %s: %v", exp.DownloadUrl.String, err)
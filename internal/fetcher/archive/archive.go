@@ -0,0 +1,242 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+// Package archive backfills a source from the official "download your data"
+// exports of the networks that provide one.
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/fetcher/common"
+	"github.com/google/uuid"
+)
+
+// SupportedNetworks lists the networks whose exports can be imported.
+var SupportedNetworks = []string{"Twitter", "Instagram", "Bluesky", "Mastodon"}
+
+type Result struct {
+	Format   string
+	Imported int
+	Skipped  int
+}
+
+// archivePost is a post read from an export, before it is matched against
+// the posts already stored for the source.
+type archivePost struct {
+	NetworkID string
+	CreatedAt time.Time
+	PostType  string
+	Author    string
+	Content   string
+	Likes     sql.NullInt64
+	Reposts   sql.NullInt64
+	// MatchByTime is set for exports that carry no network ID. Such posts
+	// are treated as duplicates when a stored post was created within
+	// archiveTimeMatchWindow of them.
+	MatchByTime bool
+}
+
+const archiveTimeMatchWindow = time.Minute
+
+// maxArchiveEntrySize skips media and other large files inside zip exports.
+const maxArchiveEntrySize = 256 << 20
+
+func IsSupported(network string) bool {
+	for _, n := range SupportedNetworks {
+		if n == network {
+			return true
+		}
+	}
+	return false
+}
+
+// readArchiveFiles returns the data files of an upload, keyed by their path.
+// Zip exports are unpacked; any other upload is returned as a single file.
+func readArchiveFiles(filename string, r io.ReaderAt, size int64) (map[string][]byte, error) {
+	magic := make([]byte, 4)
+	if _, err := r.ReadAt(magic, 0); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	if !bytes.Equal(magic, []byte("PK\x03\x04")) {
+		data, err := io.ReadAll(io.NewSectionReader(r, 0, size))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read upload: %w", err)
+		}
+		return map[string][]byte{filename: data}, nil
+	}
+
+	zipReader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip file: %w", err)
+	}
+
+	files := make(map[string][]byte)
+	for _, f := range zipReader.File {
+		switch strings.ToLower(path.Ext(f.Name)) {
+		case ".js", ".json", ".car":
+		default:
+			continue
+		}
+		if f.UncompressedSize64 > maxArchiveEntrySize {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open zip entry %s: %w", f.Name, err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read zip entry %s: %w", f.Name, err)
+		}
+		files[f.Name] = content
+	}
+
+	return files, nil
+}
+
+// Import reads an export archive of size bytes from r and stores its posts
+// under the source. Posts that already exist are left untouched.
+func Import(ctx context.Context, dbQueries *database.Queries, source database.Source, filename string, r io.ReaderAt, size int64) (*Result, error) {
+	files, err := readArchiveFiles(filename, r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	var posts []archivePost
+	result := &Result{}
+
+	switch source.Network {
+	case "Twitter":
+		result.Format = "twitter_tweets_js"
+		posts, err = parseTwitterArchive(files, source.UserName)
+	case "Instagram":
+		result.Format = "instagram_json"
+		posts, err = parseInstagramArchive(files, source.UserName)
+	case "Bluesky":
+		result.Format = "bluesky_car"
+		posts, err = parseBlueskyArchive(files, source.UserName)
+	case "Mastodon":
+		result.Format = "mastodon_outbox"
+		posts, err = parseMastodonArchive(files)
+	default:
+		return nil, fmt.Errorf("archive import is not supported for %s", source.Network)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(posts) == 0 {
+		return nil, errors.New("no posts found in the archive")
+	}
+
+	exclusionMap, err := common.LoadExclusionMap(dbQueries, source.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{})
+	now := time.Now()
+
+	for _, p := range posts {
+		if _, exists := seen[p.NetworkID]; exists {
+			continue
+		}
+		seen[p.NetworkID] = struct{}{}
+
+		if exclusionMap[p.NetworkID] {
+			result.Skipped++
+			continue
+		}
+
+		exists, err := postExists(ctx, dbQueries, source.ID, p)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			result.Skipped++
+			continue
+		}
+
+		postID, err := common.CreateOrUpdatePost(
+			ctx,
+			dbQueries,
+			source.ID,
+			p.NetworkID,
+			source.Network,
+			p.CreatedAt,
+			p.PostType,
+			p.Author,
+			p.Content,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		// The import time is the post's last sync, so ArchiveUnsyncedPosts
+		// keeps the post until a live sync sees it.
+		if err := dbQueries.CreatePostImport(ctx, database.CreatePostImportParams{
+			PostID:        postID,
+			ArchiveFormat: result.Format,
+		}); err != nil {
+			return nil, err
+		}
+
+		if p.Likes.Valid || p.Reposts.Valid {
+			if _, err := dbQueries.SyncReactions(ctx, database.SyncReactionsParams{
+				ID:       uuid.New(),
+				SyncedAt: now,
+				PostID:   postID,
+				Likes:    p.Likes,
+				Reposts:  p.Reposts,
+			}); err != nil {
+				return nil, err
+			}
+		}
+
+		result.Imported++
+	}
+
+	return result, nil
+}
+
+func postExists(ctx context.Context, dbQueries *database.Queries, sourceID uuid.UUID, p archivePost) (bool, error) {
+	_, err := dbQueries.GetPostBySourceAndNetworkId(ctx, database.GetPostBySourceAndNetworkIdParams{
+		NetworkInternalID: p.NetworkID,
+		SourceID:          sourceID,
+	})
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+
+	if !p.MatchByTime {
+		return false, nil
+	}
+
+	_, err = dbQueries.GetPostIdBySourceAndTimeRange(ctx, database.GetPostIdBySourceAndTimeRangeParams{
+		SourceID: sourceID,
+		FromTime: p.CreatedAt.Add(-archiveTimeMatchWindow),
+		ToTime:   p.CreatedAt.Add(archiveTimeMatchWindow),
+	})
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return false, err
+}
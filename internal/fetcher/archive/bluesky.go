@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package archive
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+const bskyPostCollection = "app.bsky.feed.post/"
+
+// bskyMSTNode is a node of the repository's Merkle Search Tree. Entry keys
+// are prefix-compressed against the previous entry of the same node.
+type bskyMSTNode struct {
+	Entries []struct {
+		PrefixLen int       `cbor:"p"`
+		KeySuffix []byte    `cbor:"k"`
+		Value     cbor.Tag  `cbor:"v"`
+		Tree      *cbor.Tag `cbor:"t"`
+	} `cbor:"e"`
+}
+
+type bskyArchiveRecord struct {
+	Type      string `cbor:"$type"`
+	Text      string `cbor:"text"`
+	CreatedAt string `cbor:"createdAt"`
+	Reply     *struct {
+		Parent struct {
+			URI string `cbor:"uri"`
+		} `cbor:"parent"`
+	} `cbor:"reply"`
+	Embed *struct {
+		Type string `cbor:"$type"`
+	} `cbor:"embed"`
+}
+
+// carCIDLength returns the length of the binary CID at the start of b.
+func carCIDLength(b []byte) (int, error) {
+	// CIDv0 is a bare sha2-256 multihash.
+	if len(b) >= 34 && b[0] == 0x12 && b[1] == 0x20 {
+		return 34, nil
+	}
+
+	pos := 0
+	// version, codec and multihash function code
+	for i := 0; i < 3; i++ {
+		_, n := binary.Uvarint(b[pos:])
+		if n <= 0 {
+			return 0, errors.New("invalid CID")
+		}
+		pos += n
+	}
+
+	size, n := binary.Uvarint(b[pos:])
+	if n <= 0 {
+		return 0, errors.New("invalid CID")
+	}
+	pos += n + int(size)
+	if pos > len(b) {
+		return 0, errors.New("invalid CID")
+	}
+	return pos, nil
+}
+
+// readCARBlocks splits a CAR v1 file into its blocks, keyed by binary CID.
+func readCARBlocks(data []byte) (map[string][]byte, error) {
+	headerLen, n := binary.Uvarint(data)
+	if n <= 0 || n+int(headerLen) > len(data) {
+		return nil, errors.New("invalid CAR header")
+	}
+	pos := n + int(headerLen)
+
+	blocks := make(map[string][]byte)
+	for pos < len(data) {
+		sectionLen, n := binary.Uvarint(data[pos:])
+		if n <= 0 {
+			return nil, errors.New("invalid CAR section")
+		}
+		pos += n
+		end := pos + int(sectionLen)
+		if end > len(data) {
+			return nil, errors.New("truncated CAR file")
+		}
+
+		section := data[pos:end]
+		cidLen, err := carCIDLength(section)
+		if err != nil {
+			return nil, err
+		}
+		blocks[string(section[:cidLen])] = section[cidLen:]
+		pos = end
+	}
+
+	return blocks, nil
+}
+
+// cidFromLink returns the binary CID of a DAG-CBOR link (tag 42), which is
+// stored with a leading multibase identity byte.
+func cidFromLink(tag cbor.Tag) (string, bool) {
+	if tag.Number != 42 {
+		return "", false
+	}
+	b, ok := tag.Content.([]byte)
+	if !ok || len(b) < 2 {
+		return "", false
+	}
+	return string(b[1:]), true
+}
+
+// parseBlueskyArchive reads the repo.car export of a Bluesky account. Only
+// the account's own posts and quotes are imported; the export carries no
+// engagement counts, and replies are skipped like in the default live sync.
+func parseBlueskyArchive(files map[string][]byte, username string) ([]archivePost, error) {
+	var posts []archivePost
+	found := false
+
+	for name, data := range files {
+		if !strings.EqualFold(path.Ext(name), ".car") {
+			continue
+		}
+		found = true
+
+		blocks, err := readCARBlocks(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		for _, block := range blocks {
+			var node bskyMSTNode
+			if err := cbor.Unmarshal(block, &node); err != nil || len(node.Entries) == 0 {
+				continue
+			}
+
+			var key []byte
+			for _, entry := range node.Entries {
+				if entry.PrefixLen > len(key) {
+					break
+				}
+				key = append(key[:entry.PrefixLen:entry.PrefixLen], entry.KeySuffix...)
+
+				if !strings.HasPrefix(string(key), bskyPostCollection) {
+					continue
+				}
+				rkey := strings.TrimPrefix(string(key), bskyPostCollection)
+
+				cid, ok := cidFromLink(entry.Value)
+				if !ok {
+					continue
+				}
+				recordData, ok := blocks[cid]
+				if !ok {
+					continue
+				}
+
+				var record bskyArchiveRecord
+				if err := cbor.Unmarshal(recordData, &record); err != nil {
+					continue
+				}
+				if record.Reply != nil {
+					continue
+				}
+
+				createdAt, err := time.Parse(time.RFC3339Nano, record.CreatedAt)
+				if err != nil {
+					continue
+				}
+
+				postType := "post"
+				if record.Embed != nil && (record.Embed.Type == "app.bsky.embed.record" || record.Embed.Type == "app.bsky.embed.recordWithMedia") {
+					postType = "quote"
+				}
+
+				posts = append(posts, archivePost{
+					NetworkID: rkey,
+					CreatedAt: createdAt,
+					PostType:  postType,
+					Author:    username,
+					Content:   record.Text,
+				})
+			}
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("no .car repository export found in the upload")
+	}
+
+	return posts, nil
+}
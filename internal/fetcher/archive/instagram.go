@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var instagramPostsFile = regexp.MustCompile(`^posts_\d+\.json$`)
+
+type instagramArchiveMedia struct {
+	URI               string `json:"uri"`
+	CreationTimestamp int64  `json:"creation_timestamp"`
+	Title             string `json:"title"`
+}
+
+type instagramArchivePost struct {
+	Media             []instagramArchiveMedia `json:"media"`
+	Title             string                  `json:"title"`
+	CreationTimestamp int64                   `json:"creation_timestamp"`
+}
+
+type instagramArchiveReels struct {
+	Reels []instagramArchivePost `json:"ig_reels_media"`
+}
+
+// fixMetaEncoding undoes the double encoding in Meta exports, where UTF-8
+// bytes are written out as individual \u00XX escapes.
+func fixMetaEncoding(s string) string {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r > 0xff {
+			return s
+		}
+		b = append(b, byte(r))
+	}
+	if !utf8.Valid(b) {
+		return s
+	}
+	return string(b)
+}
+
+func convertInstagramArchivePost(item instagramArchivePost, postType, username string) (archivePost, bool) {
+	caption := item.Title
+	created := item.CreationTimestamp
+	if len(item.Media) > 0 {
+		if caption == "" {
+			caption = item.Media[0].Title
+		}
+		if created == 0 {
+			created = item.Media[0].CreationTimestamp
+		}
+	}
+	if created == 0 {
+		return archivePost{}, false
+	}
+
+	if postType == "" {
+		postType = "image"
+		for _, m := range item.Media {
+			if strings.EqualFold(path.Ext(m.URI), ".mp4") {
+				postType = "video"
+				break
+			}
+		}
+	}
+
+	return archivePost{
+		// The export has no shortcodes, so posts are matched by time instead.
+		NetworkID:   "archive-" + strconv.FormatInt(created, 10),
+		CreatedAt:   time.Unix(created, 0).UTC(),
+		PostType:    postType,
+		Author:      username,
+		Content:     fixMetaEncoding(caption),
+		MatchByTime: true,
+	}, true
+}
+
+// parseInstagramArchive reads posts_N.json and reels.json from an Instagram
+// JSON export. The export contains no engagement counts.
+func parseInstagramArchive(files map[string][]byte, username string) ([]archivePost, error) {
+	var posts []archivePost
+	found := false
+
+	for name, data := range files {
+		base := path.Base(name)
+
+		switch {
+		case instagramPostsFile.MatchString(base):
+			found = true
+			var items []instagramArchivePost
+			if err := json.Unmarshal(data, &items); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			for _, item := range items {
+				if p, ok := convertInstagramArchivePost(item, "", username); ok {
+					posts = append(posts, p)
+				}
+			}
+
+		case base == "reels.json":
+			found = true
+			var reels instagramArchiveReels
+			if err := json.Unmarshal(data, &reels); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			for _, item := range reels.Reels {
+				if p, ok := convertInstagramArchivePost(item, "video", username); ok {
+					posts = append(posts, p)
+				}
+			}
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("posts_1.json not found in the archive; make sure the export was requested in JSON format")
+	}
+
+	return posts, nil
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/fetcher/common"
+)
+
+type mastodonOutbox struct {
+	OrderedItems []struct {
+		Type      string          `json:"type"`
+		Actor     string          `json:"actor"`
+		Published time.Time       `json:"published"`
+		Object    json.RawMessage `json:"object"`
+	} `json:"orderedItems"`
+}
+
+type mastodonArchiveNote struct {
+	ID        string    `json:"id"`
+	Published time.Time `json:"published"`
+	Content   string    `json:"content"`
+	InReplyTo *string   `json:"inReplyTo"`
+}
+
+// mastodonStatusID extracts the status ID from an ActivityPub object URI,
+// the same way the live sync does for reblogs.
+func mastodonStatusID(uri string) string {
+	if parts := strings.Split(uri, "statuses/"); len(parts) > 1 {
+		return parts[1]
+	}
+	return path.Base(uri)
+}
+
+// mastodonHandle turns an actor or status URI into user@domain.
+func mastodonHandle(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return ""
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i, s := range segments {
+		if (s == "users" || s == "u") && i+1 < len(segments) {
+			return fmt.Sprintf("%s@%s", segments[i+1], u.Host)
+		}
+		if strings.HasPrefix(s, "@") {
+			return fmt.Sprintf("%s@%s", strings.TrimPrefix(s, "@"), u.Host)
+		}
+	}
+	return ""
+}
+
+// parseMastodonArchive reads outbox.json from a Mastodon (or compatible)
+// export. Boosts become reposts; replies are skipped like in the live sync.
+func parseMastodonArchive(files map[string][]byte) ([]archivePost, error) {
+	var posts []archivePost
+	found := false
+
+	for name, data := range files {
+		if path.Base(name) != "outbox.json" {
+			continue
+		}
+		found = true
+
+		var outbox mastodonOutbox
+		if err := json.Unmarshal(data, &outbox); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		for _, item := range outbox.OrderedItems {
+			switch item.Type {
+			case "Create":
+				var note mastodonArchiveNote
+				if err := json.Unmarshal(item.Object, &note); err != nil || note.ID == "" {
+					continue
+				}
+				if note.InReplyTo != nil && *note.InReplyTo != "" {
+					continue
+				}
+
+				createdAt := note.Published
+				if createdAt.IsZero() {
+					createdAt = item.Published
+				}
+
+				posts = append(posts, archivePost{
+					NetworkID: mastodonStatusID(note.ID),
+					CreatedAt: createdAt,
+					PostType:  "post",
+					Author:    mastodonHandle(item.Actor),
+					Content:   common.StripHTMLToText(note.Content),
+				})
+
+			case "Announce":
+				var target string
+				if err := json.Unmarshal(item.Object, &target); err != nil || target == "" {
+					continue
+				}
+
+				posts = append(posts, archivePost{
+					NetworkID: mastodonStatusID(target),
+					CreatedAt: item.Published,
+					PostType:  "repost",
+					Author:    mastodonHandle(target),
+				})
+			}
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("outbox.json not found in the archive")
+	}
+
+	return posts, nil
+}
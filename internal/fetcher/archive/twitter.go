@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package archive
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// twitterTweetsFile matches data/tweets.js, data/tweets-part1.js and the
+// older data/tweet.js naming.
+var twitterTweetsFile = regexp.MustCompile(`^tweets?(-part\d+)?\.js$`)
+
+type twitterArchiveTweet struct {
+	Tweet struct {
+		IDStr                string `json:"id_str"`
+		CreatedAt            string `json:"created_at"`
+		FullText             string `json:"full_text"`
+		FavoriteCount        string `json:"favorite_count"`
+		RetweetCount         string `json:"retweet_count"`
+		InReplyToStatusIDStr string `json:"in_reply_to_status_id_str"`
+	} `json:"tweet"`
+}
+
+func parseCount(value string) sql.NullInt64 {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: n, Valid: true}
+}
+
+// parseTwitterArchive reads the tweets.js files of a Twitter/X export.
+// Retweets are skipped because the export does not carry the ID of the
+// retweeted tweet, which is what the live sync stores; replies are skipped
+// as the live sync does not collect them either.
+func parseTwitterArchive(files map[string][]byte, username string) ([]archivePost, error) {
+	var posts []archivePost
+	found := false
+
+	for name, data := range files {
+		if !twitterTweetsFile.MatchString(path.Base(name)) {
+			continue
+		}
+		found = true
+
+		// The file is a JavaScript assignment: window.YTD.tweets.part0 = [...]
+		idx := bytes.IndexByte(data, '=')
+		if idx < 0 {
+			return nil, fmt.Errorf("%s: unexpected file format", name)
+		}
+
+		var tweets []twitterArchiveTweet
+		if err := json.Unmarshal(data[idx+1:], &tweets); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		for _, t := range tweets {
+			tweet := t.Tweet
+			if tweet.IDStr == "" {
+				continue
+			}
+			if strings.HasPrefix(tweet.FullText, "RT @") || tweet.InReplyToStatusIDStr != "" {
+				continue
+			}
+
+			createdAt, err := time.Parse(time.RubyDate, tweet.CreatedAt)
+			if err != nil {
+				continue
+			}
+
+			posts = append(posts, archivePost{
+				NetworkID: tweet.IDStr,
+				CreatedAt: createdAt,
+				PostType:  "post",
+				Author:    username,
+				Content:   tweet.FullText,
+				Likes:     parseCount(tweet.FavoriteCount),
+				Reposts:   parseCount(tweet.RetweetCount),
+			})
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("tweets.js not found in the archive")
+	}
+
+	return posts, nil
+}
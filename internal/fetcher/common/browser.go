@@ -0,0 +1,290 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	DefaultBrowserPoolSize = 2
+	browserIdleTimeout     = 5 * time.Minute
+	browserUserAgent       = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+)
+
+// BrowserPool shares headless Chrome processes between every fetcher that
+// needs JavaScript rendering. One browser is kept per proxy server, each job
+// gets its own tab in a fresh browser context so cookies never leak between
+// sources, and at most size tabs are open at once. Browsers that crash are
+// replaced on the next request and idle ones are shut down.
+type BrowserPool struct {
+	sem chan struct{}
+
+	mu       sync.Mutex
+	browsers map[string]*pooledBrowser
+}
+
+type pooledBrowser struct {
+	ctx         context.Context
+	cancel      context.CancelFunc
+	allocCancel context.CancelFunc
+	tabs        int
+	idleTimer   *time.Timer
+}
+
+func NewBrowserPool(size int) *BrowserPool {
+	if size < 1 {
+		size = DefaultBrowserPoolSize
+	}
+	return &BrowserPool{
+		sem:      make(chan struct{}, size),
+		browsers: make(map[string]*pooledBrowser),
+	}
+}
+
+// NewBrowserTab opens a tab on the shared pool with the client's proxy,
+// User-Agent and extra headers applied. ctx bounds the wait for a free tab;
+// the tab itself stays open until release is called.
+func (c *Client) NewBrowserTab(ctx context.Context) (tabCtx context.Context, release func(), err error) {
+	if c.Browsers == nil {
+		return nil, nil, errors.New("no browser pool configured")
+	}
+	return c.Browsers.newTab(ctx, c)
+}
+
+func (p *BrowserPool) newTab(ctx context.Context, c *Client) (context.Context, func(), error) {
+	select {
+	case p.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+
+	proxy := c.browserProxy()
+
+	var lastErr error
+	for attempt := 0; attempt < 2 && ctx.Err() == nil; attempt++ {
+		b, err := p.acquire(proxy)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		tabCtx, tabCancel := chromedp.NewContext(b.ctx, chromedp.WithNewBrowserContext())
+		if err := chromedp.Run(tabCtx, c.browserSetup()); err != nil {
+			tabCancel()
+			if b.ctx.Err() != nil {
+				// The browser died underneath us; start a new one.
+				p.discard(proxy, b)
+			}
+			p.release(proxy, b)
+			lastErr = err
+			continue
+		}
+
+		var once sync.Once
+		release := func() {
+			once.Do(func() {
+				tabCancel()
+				p.release(proxy, b)
+				<-p.sem
+			})
+		}
+		return tabCtx, release, nil
+	}
+
+	<-p.sem
+	if lastErr == nil {
+		lastErr = ctx.Err()
+	}
+	return nil, nil, fmt.Errorf("failed to open browser tab: %w", lastErr)
+}
+
+func (p *BrowserPool) acquire(proxy string) (*pooledBrowser, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if b, ok := p.browsers[proxy]; ok {
+		if b.ctx.Err() == nil {
+			if b.idleTimer != nil {
+				b.idleTimer.Stop()
+				b.idleTimer = nil
+			}
+			b.tabs++
+			return b, nil
+		}
+		log.Printf("Browser: restarting crashed browser (proxy %q)", proxy)
+		b.close()
+		delete(p.browsers, proxy)
+	}
+
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.UserAgent(browserUserAgent),
+		chromedp.WindowSize(1920, 1080),
+		chromedp.Flag("headless", true),
+		chromedp.Flag("disable-gpu", true),
+		chromedp.Flag("no-sandbox", true),
+		chromedp.Flag("disable-setuid-sandbox", true),
+		chromedp.Flag("disable-dev-shm-usage", true),
+		chromedp.Flag("enable-automation", false),
+		chromedp.Flag("disable-blink-features", "AutomationControlled"),
+		chromedp.Flag("disable-infobars", true),
+		chromedp.Flag("no-first-run", true),
+		chromedp.Flag("no-default-browser-check", true),
+	)
+	if proxy != "" {
+		opts = append(opts, chromedp.ProxyServer(proxy))
+	}
+
+	allocCtx, allocCancel := chromedp.NewExecAllocator(context.Background(), opts...)
+	ctx, cancel := chromedp.NewContext(allocCtx)
+	if err := chromedp.Run(ctx); err != nil {
+		cancel()
+		allocCancel()
+		return nil, fmt.Errorf("failed to start browser: %w", err)
+	}
+
+	b := &pooledBrowser{ctx: ctx, cancel: cancel, allocCancel: allocCancel, tabs: 1}
+	p.browsers[proxy] = b
+	return b, nil
+}
+
+func (p *BrowserPool) release(proxy string, b *pooledBrowser) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	b.tabs--
+	if b.tabs > 0 {
+		return
+	}
+	if p.browsers[proxy] != b {
+		// The browser was discarded while this tab was still open.
+		b.close()
+		return
+	}
+	b.idleTimer = time.AfterFunc(browserIdleTimeout, func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.browsers[proxy] == b && b.tabs == 0 {
+			b.close()
+			delete(p.browsers, proxy)
+		}
+	})
+}
+
+// discard drops a crashed browser from the pool. It is shut down right away
+// when no tab uses it, or by release once its last tab closes.
+func (p *BrowserPool) discard(proxy string, b *pooledBrowser) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.browsers[proxy] == b {
+		delete(p.browsers, proxy)
+	}
+	if b.tabs == 0 {
+		b.close()
+	}
+}
+
+// Close shuts down every pooled browser.
+func (p *BrowserPool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for proxy, b := range p.browsers {
+		b.close()
+		delete(p.browsers, proxy)
+	}
+}
+
+func (b *pooledBrowser) close() {
+	if b.idleTimer != nil {
+		b.idleTimer.Stop()
+	}
+	b.cancel()
+	b.allocCancel()
+}
+
+// browserProxy is the client's proxy in the form Chrome's --proxy-server
+// flag accepts. Chrome cannot take credentials there; see browserSetup.
+func (c *Client) browserProxy() string {
+	if c.network.ProxyURL == "" {
+		return ""
+	}
+	proxy, err := ParseProxyURL(c.network.ProxyURL)
+	if err != nil {
+		return ""
+	}
+	scheme := proxy.Scheme
+	if scheme == "socks5h" {
+		scheme = "socks5"
+	}
+	return scheme + "://" + proxy.Host
+}
+
+// browserSetup applies the client's User-Agent and extra headers to a new
+// tab and answers proxy authentication challenges.
+func (c *Client) browserSetup() chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		if c.network.UserAgent != "" {
+			if err := emulation.SetUserAgentOverride(c.network.UserAgent).Do(ctx); err != nil {
+				return err
+			}
+		}
+
+		if len(c.network.Headers) > 0 {
+			headers := make(network.Headers, len(c.network.Headers))
+			for k, v := range c.network.Headers {
+				headers[k] = v
+			}
+			if err := network.Enable().Do(ctx); err != nil {
+				return err
+			}
+			if err := network.SetExtraHTTPHeaders(headers).Do(ctx); err != nil {
+				return err
+			}
+		}
+
+		if c.network.ProxyURL == "" {
+			return nil
+		}
+		proxy, err := ParseProxyURL(c.network.ProxyURL)
+		if err != nil || proxy.User == nil {
+			return nil
+		}
+		if proxy.Scheme != "http" && proxy.Scheme != "https" {
+			log.Printf("Browser: %s proxies with credentials are not supported by Chrome", proxy.Scheme)
+			return nil
+		}
+
+		username := proxy.User.Username()
+		password, _ := proxy.User.Password()
+
+		chromedp.ListenTarget(ctx, func(ev interface{}) {
+			switch e := ev.(type) {
+			case *fetch.EventRequestPaused:
+				go func() {
+					_ = fetch.ContinueRequest(e.RequestID).Do(ctx)
+				}()
+			case *fetch.EventAuthRequired:
+				go func() {
+					_ = fetch.ContinueWithAuth(e.RequestID, &fetch.AuthChallengeResponse{
+						Response: fetch.AuthChallengeResponseResponseProvideCredentials,
+						Username: username,
+						Password: password,
+					}).Do(ctx)
+				}()
+			}
+		})
+
+		return fetch.Enable().WithHandleAuthRequests(true).Do(ctx)
+	})
+}
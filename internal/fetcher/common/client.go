@@ -2,18 +2,297 @@
 package common
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
+
+	"golang.org/x/oauth2"
 )
 
 type Client struct {
 	HTTPClient http.Client
+	Browsers   *BrowserPool
+	endpoints  map[string]*url.URL
+	network    NetworkSettings
+	base       http.RoundTripper
+	wrap       func(http.RoundTripper) http.RoundTripper
+}
+
+// NetworkSettings controls how a source's requests leave the server.
+type NetworkSettings struct {
+	// ProxyURL is an http, https or socks5 proxy, optionally with credentials.
+	ProxyURL  string
+	UserAgent string
+	Headers   map[string]string
 }
 
-func NewClient(timeout time.Duration) *Client {
+// NewClient returns a client whose fetchers open browser tabs on browsers.
+// browsers may be nil for clients that never render pages.
+func NewClient(timeout time.Duration, browsers *BrowserPool) *Client {
 	return &Client{
 		HTTPClient: http.Client{
 			Timeout: timeout,
 		},
+		Browsers: browsers,
+	}
+}
+
+// endpointTransport sends requests addressed to a default base URL to its
+// configured replacement instead.
+type endpointTransport struct {
+	base      http.RoundTripper
+	endpoints map[string]*url.URL
+}
+
+func (t *endpointTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if target, ok := t.endpoints[baseURLKey(req.URL)]; ok {
+		req = req.Clone(req.Context())
+		rewriteURL(req.URL, target)
+		req.Host = ""
+	}
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+// headerTransport applies a source's User-Agent and extra headers on top of
+// whatever the fetcher set.
+type headerTransport struct {
+	base      http.RoundTripper
+	userAgent string
+	headers   map[string]string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	if t.userAgent != "" {
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+func baseURLKey(u *url.URL) string {
+	return strings.ToLower(u.Scheme + "://" + u.Host)
+}
+
+func rewriteURL(u *url.URL, target *url.URL) {
+	prefix := strings.TrimRight(target.Path, "/")
+	u.Scheme = target.Scheme
+	u.Host = target.Host
+	if u.RawPath != "" {
+		u.RawPath = strings.TrimRight(target.EscapedPath(), "/") + u.RawPath
+	}
+	u.Path = prefix + u.Path
+}
+
+// ParseBaseURL validates an endpoint override. Only http and https URLs are
+// accepted; a path is kept as a prefix for every request.
+func ParseBaseURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%q is not an http(s) base URL", raw)
+	}
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u, nil
+}
+
+// ParseProxyURL validates a proxy setting. http, https and socks5 proxies
+// are supported.
+func ParseProxyURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("%q is not an http(s) or socks5 proxy URL", u.Redacted())
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("%q has no proxy host", u.Redacted())
+	}
+	return u, nil
+}
+
+// WrapTransport installs a wrapper around the client's transport, above
+// endpoint overrides and below per-source headers. It is used to record
+// fixtures, which keep the default host of each request.
+func (c *Client) WrapTransport(wrap func(http.RoundTripper) http.RoundTripper) {
+	c.base = c.rawTransport()
+	c.wrap = wrap
+	c.HTTPClient.Transport = c.transport(c.base)
+}
+
+// WithEndpoints returns a copy of the client whose requests to each default
+// base URL in overrides go to the replacement base URL instead.
+func (c *Client) WithEndpoints(overrides map[string]string) (*Client, error) {
+	if len(overrides) == 0 {
+		return c, nil
+	}
+
+	endpoints := make(map[string]*url.URL, len(c.endpoints)+len(overrides))
+	for k, v := range c.endpoints {
+		endpoints[k] = v
+	}
+
+	for defaultURL, override := range overrides {
+		from, err := ParseBaseURL(defaultURL)
+		if err != nil {
+			return nil, err
+		}
+		to, err := ParseBaseURL(override)
+		if err != nil {
+			return nil, err
+		}
+		endpoints[baseURLKey(from)] = to
+	}
+
+	clone := c.clone()
+	clone.endpoints = endpoints
+	clone.HTTPClient.Transport = clone.transport(clone.base)
+	return clone, nil
+}
+
+// WithNetworkSettings returns a copy of the client that sends its requests
+// through the given proxy, User-Agent and extra headers.
+func (c *Client) WithNetworkSettings(settings NetworkSettings) (*Client, error) {
+	if settings.ProxyURL != "" {
+		if _, err := ParseProxyURL(settings.ProxyURL); err != nil {
+			return nil, err
+		}
+	}
+
+	clone := c.clone()
+	clone.network = settings
+	clone.HTTPClient.Transport = clone.transport(clone.base)
+	return clone, nil
+}
+
+func (c *Client) clone() *Client {
+	clone := *c
+	clone.base = c.rawTransport()
+	return &clone
+}
+
+// rawTransport is the transport the chain is built on. Until a chain has
+// been built, that is whatever HTTPClient.Transport was set to.
+func (c *Client) rawTransport() http.RoundTripper {
+	if c.wrap == nil && len(c.endpoints) == 0 && c.network.isZero() {
+		return c.HTTPClient.Transport
+	}
+	return c.base
+}
+
+func (s NetworkSettings) isZero() bool {
+	return s.ProxyURL == "" && s.UserAgent == "" && len(s.Headers) == 0
+}
+
+// transport builds the round tripper chain on top of base: proxy, endpoint
+// overrides, fixture wrapper, then headers.
+func (c *Client) transport(base http.RoundTripper) http.RoundTripper {
+	rt := base
+	if c.network.ProxyURL != "" {
+		if proxy, err := ParseProxyURL(c.network.ProxyURL); err == nil {
+			rt = proxyTransport(rt, proxy)
+		}
+	}
+	if len(c.endpoints) > 0 {
+		rt = &endpointTransport{base: rt, endpoints: c.endpoints}
+	}
+	if c.wrap != nil {
+		rt = c.wrap(rt)
+	}
+	if c.network.UserAgent != "" || len(c.network.Headers) > 0 {
+		rt = &headerTransport{base: rt, userAgent: c.network.UserAgent, headers: c.network.Headers}
+	}
+	return rt
+}
+
+// proxyTransportKey identifies a transport cloned to go through a proxy.
+type proxyTransportKey struct {
+	base  *http.Transport
+	proxy string
+}
+
+var (
+	proxyTransportsMu sync.Mutex
+	proxyTransports   = map[proxyTransportKey]*http.Transport{}
+)
+
+// proxyTransport returns a transport that sends requests through proxy. One
+// transport is kept per base transport and proxy URL, so clients built for
+// every sync share its connection pool instead of leaking a new one each.
+func proxyTransport(rt http.RoundTripper, proxy *url.URL) http.RoundTripper {
+	base, ok := rt.(*http.Transport)
+	if !ok {
+		base = http.DefaultTransport.(*http.Transport)
+	}
+	key := proxyTransportKey{base: base, proxy: proxy.String()}
+
+	proxyTransportsMu.Lock()
+	defer proxyTransportsMu.Unlock()
+
+	if t, ok := proxyTransports[key]; ok {
+		return t
+	}
+	t := base.Clone()
+	t.Proxy = http.ProxyURL(proxy)
+	proxyTransports[key] = t
+	return t
+}
+
+// WrapHTTPClient returns a copy of hc that applies the client's endpoint
+// overrides and network settings, for fetchers that need their own
+// transport settings.
+func (c *Client) WrapHTTPClient(hc *http.Client) *http.Client {
+	wrapped := *hc
+	if len(c.endpoints) > 0 || c.wrap != nil || !c.network.isZero() {
+		wrapped.Transport = c.transport(hc.Transport)
+	}
+	return &wrapped
+}
+
+// OAuth2Context returns ctx carrying the client's HTTP client, so clients
+// built by the oauth2 and Google API packages use the source's endpoint
+// overrides and network settings, token requests included.
+func (c *Client) OAuth2Context(ctx context.Context) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, &c.HTTPClient)
+}
+
+// ResolveURL applies the client's endpoint overrides to rawURL. It is used
+// where requests do not go through HTTPClient, such as headless browsers.
+func (c *Client) ResolveURL(rawURL string) string {
+	if len(c.endpoints) == 0 {
+		return rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	target, ok := c.endpoints[baseURLKey(u)]
+	if !ok {
+		return rawURL
 	}
+	rewriteURL(u, target)
+	return u.String()
 }
@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package common
+
+import "strings"
+
+// DefaultBaseURLs lists the base URLs each network's fetcher talks to. Any
+// of them can be overridden per source, e.g. to use a mirror, an alternate
+// AppView, a recording proxy or a local stub server. Google services share
+// the OAuth token endpoint.
+var DefaultBaseURLs = map[string][]string{
+	"BadPups":               {"https://badpups.com"},
+	"Bluesky":               {"https://public.api.bsky.app", "https://bsky.social"},
+	"DeviantArt":            {"https://www.deviantart.com"},
+	"Discord":               {"https://discord.com"},
+	"e621":                  {"https://e621.net"},
+	"FurAffinity":           {"https://www.furaffinity.net"},
+	"FurTrack":              {"https://www.furtrack.com"},
+	"Google Analytics":      {"https://analyticsdata.googleapis.com", "https://oauth2.googleapis.com"},
+	"Google Search Console": {"https://www.googleapis.com", "https://oauth2.googleapis.com"},
+	"Instagram":             {"https://graph.facebook.com"},
+	"Murrtube":              {"https://murrtube.net"},
+	"Patreon":               {"https://www.patreon.com"},
+	"Reddit":                {"https://www.reddit.com", "https://oauth.reddit.com"},
+	"Telegram":              {"https://t.me"},
+	"Threads":               {"https://graph.threads.net"},
+	"TikTok":                {"https://www.tiktok.com"},
+	"Twitch":                {"https://api.twitch.tv", "https://id.twitch.tv"},
+	"Twitter":               {"https://twitter.com"},
+	"Weasyl":                {"https://www.weasyl.com"},
+	"YouTube":               {"https://youtube.googleapis.com", "https://youtubeanalytics.googleapis.com", "https://oauth2.googleapis.com"},
+}
+
+// SourceBaseURLs returns the base URLs a source talks to. Mastodon sources
+// talk to the instance named in their user@domain handle.
+func SourceBaseURLs(network, userName string) []string {
+	if network == "Mastodon" {
+		_, domain, ok := strings.Cut(userName, "@")
+		if !ok || domain == "" {
+			return nil
+		}
+		return []string{"https://" + strings.ToLower(domain)}
+	}
+	return DefaultBaseURLs[network]
+}
+
+// EndpointNetworks reports the networks whose base URLs can be overridden.
+func EndpointNetworks() map[string]bool {
+	networks := make(map[string]bool, len(DefaultBaseURLs)+1)
+	for network := range DefaultBaseURLs {
+		networks[network] = true
+	}
+	networks["Mastodon"] = true
+	return networks
+}
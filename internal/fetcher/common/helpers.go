@@ -4,6 +4,7 @@ package common
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"log"
 	"strings"
 	"time"
@@ -70,7 +71,8 @@ func SaveOrUpdateSourceStats(ctx context.Context, dbQueries *database.Queries, s
 		Date:     today,
 	})
 
-	var followersCount, followingCount, postsCount sql.NullInt64
+	var followersCount, followingCount, postsCount, karma sql.NullInt64
+	var followersDelta, followingDelta sql.NullInt64
 	var avgLikes, avgReposts, avgViews sql.NullFloat64
 
 	if stats.FollowersCount != nil {
@@ -91,6 +93,22 @@ func SaveOrUpdateSourceStats(ctx context.Context, dbQueries *database.Queries, s
 	if stats.AverageViews != nil {
 		avgViews = sql.NullFloat64{Float64: *stats.AverageViews, Valid: true}
 	}
+	if stats.Karma != nil {
+		karma = sql.NullInt64{Int64: int64(*stats.Karma), Valid: true}
+	}
+
+	previous, prevErr := dbQueries.GetLatestSourceStatBeforeDate(ctx, database.GetLatestSourceStatBeforeDateParams{
+		SourceID: sourceID,
+		Date:     today,
+	})
+	if prevErr == nil {
+		if followersCount.Valid && previous.FollowersCount.Valid {
+			followersDelta = sql.NullInt64{Int64: followersCount.Int64 - previous.FollowersCount.Int64, Valid: true}
+		}
+		if followingCount.Valid && previous.FollowingCount.Valid {
+			followingDelta = sql.NullInt64{Int64: followingCount.Int64 - previous.FollowingCount.Int64, Valid: true}
+		}
+	}
 
 	if err != nil {
 
@@ -104,6 +122,9 @@ func SaveOrUpdateSourceStats(ctx context.Context, dbQueries *database.Queries, s
 			AverageLikes:   avgLikes,
 			AverageReposts: avgReposts,
 			AverageViews:   avgViews,
+			Karma:          karma,
+			FollowersDelta: followersDelta,
+			FollowingDelta: followingDelta,
 		})
 		return err
 	}
@@ -115,6 +136,9 @@ func SaveOrUpdateSourceStats(ctx context.Context, dbQueries *database.Queries, s
 		AverageLikes:   avgLikes,
 		AverageReposts: avgReposts,
 		AverageViews:   avgViews,
+		Karma:          karma,
+		FollowersDelta: followersDelta,
+		FollowingDelta: followingDelta,
 		SourceID:       sourceID,
 		Date:           today,
 	})
@@ -126,6 +150,43 @@ func SaveOrUpdateSourceStats(ctx context.Context, dbQueries *database.Queries, s
 	return err
 }
 
+// SyncReactionBreakdown stores today's count for every reaction key of a post.
+// Keys are network specific: unicode emoji, custom emoji names or ids. Keys
+// that had a count before and are now gone are stored as zero.
+func SyncReactionBreakdown(ctx context.Context, dbQueries *database.Queries, postID uuid.UUID, counts map[string]int64) error {
+	previous, err := dbQueries.GetLatestReactionBreakdownForPost(ctx, postID)
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string]int64, len(counts))
+	for key, count := range counts {
+		if key != "" && count > 0 {
+			current[key] = count
+		}
+	}
+	for _, p := range previous {
+		if _, ok := current[p.ReactionKey]; !ok && p.Count > 0 {
+			current[p.ReactionKey] = 0
+		}
+	}
+
+	now := time.Now()
+	for key, count := range current {
+		err := dbQueries.SyncReactionBreakdown(ctx, database.SyncReactionBreakdownParams{
+			ID:          uuid.New(),
+			SyncedAt:    now,
+			PostID:      postID,
+			ReactionKey: key,
+			Count:       count,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func CalculateAverageStats(ctx context.Context, dbQueries *database.Queries, sourceID uuid.UUID) (*ProfileStats, error) {
 	totals, err := dbQueries.GetSourceTotals(ctx, sourceID)
 	if err != nil {
@@ -160,6 +221,16 @@ func sanitizeText(s string) string {
 	}, s)
 }
 
+const (
+	importedPostPrefix      = "archive-"
+	importedPostMatchWindow = time.Minute
+)
+
+// ErrMissingPostDate is returned by CreateOrUpdatePost when a post seen for
+// the first time has no posted date. Known posts keep their stored date, so
+// scrapers pass a zero time when the date could not be parsed.
+var ErrMissingPostDate = errors.New("post has no posted date")
+
 func CreateOrUpdatePost(
 	ctx context.Context,
 	dbQueries *database.Queries,
@@ -178,6 +249,31 @@ func CreateOrUpdatePost(
 		SourceID:          sourceID,
 	})
 
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, err
+	}
+
+	if err != nil && createdAt.IsZero() {
+		return uuid.Nil, ErrMissingPostDate
+	}
+
+	if err != nil && !strings.HasPrefix(networkInternalID, importedPostPrefix) {
+		// Archives without network IDs (Instagram) store posts under a
+		// placeholder ID. The first live post at the same time takes it over
+		// instead of being stored twice.
+		adoptedID, adoptErr := dbQueries.AdoptImportedPost(ctx, database.AdoptImportedPostParams{
+			NetworkInternalID: networkInternalID,
+			SourceID:          sourceID,
+			FromTime:          createdAt.Add(-importedPostMatchWindow),
+			ToTime:            createdAt.Add(importedPostMatchWindow),
+		})
+		if adoptErr == nil {
+			post.ID, err = adoptedID, nil
+		} else if !errors.Is(adoptErr, sql.ErrNoRows) {
+			return uuid.Nil, adoptErr
+		}
+	}
+
 	if err != nil {
 		newPost, err := dbQueries.CreatePost(ctx, database.CreatePostParams{
 			ID:                uuid.New(),
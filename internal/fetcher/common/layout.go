@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/google/uuid"
+)
+
+// ErrLayoutChanged is wrapped by errors from HTML scrapers whose selectors no
+// longer match the site's markup. Syncs failing with it are not retried.
+var ErrLayoutChanged = errors.New("layout changed")
+
+const (
+	MetricLikes = "likes"
+	MetricViews = "views"
+
+	// minLayoutSamples is how many pages a run must parse before a metric
+	// that is zero on all of them is blamed on the parser.
+	minLayoutSamples = 3
+)
+
+// Layout identifies an HTML scraper's set of selectors. Bump Version whenever
+// the selectors are updated for new markup.
+type Layout struct {
+	Network string
+	Version int
+}
+
+// LayoutCheck collects what a scraper parsed during one run, so the run can
+// be rejected before anything is written when the results look like the
+// markup changed: a required element missing from most pages, or a count
+// that is zero on every page although the source's history says otherwise.
+type LayoutCheck struct {
+	layout  Layout
+	pages   int
+	missing map[string]int
+	metrics map[string]bool
+}
+
+// Errorf reports markup the parser does not understand.
+func (l Layout) Errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s parser v%d: %s", ErrLayoutChanged, l.Network, l.Version, fmt.Sprintf(format, args...))
+}
+
+func (l Layout) NewCheck() *LayoutCheck {
+	return &LayoutCheck{
+		layout:  l,
+		missing: make(map[string]int),
+		metrics: make(map[string]bool),
+	}
+}
+
+// Page records that one more page was parsed.
+func (c *LayoutCheck) Page() {
+	c.pages++
+}
+
+// Missing records that a required field could not be found on the current
+// page.
+func (c *LayoutCheck) Missing(field string) {
+	c.missing[field]++
+}
+
+// Metric records a count parsed from the current page.
+func (c *LayoutCheck) Metric(name string, value int) {
+	c.metrics[name] = c.metrics[name] || value != 0
+}
+
+// Err reports whether the run looks broken.
+func (c *LayoutCheck) Err(ctx context.Context, dbQueries *database.Queries, sourceID uuid.UUID) error {
+	if c.pages == 0 {
+		return nil
+	}
+
+	fields := make([]string, 0, len(c.missing))
+	for field := range c.missing {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		if n := c.missing[field]; n*2 > c.pages {
+			return c.layout.Errorf("%s not found on %d of %d pages", field, n, c.pages)
+		}
+	}
+
+	if c.pages < minLayoutSamples {
+		return nil
+	}
+
+	var zero []string
+	for name, nonZero := range c.metrics {
+		if !nonZero {
+			zero = append(zero, name)
+		}
+	}
+	if len(zero) == 0 {
+		return nil
+	}
+	sort.Strings(zero)
+
+	maxima, err := dbQueries.GetSourceReactionMaxima(ctx, sourceID)
+	if err != nil {
+		return err
+	}
+	for _, name := range zero {
+		var previous int64
+		switch name {
+		case MetricLikes:
+			previous = maxima.MaxLikes
+		case MetricViews:
+			previous = maxima.MaxViews
+		}
+		if previous > 0 {
+			return c.layout.Errorf("%s are 0 on all %d pages, previously up to %d", name, c.pages, previous)
+		}
+	}
+
+	return nil
+}
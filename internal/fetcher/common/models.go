@@ -40,4 +40,5 @@ type ProfileStats struct {
 	AverageLikes   *float64
 	AverageReposts *float64
 	AverageViews   *float64
+	Karma          *int
 }
@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package cookies
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// SetAll loads a jar into a headless browser tab.
+func SetAll(jar []Cookie) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		for _, c := range jar {
+			params := network.SetCookie(c.Name, c.Value).
+				WithDomain(c.Domain).
+				WithPath(c.Path).
+				WithSecure(c.Secure).
+				WithHTTPOnly(c.HTTPOnly)
+
+			switch c.SameSite {
+			case "no_restriction":
+				params = params.WithSameSite(network.CookieSameSiteNone)
+			case "lax":
+				params = params.WithSameSite(network.CookieSameSiteLax)
+			case "strict":
+				params = params.WithSameSite(network.CookieSameSiteStrict)
+			}
+
+			if !c.Expires.IsZero() {
+				ts := cdp.TimeSinceEpoch(c.Expires)
+				params = params.WithExpires(&ts)
+			}
+
+			if err := params.Do(ctx); err != nil {
+				return fmt.Errorf("failed to set cookie %s: %w", c.Name, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,222 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+// Package cookies reads, stores and checks the browser session cookies that
+// logged-in scrapers (Twitter, Murrtube, TikTok) sync with.
+package cookies
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/authhelp"
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/fetcher/common"
+	"github.com/google/uuid"
+)
+
+// ExpiryWarning is how long before a session cookie expires the dashboard
+// starts warning about it.
+const ExpiryWarning = 7 * 24 * time.Hour
+
+type Cookie struct {
+	Name     string
+	Value    string
+	Domain   string
+	Path     string
+	Secure   bool
+	HTTPOnly bool
+	// SameSite uses Cookie-Editor's names: "lax", "strict", "no_restriction"
+	// or empty when unspecified.
+	SameSite string
+	// Expires is zero for cookies that only last for the browser session.
+	Expires time.Time
+}
+
+type Network struct {
+	// Domains the network's cookies belong to. Imports drop everything else.
+	Domains []string
+	// Required cookies carry the login session.
+	Required []string
+	// CheckURL only answers logged-in users; anything else gets a redirect
+	// to a login page or an error status.
+	CheckURL string
+	// prepareCheck adds what CheckURL needs besides the cookies.
+	prepareCheck func(req *http.Request, jar []Cookie)
+	// loggedIn reports whether a successful CheckURL response belongs to a
+	// session. nil accepts every 200 response.
+	loggedIn func(body []byte) bool
+}
+
+// twitterWebBearer is the public token the Twitter web app sends with every
+// API request; the cookies say which user is calling.
+const twitterWebBearer = "AAAAAAAAAAAAAAAAAAAAANRILgAAAAAAnNwIzUejRCOuH5E6I8xnZz4puTs%3D1Zv7ttfk8LF81IUq16cHjhLTvJu4FA33AGWWjCpTnA"
+
+var Networks = map[string]Network{
+	"Murrtube": {
+		Domains:  []string{"murrtube.net"},
+		Required: []string{"_murrtube_ultra_session"},
+		CheckURL: "https://murrtube.net/settings",
+	},
+	"TikTok": {
+		Domains:  []string{"tiktok.com"},
+		Required: []string{"sessionid"},
+		CheckURL: "https://www.tiktok.com/passport/web/account/info/",
+		loggedIn: func(body []byte) bool {
+			return bytes.Contains(body, []byte(`"message":"success"`))
+		},
+	},
+	"Twitter": {
+		Domains:  []string{"twitter.com", "x.com"},
+		Required: []string{"auth_token"},
+		CheckURL: "https://x.com/i/api/1.1/account/settings.json",
+		prepareCheck: func(req *http.Request, jar []Cookie) {
+			req.Header.Set("Authorization", "Bearer "+twitterWebBearer)
+			for _, c := range jar {
+				if c.Name == "ct0" {
+					req.Header.Set("X-Csrf-Token", c.Value)
+				}
+			}
+		},
+		loggedIn: func(body []byte) bool {
+			return bytes.Contains(body, []byte(`"screen_name"`))
+		},
+	},
+}
+
+func IsSupported(network string) bool {
+	_, ok := Networks[network]
+	return ok
+}
+
+// Filter keeps the cookies that belong to the network's domains.
+func Filter(network string, jar []Cookie) []Cookie {
+	n, ok := Networks[network]
+	if !ok {
+		return jar
+	}
+
+	var out []Cookie
+	for _, c := range jar {
+		domain := strings.TrimPrefix(strings.ToLower(c.Domain), ".")
+		for _, d := range n.Domains {
+			if domain == d || strings.HasSuffix(domain, "."+d) {
+				out = append(out, c)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// Validate reports a missing session cookie.
+func Validate(network string, jar []Cookie) error {
+	for _, name := range Networks[network].Required {
+		if !slices.ContainsFunc(jar, func(c Cookie) bool { return c.Name == name }) {
+			return fmt.Errorf("cookies must contain a %s cookie", name)
+		}
+	}
+	return nil
+}
+
+// IsRequired reports whether the named cookie carries the network's session.
+func IsRequired(network, name string) bool {
+	return slices.Contains(Networks[network].Required, name)
+}
+
+// SessionExpiry returns when the first of the network's session cookies
+// expires. ok is false when none of them has an expiry date.
+func SessionExpiry(network string, jar []Cookie) (expires time.Time, ok bool) {
+	for _, c := range jar {
+		if !IsRequired(network, c.Name) || c.Expires.IsZero() {
+			continue
+		}
+		if !ok || c.Expires.Before(expires) {
+			expires = c.Expires
+			ok = true
+		}
+	}
+	return expires, ok
+}
+
+// Test requests the network's login-only endpoint with the jar and reports
+// whether the session is still accepted.
+func Test(ctx context.Context, c *common.Client, networkName string, jar []Cookie) error {
+	n, ok := Networks[networkName]
+	if !ok {
+		return fmt.Errorf("cookie checks are not supported for %s", networkName)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", n.CheckURL, nil)
+	if err != nil {
+		return err
+	}
+	// The jar only holds the network's cookies, so all of them are sent,
+	// whichever of the network's domains they were saved for.
+	for _, cookie := range jar {
+		req.AddCookie(&http.Cookie{Name: cookie.Name, Value: cookie.Value})
+	}
+	if n.prepareCheck != nil {
+		n.prepareCheck(req, jar)
+	}
+
+	// A redirect means the session was sent to a login page, so it is
+	// reported rather than followed.
+	hc := c.HTTPClient
+	hc.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", n.CheckURL, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", n.CheckURL, err)
+	}
+
+	switch {
+	case resp.StatusCode >= 300 && resp.StatusCode < 400:
+		return fmt.Errorf("session rejected: redirected to %s", resp.Header.Get("Location"))
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("session rejected: %s returned status %d", n.CheckURL, resp.StatusCode)
+	case n.loggedIn != nil && !n.loggedIn(body):
+		return fmt.Errorf("session rejected: %s did not return the logged-in account", n.CheckURL)
+	}
+	return nil
+}
+
+// Load reads a source's stored cookie jar.
+func Load(ctx context.Context, dbQueries *database.Queries, encryptionKey []byte, sourceID uuid.UUID) ([]Cookie, error) {
+	data, _, _, _, err := authhelp.GetSourceToken(ctx, dbQueries, encryptionKey, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	jar, _, err := Parse([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse stored cookies: %w", err)
+	}
+	return jar, nil
+}
+
+// Store replaces a source's cookie jar, encrypted in the tokens table.
+func Store(ctx context.Context, dbQueries *database.Queries, encryptionKey []byte, sourceID uuid.UUID, jar []Cookie) error {
+	if len(jar) == 0 {
+		return errors.New("no cookies to store")
+	}
+
+	data, err := Marshal(jar)
+	if err != nil {
+		return err
+	}
+	return authhelp.ReplaceSourceToken(ctx, dbQueries, encryptionKey, sourceID, string(data))
+}
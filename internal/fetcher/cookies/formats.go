@@ -0,0 +1,319 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package cookies
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+const (
+	FormatJSON     = "JSON"
+	FormatNetscape = "Netscape cookies.txt"
+	FormatHAR      = "HAR"
+)
+
+// jsonCookie covers both Cookie-Editor exports (expirationDate) and Chrome
+// DevTools cookies (expires, -1 for session cookies).
+type jsonCookie struct {
+	Name           string   `json:"name"`
+	Value          string   `json:"value"`
+	Domain         string   `json:"domain"`
+	Path           string   `json:"path"`
+	Secure         bool     `json:"secure"`
+	HTTPOnly       bool     `json:"httpOnly"`
+	SameSite       *string  `json:"sameSite,omitempty"`
+	ExpirationDate *float64 `json:"expirationDate,omitempty"`
+	Expires        *float64 `json:"expires,omitempty"`
+	Session        bool     `json:"session"`
+	HostOnly       bool     `json:"hostOnly"`
+}
+
+type harFile struct {
+	Log struct {
+		Entries []struct {
+			Request struct {
+				URL     string      `json:"url"`
+				Cookies []harCookie `json:"cookies"`
+			} `json:"request"`
+			Response struct {
+				Cookies []harCookie `json:"cookies"`
+			} `json:"response"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+type harCookie struct {
+	Name     string  `json:"name"`
+	Value    string  `json:"value"`
+	Domain   string  `json:"domain"`
+	Path     string  `json:"path"`
+	Expires  *string `json:"expires"`
+	HTTPOnly bool    `json:"httpOnly"`
+	Secure   bool    `json:"secure"`
+	SameSite string  `json:"sameSite"`
+}
+
+// Parse reads a cookie export in any supported format: a JSON array from
+// Cookie-Editor or Chrome DevTools, a Netscape cookies.txt file, or a HAR
+// capture. It returns the name of the detected format.
+func Parse(data []byte) ([]Cookie, string, error) {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	if len(trimmed) == 0 {
+		return nil, "", errors.New("cookie file is empty")
+	}
+
+	switch trimmed[0] {
+	case '[':
+		jar, err := parseJSON(trimmed)
+		return jar, FormatJSON, err
+	case '{':
+		jar, err := parseHAR(trimmed)
+		return jar, FormatHAR, err
+	default:
+		jar, err := parseNetscape(trimmed)
+		return jar, FormatNetscape, err
+	}
+}
+
+func parseJSON(data []byte) ([]Cookie, error) {
+	var raw []jsonCookie
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid cookie JSON: %w", err)
+	}
+
+	jar := make([]Cookie, 0, len(raw))
+	for _, r := range raw {
+		if r.Name == "" {
+			continue
+		}
+		c := Cookie{
+			Name:     r.Name,
+			Value:    r.Value,
+			Domain:   r.Domain,
+			Path:     r.Path,
+			Secure:   r.Secure,
+			HTTPOnly: r.HTTPOnly,
+		}
+		if r.SameSite != nil {
+			c.SameSite = normalizeSameSite(*r.SameSite)
+		}
+		switch {
+		case r.ExpirationDate != nil && !r.Session:
+			c.Expires = unixTime(*r.ExpirationDate)
+		case r.Expires != nil && *r.Expires > 0 && !r.Session:
+			c.Expires = unixTime(*r.Expires)
+		}
+		jar = append(jar, c)
+	}
+
+	if len(jar) == 0 {
+		return nil, errors.New("cookie JSON contains no cookies")
+	}
+	return jar, nil
+}
+
+func parseNetscape(data []byte) ([]Cookie, error) {
+	var jar []Cookie
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		httpOnly := false
+		if rest, ok := strings.CutPrefix(line, "#HttpOnly_"); ok {
+			line = rest
+			httpOnly = true
+		}
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) < 6 {
+			return nil, fmt.Errorf("invalid cookies.txt line: expected 7 tab-separated fields, got %d", len(fields))
+		}
+		value := ""
+		if len(fields) > 6 {
+			value = fields[6]
+		}
+
+		c := Cookie{
+			Name:     fields[5],
+			Value:    value,
+			Domain:   fields[0],
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			HTTPOnly: httpOnly,
+		}
+		if ts, err := strconv.ParseFloat(fields[4], 64); err == nil && ts > 0 {
+			c.Expires = unixTime(ts)
+		}
+		jar = append(jar, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(jar) == 0 {
+		return nil, errors.New("cookies.txt contains no cookies")
+	}
+	return jar, nil
+}
+
+// parseHAR collects the cookies sent and set across a HAR capture. Cookies
+// set by a response win over ones only seen in requests, and later entries
+// win over earlier ones.
+func parseHAR(data []byte) ([]Cookie, error) {
+	var har harFile
+	if err := json.Unmarshal(data, &har); err != nil {
+		return nil, fmt.Errorf("invalid HAR file: %w", err)
+	}
+
+	type key struct{ name, domain string }
+	byKey := make(map[key]int)
+	fromResponse := make(map[key]bool)
+	var jar []Cookie
+
+	add := func(c Cookie, response bool) {
+		k := key{c.Name, strings.TrimPrefix(strings.ToLower(c.Domain), ".")}
+		if i, ok := byKey[k]; ok {
+			if fromResponse[k] && !response {
+				return
+			}
+			jar[i] = c
+		} else {
+			byKey[k] = len(jar)
+			jar = append(jar, c)
+		}
+		if response {
+			fromResponse[k] = true
+		}
+	}
+
+	for _, e := range har.Log.Entries {
+		host := ""
+		if u, err := url.Parse(e.Request.URL); err == nil {
+			host = u.Hostname()
+		}
+
+		for _, hc := range e.Request.Cookies {
+			if hc.Name == "" || host == "" {
+				continue
+			}
+			add(Cookie{Name: hc.Name, Value: hc.Value, Domain: host, Path: "/", Secure: true}, false)
+		}
+
+		for _, hc := range e.Response.Cookies {
+			if hc.Name == "" {
+				continue
+			}
+			c := Cookie{
+				Name:     hc.Name,
+				Value:    hc.Value,
+				Domain:   hc.Domain,
+				Path:     hc.Path,
+				Secure:   hc.Secure,
+				HTTPOnly: hc.HTTPOnly,
+				SameSite: normalizeSameSite(hc.SameSite),
+			}
+			if c.Domain == "" {
+				c.Domain = host
+			}
+			if c.Path == "" {
+				c.Path = "/"
+			}
+			if hc.Expires != nil && *hc.Expires != "" {
+				if t, err := time.Parse(time.RFC3339, *hc.Expires); err == nil {
+					c.Expires = t
+				}
+			}
+			add(c, true)
+		}
+	}
+
+	if len(jar) == 0 {
+		return nil, errors.New("HAR file contains no cookies")
+	}
+	return jar, nil
+}
+
+// Marshal encodes a jar as Cookie-Editor JSON, the format the scrapers and
+// the source form read.
+func Marshal(jar []Cookie) ([]byte, error) {
+	out := make([]jsonCookie, 0, len(jar))
+	for _, c := range jar {
+		jc := jsonCookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Secure:   c.Secure,
+			HTTPOnly: c.HTTPOnly,
+			Session:  c.Expires.IsZero(),
+			HostOnly: !strings.HasPrefix(c.Domain, "."),
+		}
+		if c.SameSite != "" {
+			sameSite := c.SameSite
+			jc.SameSite = &sameSite
+		}
+		if !c.Expires.IsZero() {
+			ts := float64(c.Expires.Unix())
+			jc.ExpirationDate = &ts
+		}
+		out = append(out, jc)
+	}
+	return json.Marshal(out)
+}
+
+// FromCDP converts cookies read from a headless browser.
+func FromCDP(cdpCookies []*network.Cookie) []Cookie {
+	jar := make([]Cookie, 0, len(cdpCookies))
+	for _, nc := range cdpCookies {
+		c := Cookie{
+			Name:     nc.Name,
+			Value:    nc.Value,
+			Domain:   nc.Domain,
+			Path:     nc.Path,
+			Secure:   nc.Secure,
+			HTTPOnly: nc.HTTPOnly,
+			SameSite: normalizeSameSite(nc.SameSite.String()),
+		}
+		if !nc.Session && nc.Expires > 0 {
+			c.Expires = unixTime(nc.Expires)
+		}
+		jar = append(jar, c)
+	}
+	return jar
+}
+
+func normalizeSameSite(s string) string {
+	switch strings.ToLower(s) {
+	case "lax":
+		return "lax"
+	case "strict":
+		return "strict"
+	case "none", "no_restriction":
+		return "no_restriction"
+	default:
+		return ""
+	}
+}
+
+func unixTime(ts float64) time.Time {
+	if math.IsInf(ts, 0) || math.IsNaN(ts) {
+		return time.Time{}
+	}
+	sec, frac := math.Modf(ts)
+	return time.Unix(int64(sec), int64(frac*1e9)).UTC()
+}
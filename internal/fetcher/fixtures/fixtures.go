@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+// Package fixtures records fetcher traffic and replays it from a local stub
+// server, so fetchers can be run end-to-end without reaching the networks.
+//
+// A stub server serves every recorded host under its own path prefix: point
+// a source's endpoint override for https://www.weasyl.com at
+// http://localhost:8089/www.weasyl.com to replay the Weasyl fixtures.
+package fixtures
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Wildcard matches any value in a fixture's path segment or query parameter.
+const Wildcard = "*"
+
+type Request struct {
+	Method string            `json:"method"`
+	Host   string            `json:"host"`
+	Path   string            `json:"path"`
+	Query  map[string]string `json:"query,omitempty"`
+}
+
+type Response struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body holds JSON responses verbatim; BodyText is used for everything else.
+	Body     json.RawMessage `json:"body,omitempty"`
+	BodyText string          `json:"body_text,omitempty"`
+}
+
+type Fixture struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+
+	file string
+}
+
+// ManifestFile names the sources a fixture directory can sync. Fixtures
+// themselves live one directory per host, so files directly in dir are not
+// loaded as fixtures.
+const ManifestFile = "sources.json"
+
+// Load reads every *.json fixture in the host directories below dir.
+func Load(dir string) ([]Fixture, error) {
+	var fixtures []Fixture
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(p) != ".json" || filepath.Dir(p) == filepath.Clean(dir) {
+			return nil
+		}
+
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		var f Fixture
+		if err := json.Unmarshal(data, &f); err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		if f.Request.Method == "" {
+			f.Request.Method = "GET"
+		}
+		if f.Response.Status == 0 {
+			f.Response.Status = 200
+		}
+		f.file = p
+		fixtures = append(fixtures, f)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return fixtures, nil
+}
+
+// matchScore reports how specifically f matches the request, or -1 when it
+// does not match at all.
+func (f *Fixture) matchScore(method, host, reqPath string, query map[string][]string) int {
+	if !strings.EqualFold(f.Request.Method, method) || !strings.EqualFold(f.Request.Host, host) {
+		return -1
+	}
+
+	score := 0
+	if f.Request.Path == reqPath {
+		score++
+	} else if ok, _ := path.Match(f.Request.Path, reqPath); !ok {
+		return -1
+	}
+
+	for key, want := range f.Request.Query {
+		got, ok := query[key]
+		if !ok || len(got) == 0 {
+			return -1
+		}
+		if want == Wildcard {
+			score += 2
+			continue
+		}
+		if got[0] != want {
+			return -1
+		}
+		score += 3
+	}
+
+	return score
+}
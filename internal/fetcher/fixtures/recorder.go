@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// secretQueryParams are replaced by Wildcard when recording, so credentials
+// never end up in fixture files.
+var secretQueryParams = map[string]bool{
+	"access_token":  true,
+	"api_key":       true,
+	"client_secret": true,
+	"key":           true,
+	"password":      true,
+	"token":         true,
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// Recorder is an http.RoundTripper that saves every response it sees as a
+// fixture below dir, one directory per host.
+type Recorder struct {
+	dir   string
+	base  http.RoundTripper
+	count *atomic.Int64
+}
+
+func NewRecorder(dir string, base http.RoundTripper) *Recorder {
+	return &Recorder{dir: dir, base: base, count: new(atomic.Int64)}
+}
+
+// WithBase returns a recorder on top of another transport that numbers its
+// fixtures in the same sequence as r.
+func (r *Recorder) WithBase(base http.RoundTripper) *Recorder {
+	return &Recorder{dir: r.dir, base: base, count: r.count}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := r.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err := r.save(req, resp, body); err != nil {
+		log.Printf("Fixtures: failed to record %s: %v", req.URL.Redacted(), err)
+	}
+
+	return resp, nil
+}
+
+func (r *Recorder) save(req *http.Request, resp *http.Response, body []byte) error {
+	query := make(map[string]string)
+	for key, values := range req.URL.Query() {
+		if len(values) == 0 {
+			continue
+		}
+		if secretQueryParams[strings.ToLower(key)] {
+			query[key] = Wildcard
+		} else {
+			query[key] = values[0]
+		}
+	}
+
+	f := Fixture{
+		Request: Request{
+			Method: req.Method,
+			Host:   req.URL.Host,
+			Path:   req.URL.Path,
+			Query:  query,
+		},
+		Response: Response{
+			Status: resp.StatusCode,
+		},
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType != "" {
+		f.Response.Headers = map[string]string{"Content-Type": contentType}
+	}
+	if strings.Contains(contentType, "json") && json.Valid(body) {
+		f.Response.Body = body
+	} else {
+		f.Response.BodyText = string(body)
+	}
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	n := r.count.Add(1)
+
+	hostDir := filepath.Join(r.dir, unsafeFileChars.ReplaceAllString(req.URL.Host, "_"))
+	if err := os.MkdirAll(hostDir, 0o755); err != nil {
+		return err
+	}
+
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(req.URL.Path, "_"), "_")
+	if len(name) > 80 {
+		name = name[:80]
+	}
+	return os.WriteFile(filepath.Join(hostDir, fmt.Sprintf("%04d-%s.json", n, name)), data, 0o644)
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package fixtures
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// Server replays fixtures. The first path segment of each request names the
+// host the fixture was recorded for.
+type Server struct {
+	fixtures []Fixture
+}
+
+func NewServer(fixtures []Fixture) *Server {
+	return &Server{fixtures: fixtures}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	trimmed := strings.TrimPrefix(r.URL.Path, "/")
+	host, rest, _ := strings.Cut(trimmed, "/")
+	reqPath := "/" + rest
+	query := r.URL.Query()
+
+	var best *Fixture
+	bestScore := -1
+	for i := range s.fixtures {
+		if score := s.fixtures[i].matchScore(r.Method, host, reqPath, query); score > bestScore {
+			best = &s.fixtures[i]
+			bestScore = score
+		}
+	}
+
+	if best == nil {
+		log.Printf("Fixtures: no fixture for %s %s%s?%s", r.Method, host, reqPath, r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"error": "no fixture for " + r.Method + " " + host + reqPath,
+		})
+		return
+	}
+
+	log.Printf("Fixtures: %s %s%s -> %s", r.Method, host, reqPath, best.file)
+
+	for k, v := range best.Response.Headers {
+		w.Header().Set(k, v)
+	}
+	if len(best.Response.Body) > 0 && w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(best.Response.Status)
+
+	if len(best.Response.Body) > 0 {
+		_, _ = w.Write(best.Response.Body)
+	} else {
+		_, _ = w.Write([]byte(best.Response.BodyText))
+	}
+}
@@ -0,0 +1,27 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package fetcher
+
+import (
+	"context"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/fetcher/common"
+	"github.com/fluffyriot/rpsync/internal/fetcher/sources"
+)
+
+var LiveNetworks = []string{"Twitch"}
+
+func PollLiveBySource(source database.Source, dbQueries *database.Queries, c *common.Client, encryptionKey []byte) (bool, error) {
+	c, err := ClientForSource(context.Background(), dbQueries, c, encryptionKey, source.ID)
+	if err != nil {
+		return false, err
+	}
+
+	switch source.Network {
+	case "Twitch":
+		return sources.PollTwitchLive(dbQueries, encryptionKey, source.ID, c)
+
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package fetcher
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/fluffyriot/rpsync/internal/authhelp"
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/fetcher/common"
+	"github.com/google/uuid"
+)
+
+// sealedNetworkSettings is the encrypted part of a source's network
+// settings: the proxy URL may hold credentials and headers often hold keys.
+type sealedNetworkSettings struct {
+	ProxyURL string            `json:"proxy_url"`
+	Headers  map[string]string `json:"headers"`
+}
+
+// ClientForSource returns the shared client with the source's endpoint
+// overrides and network settings applied.
+func ClientForSource(ctx context.Context, dbQueries *database.Queries, c *common.Client, encryptionKey []byte, sourceID uuid.UUID) (*common.Client, error) {
+	endpoints, err := dbQueries.GetSourceEndpoints(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(endpoints) > 0 {
+		overrides := make(map[string]string, len(endpoints))
+		for _, e := range endpoints {
+			overrides[e.DefaultBaseUrl] = e.BaseUrl
+		}
+
+		c, err = c.WithEndpoints(overrides)
+		if err != nil {
+			return nil, fmt.Errorf("invalid endpoint override: %w", err)
+		}
+	}
+
+	settings, err := LoadNetworkSettings(ctx, dbQueries, encryptionKey, sourceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c, err = c.WithNetworkSettings(settings)
+	if err != nil {
+		return nil, fmt.Errorf("invalid network settings: %w", err)
+	}
+	return c, nil
+}
+
+// LoadNetworkSettings reads and decrypts a source's network settings. It
+// returns sql.ErrNoRows when the source has none.
+func LoadNetworkSettings(ctx context.Context, dbQueries *database.Queries, encryptionKey []byte, sourceID uuid.UUID) (common.NetworkSettings, error) {
+	row, err := dbQueries.GetSourceNetworkSettings(ctx, sourceID)
+	if err != nil {
+		return common.NetworkSettings{}, err
+	}
+
+	plain, err := authhelp.DecryptSecret(row.EncryptedSettings, row.Nonce, encryptionKey)
+	if err != nil {
+		return common.NetworkSettings{}, fmt.Errorf("failed to decrypt network settings: %w", err)
+	}
+
+	var sealed sealedNetworkSettings
+	if err := json.Unmarshal([]byte(plain), &sealed); err != nil {
+		return common.NetworkSettings{}, fmt.Errorf("invalid network settings: %w", err)
+	}
+
+	return common.NetworkSettings{
+		ProxyURL:  sealed.ProxyURL,
+		UserAgent: row.UserAgent,
+		Headers:   sealed.Headers,
+	}, nil
+}
+
+// SaveNetworkSettings stores a source's network settings, encrypting the
+// proxy URL and extra headers.
+func SaveNetworkSettings(ctx context.Context, dbQueries *database.Queries, encryptionKey []byte, sourceID uuid.UUID, settings common.NetworkSettings) error {
+	headers := settings.Headers
+	if headers == nil {
+		headers = map[string]string{}
+	}
+
+	plain, err := json.Marshal(sealedNetworkSettings{ProxyURL: settings.ProxyURL, Headers: headers})
+	if err != nil {
+		return err
+	}
+
+	encrypted, nonce, err := authhelp.EncryptSecret(string(plain), encryptionKey)
+	if err != nil {
+		return err
+	}
+
+	return dbQueries.UpsertSourceNetworkSettings(ctx, database.UpsertSourceNetworkSettingsParams{
+		SourceID:          sourceID,
+		UserAgent:         settings.UserAgent,
+		EncryptedSettings: encrypted,
+		Nonce:             nonce,
+	})
+}
@@ -20,6 +20,17 @@ import (
 	"github.com/google/uuid"
 )
 
+// badpupsLayout versions the selectors below.
+var badpupsLayout = common.Layout{Network: "BadPups", Version: 1}
+
+type badpupsVideo struct {
+	ID         string
+	UploadTime time.Time
+	Content    string
+	Likes      sql.NullInt64
+	Views      sql.NullInt64
+}
+
 type VideoObjectLD struct {
 	Type        string `json:"@type"`
 	Name        string `json:"name"`
@@ -161,6 +172,9 @@ func FetchBadpupsPosts(uid uuid.UUID, dbQueries *database.Queries, c *common.Cli
 	linkPattern := regexp.MustCompile(`^https?://[^/]+/lite/video/[^/]+$`)
 	viewsRe := regexp.MustCompile(`([\d,]+)\s+views`)
 
+	check := badpupsLayout.NewCheck()
+	var videos []badpupsVideo
+
 	doc.Find("a").Each(func(_ int, s *goquery.Selection) {
 		href, exists := s.Attr("href")
 		if !exists || !linkPattern.MatchString(href) {
@@ -200,8 +214,11 @@ func FetchBadpupsPosts(uid uuid.UUID, dbQueries *database.Queries, c *common.Cli
 			return
 		}
 
+		check.Page()
+
 		videoLD, err := extractVideoObjectLD(videoDoc)
 		if err != nil {
+			check.Missing("video metadata")
 			return
 		}
 
@@ -210,7 +227,7 @@ func FetchBadpupsPosts(uid uuid.UUID, dbQueries *database.Queries, c *common.Cli
 
 		uploadTime, err := time.Parse(time.RFC3339, videoLD.UploadDate)
 		if err != nil {
-			uploadTime = time.Now()
+			check.Missing("upload date")
 		}
 
 		var categories []string
@@ -233,72 +250,79 @@ func FetchBadpupsPosts(uid uuid.UUID, dbQueries *database.Queries, c *common.Cli
 			content += "\n\n" + hashtags
 		}
 
-		postID, err := common.CreateOrUpdatePost(
-			context.Background(),
-			dbQueries,
-			sourceId,
-			id,
-			"BadPups",
-			uploadTime,
-			"video",
-			username,
-			content,
-		)
-		if err != nil {
-			return
+		likes, likesErr := strconv.Atoi(strings.TrimSpace(videoDoc.Find("span.likes_count").First().Text()))
+		if likesErr != nil {
+			check.Missing("likes")
+		} else {
+			check.Metric(common.MetricLikes, likes)
 		}
 
-		likesText := strings.TrimSpace(
-			videoDoc.Find("span.likes_count").First().Text(),
-		)
-
-		dislikesText := strings.TrimSpace(
-			videoDoc.Find("span.dislikes_count").First().Text(),
-		)
-
-		likes := 0
 		dislikes := 0
-
-		if likesText != "" {
-			likes, _ = strconv.Atoi(likesText)
-		}
-
-		if dislikesText != "" {
+		if dislikesText := strings.TrimSpace(videoDoc.Find("span.dislikes_count").First().Text()); dislikesText != "" {
 			dislikes, _ = strconv.Atoi(dislikesText)
 		}
 
-		videoLikes := likes - dislikes
-
 		videoViews := 0
+		viewsErr := errors.New("views not found")
 		videoDoc.Find("div span").EachWithBreak(func(_ int, s *goquery.Selection) bool {
 			if m := viewsRe.FindStringSubmatch(s.Text()); m != nil {
-				videoViews, _ = strconv.Atoi(strings.ReplaceAll(m[1], ",", ""))
+				videoViews, viewsErr = strconv.Atoi(strings.ReplaceAll(m[1], ",", ""))
 				return false
 			}
 			return true
 		})
+		if viewsErr != nil {
+			check.Missing("views")
+		} else {
+			check.Metric(common.MetricViews, videoViews)
+		}
+
+		videos = append(videos, badpupsVideo{
+			ID:         id,
+			UploadTime: uploadTime,
+			Content:    content,
+			Likes:      sql.NullInt64{Int64: int64(likes - dislikes), Valid: likesErr == nil},
+			Views:      sql.NullInt64{Int64: int64(videoViews), Valid: viewsErr == nil},
+		})
+	})
 
-		_, err = dbQueries.SyncReactions(context.Background(), database.SyncReactionsParams{
+	if len(processedLinks) == 0 {
+		return errors.New("No content found")
+	}
+
+	if err := check.Err(context.Background(), dbQueries, sourceId); err != nil {
+		return err
+	}
+
+	for _, video := range videos {
+		postID, err := common.CreateOrUpdatePost(
+			context.Background(),
+			dbQueries,
+			sourceId,
+			video.ID,
+			"BadPups",
+			video.UploadTime,
+			"video",
+			username,
+			video.Content,
+		)
+		if err != nil {
+			log.Printf("BadPups: Failed to save video %s: %v", video.ID, err)
+			continue
+		}
+
+		if _, err := dbQueries.SyncReactions(context.Background(), database.SyncReactionsParams{
 			ID:       uuid.New(),
 			SyncedAt: time.Now(),
 			PostID:   postID,
-			Likes: sql.NullInt64{
-				Int64: int64(videoLikes),
-				Valid: true,
-			},
+			Likes:    video.Likes,
 			Reposts: sql.NullInt64{
 				Valid: false,
 			},
-			Views: sql.NullInt64{
-				Int64: int64(videoViews),
-				Valid: true,
-			},
-		})
-
-	})
-
-	if len(processedLinks) == 0 {
-		return errors.New("No content found")
+			Views: video.Views,
+		}); err != nil {
+			log.Printf("BadPups: Failed to sync reactions for video %s: %v", video.ID, err)
+		}
 	}
 
 	stats, err := common.CalculateAverageStats(context.Background(), dbQueries, sourceId)
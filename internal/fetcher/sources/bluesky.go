@@ -2,6 +2,7 @@
 package sources
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
@@ -10,9 +11,11 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
+	"github.com/fluffyriot/rpsync/internal/authhelp"
 	"github.com/fluffyriot/rpsync/internal/database"
 	"github.com/fluffyriot/rpsync/internal/fetcher/common"
 	"github.com/google/uuid"
@@ -20,6 +23,11 @@ import (
 	_ "github.com/lib/pq"
 )
 
+const (
+	bskyPublicAppView  = "https://public.api.bsky.app"
+	bskyDefaultService = "https://bsky.social"
+)
+
 type bskyFeed struct {
 	Feed []struct {
 		Post struct {
@@ -36,6 +44,11 @@ type bskyFeed struct {
 						Type string `json:"$type"`
 					} `json:"media"`
 				} `json:"embed"`
+				Reply *struct {
+					Parent struct {
+						URI string `json:"uri"`
+					} `json:"parent"`
+				} `json:"reply,omitempty"`
 				Text string `json:"text"`
 			} `json:"record"`
 			BookmarkCount int `json:"bookmarkCount"`
@@ -60,38 +73,128 @@ type bskyProfile struct {
 	PostsCount     int `json:"postsCount"`
 }
 
-func getBskyApiString(dbQueries *database.Queries, sourceId uuid.UUID, cursor string) (string, string, error) {
+// bskyOptions describes how a Bluesky source is read. Sources without stored
+// credentials use the public AppView and keep the original behaviour of
+// syncing reposts and quotes but not replies.
+type bskyOptions struct {
+	Service        string
+	AppPassword    string
+	IncludeReplies bool
+	IncludeReposts bool
+	IncludeQuotes  bool
+}
+
+type bskySession struct {
+	host      string
+	accessJwt string
+}
+
+func normalizeBskyHost(host string) string {
+	host = strings.TrimRight(strings.TrimSpace(host), "/")
+	if host != "" && !strings.HasPrefix(host, "http://") && !strings.HasPrefix(host, "https://") {
+		host = "https://" + host
+	}
+	return host
+}
+
+// parseBlueskyInclude reads a comma-separated list of optional post types.
+// An empty list keeps the defaults; "none" syncs original posts only.
+func parseBlueskyInclude(include string) (replies, reposts, quotes bool) {
+	include = strings.TrimSpace(strings.ToLower(include))
+	if include == "" {
+		return false, true, true
+	}
+
+	for _, part := range strings.Split(include, ",") {
+		switch strings.TrimSpace(part) {
+		case "replies":
+			replies = true
+		case "reposts":
+			reposts = true
+		case "quotes":
+			quotes = true
+		}
+	}
+	return replies, reposts, quotes
+}
 
-	username, err := dbQueries.GetSourceById(
-		context.Background(),
-		sourceId,
-	)
+func getBlueskyOptions(ctx context.Context, dbQueries *database.Queries, encryptionKey []byte, sourceId uuid.UUID) bskyOptions {
+	opts := bskyOptions{Service: bskyPublicAppView}
+	opts.IncludeReplies, opts.IncludeReposts, opts.IncludeQuotes = parseBlueskyInclude("")
 
+	accessToken, profileID, sourceAppData, _, err := authhelp.GetSourceToken(ctx, dbQueries, encryptionKey, sourceId)
 	if err != nil {
-		return "", "", err
+		return opts
 	}
 
-	apiString := fmt.Sprintf(
-		"https://public.api.bsky.app/xrpc/app.bsky.feed.getAuthorFeed?actor=%s&limit=100&filter=posts_no_replies",
-		username.UserName,
-	)
+	if accessToken != "" && accessToken != "public" {
+		opts.AppPassword = accessToken
+		opts.Service = bskyDefaultService
+	}
 
-	if cursor != "" {
-		apiString += "&cursor=" + cursor
+	if host := normalizeBskyHost(profileID); host != "" {
+		opts.Service = host
 	}
 
-	return apiString, username.UserName, nil
+	if include, ok := sourceAppData["include"].(string); ok {
+		opts.IncludeReplies, opts.IncludeReposts, opts.IncludeQuotes = parseBlueskyInclude(include)
+	}
 
+	return opts
 }
 
-func fetchBlueskyProfile(username string, c *common.Client) (*bskyProfile, error) {
+func createBlueskySession(opts bskyOptions, identifier string, c *common.Client) (*bskySession, error) {
+	if opts.AppPassword == "" {
+		return &bskySession{host: opts.Service}, nil
+	}
+
+	payload, err := json.Marshal(map[string]string{
+		"identifier": identifier,
+		"password":   opts.AppPassword,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", opts.Service+"/xrpc/com.atproto.server.createSession", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to create session: %v %v. Body: %s", resp.StatusCode, resp.Status, string(data))
+	}
+
+	var session struct {
+		AccessJwt string `json:"accessJwt"`
+	}
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, err
+	}
 
-	url := fmt.Sprintf("https://public.api.bsky.app/xrpc/app.bsky.actor.getProfile?actor=%s", username)
+	return &bskySession{host: opts.Service, accessJwt: session.AccessJwt}, nil
+}
 
-	req, err := http.NewRequest("GET", url, nil)
+func (s *bskySession) get(method string, params url.Values, c *common.Client) ([]byte, error) {
+	req, err := http.NewRequest("GET", s.host+"/xrpc/"+method+"?"+params.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
+	if s.accessJwt != "" {
+		req.Header.Set("Authorization", "Bearer "+s.accessJwt)
+	}
 
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
@@ -105,7 +208,17 @@ func fetchBlueskyProfile(username string, c *common.Client) (*bskyProfile, error
 	}
 
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("failed to get profile: %v %v. Body: %s", resp.StatusCode, resp.Status, string(data))
+		return nil, fmt.Errorf("Failed to get a successfull response. %v: %v. Body: %s", resp.StatusCode, resp.Status, string(data))
+	}
+
+	return data, nil
+}
+
+func fetchBlueskyProfile(session *bskySession, username string, c *common.Client) (*bskyProfile, error) {
+
+	data, err := session.get("app.bsky.actor.getProfile", url.Values{"actor": {username}}, c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
 	}
 
 	var profile bskyProfile
@@ -116,7 +229,20 @@ func fetchBlueskyProfile(username string, c *common.Client) (*bskyProfile, error
 	return &profile, nil
 }
 
-func FetchBlueskyPosts(dbQueries *database.Queries, c *common.Client, uid uuid.UUID, sourceId uuid.UUID) error {
+func FetchBlueskyPosts(dbQueries *database.Queries, c *common.Client, encryptionKey []byte, sourceId uuid.UUID) error {
+
+	source, err := dbQueries.GetSourceById(context.Background(), sourceId)
+	if err != nil {
+		return err
+	}
+	username := source.UserName
+
+	opts := getBlueskyOptions(context.Background(), dbQueries, encryptionKey, sourceId)
+
+	session, err := createBlueskySession(opts, username, c)
+	if err != nil {
+		return fmt.Errorf("Bluesky: %w", err)
+	}
 
 	exclusionMap, err := common.LoadExclusionMap(dbQueries, sourceId)
 	if err != nil {
@@ -127,37 +253,28 @@ func FetchBlueskyPosts(dbQueries *database.Queries, c *common.Client, uid uuid.U
 
 	const maxPages = 500
 
+	filter := "posts_no_replies"
+	if opts.IncludeReplies {
+		filter = "posts_with_replies"
+	}
+
 	var cursor string
-	var username string
-	var url string
 
 	for page := 0; page < maxPages; page++ {
 
-		url, username, err = getBskyApiString(dbQueries, sourceId, cursor)
-		if err != nil {
-			return err
-		}
-
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return err
-		}
-
-		resp, err := c.HTTPClient.Do(req)
-		if err != nil {
-			return err
+		params := url.Values{}
+		params.Set("actor", username)
+		params.Set("limit", "100")
+		params.Set("filter", filter)
+		if cursor != "" {
+			params.Set("cursor", cursor)
 		}
 
-		data, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
+		data, err := session.get("app.bsky.feed.getAuthorFeed", params, c)
 		if err != nil {
 			return err
 		}
 
-		if resp.StatusCode != 200 {
-			return fmt.Errorf("Failed to get a successfull response. %v: %v. Body: %s", resp.StatusCode, resp.Status, string(data))
-		}
-
 		var feed bskyFeed
 		if err := json.Unmarshal(data, &feed); err != nil {
 			return err
@@ -180,6 +297,10 @@ func FetchBlueskyPosts(dbQueries *database.Queries, c *common.Client, uid uuid.U
 
 			post_type := "post"
 
+			if item.Post.Record.Reply != nil {
+				post_type = "reply"
+			}
+
 			if item.Post.Record.Embed.Type == "app.bsky.embed.record" || item.Post.Record.Embed.Type == "app.bsky.embed.recordWithMedia" {
 				post_type = "quote"
 			}
@@ -188,6 +309,12 @@ func FetchBlueskyPosts(dbQueries *database.Queries, c *common.Client, uid uuid.U
 				post_type = "repost"
 			}
 
+			if (post_type == "reply" && !opts.IncludeReplies) ||
+				(post_type == "quote" && !opts.IncludeQuotes) ||
+				(post_type == "repost" && !opts.IncludeReposts) {
+				continue
+			}
+
 			postID, err := common.CreateOrUpdatePost(
 				context.Background(),
 				dbQueries,
@@ -218,6 +345,10 @@ func FetchBlueskyPosts(dbQueries *database.Queries, c *common.Client, uid uuid.U
 				Views: sql.NullInt64{
 					Valid: false,
 				},
+				Comments: sql.NullInt64{
+					Int64: int64(item.Post.ReplyCount),
+					Valid: true,
+				},
 			})
 		}
 
@@ -237,7 +368,7 @@ func FetchBlueskyPosts(dbQueries *database.Queries, c *common.Client, uid uuid.U
 		log.Printf("Bluesky: Failed to calculate stats for source %s: %v", sourceId, err)
 	} else {
 
-		profile, err := fetchBlueskyProfile(username, c)
+		profile, err := fetchBlueskyProfile(session, username, c)
 		if err != nil {
 			log.Printf("Bluesky: Failed to fetch profile for source %s: %v", sourceId, err)
 		} else {
@@ -0,0 +1,17 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package sources
+
+import (
+	"context"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/fetcher/cookies"
+)
+
+// LoadSourceCookies reads the cookie jar a logged-in scraper syncs with.
+func LoadSourceCookies(ctx context.Context, dbQueries *database.Queries, encryptionKey []byte, source database.Source) ([]cookies.Cookie, error) {
+	if source.Network == "TikTok" {
+		return loadTikTokCookies(ctx, dbQueries, encryptionKey, source.ID, source.UserName)
+	}
+	return cookies.Load(ctx, dbQueries, encryptionKey, source.ID)
+}
@@ -38,6 +38,15 @@ func getDiscordDetails(ctx context.Context, dbQueries *database.Queries, encrypt
 	return botToken, serverID, channelIDs, nil
 }
 
+// discordReactionKey returns the unicode emoji, or "name:id" for custom
+// server emoji so that renamed emoji are still told apart.
+func discordReactionKey(reaction *discordgo.MessageReactions) string {
+	if reaction.Emoji == nil {
+		return ""
+	}
+	return reaction.Emoji.APIName()
+}
+
 func handleChannelChanges(
 	ctx context.Context,
 	dbQueries *database.Queries,
@@ -100,6 +109,7 @@ func FetchDiscordPosts(dbQueries *database.Queries, encryptionKey []byte, source
 	if err != nil {
 		return fmt.Errorf("failed to create Discord session: %w", err)
 	}
+	session.Client = c.WrapHTTPClient(session.Client)
 	defer session.Close()
 
 	if err := handleChannelChanges(ctx, dbQueries, sourceId, serverID, channelIDs); err != nil {
@@ -195,8 +205,10 @@ func FetchDiscordPosts(dbQueries *database.Queries, encryptionKey []byte, source
 				}
 
 				totalReactions := 0
+				reactionCounts := make(map[string]int64)
 				for _, reaction := range msg.Reactions {
 					totalReactions += reaction.Count
+					reactionCounts[discordReactionKey(reaction)] += int64(reaction.Count)
 				}
 
 				postID, err := common.CreateOrUpdatePost(
@@ -229,6 +241,10 @@ func FetchDiscordPosts(dbQueries *database.Queries, encryptionKey []byte, source
 				if err != nil {
 					log.Printf("Discord: Failed to sync reactions for message %s: %v", msgID, err)
 				}
+
+				if err := common.SyncReactionBreakdown(ctx, dbQueries, postID, reactionCounts); err != nil {
+					log.Printf("Discord: Failed to sync reaction breakdown for message %s: %v", msgID, err)
+				}
 			}
 
 			beforeID = messages[len(messages)-1].ID
@@ -307,6 +323,7 @@ func processForumThread(
 	}
 
 	totalReactions := 0
+	reactionCounts := make(map[string]int64)
 	messages, err := session.ChannelMessages(threadID, 1, "", "0", "")
 	if err != nil {
 		log.Printf("Discord: Failed to fetch first message for thread %s: %v", threadID, err)
@@ -314,6 +331,7 @@ func processForumThread(
 		firstMsg := messages[0]
 		for _, reaction := range firstMsg.Reactions {
 			totalReactions += reaction.Count
+			reactionCounts[discordReactionKey(reaction)] += int64(reaction.Count)
 		}
 	}
 
@@ -332,5 +350,9 @@ func processForumThread(
 		return fmt.Errorf("failed to sync reactions: %w", err)
 	}
 
+	if err := common.SyncReactionBreakdown(ctx, dbQueries, postID, reactionCounts); err != nil {
+		log.Printf("Discord: Failed to sync reaction breakdown for thread %s: %v", threadID, err)
+	}
+
 	return nil
 }
@@ -17,11 +17,22 @@ import (
 	"github.com/google/uuid"
 )
 
+// furAffinityLayout versions the selectors below.
+var furAffinityLayout = common.Layout{Network: "FurAffinity", Version: 1}
+
 type furAffinityProfile struct {
 	FollowersCount int
 	FollowingCount int
 }
 
+type furAffinitySubmission struct {
+	ID        string
+	PostedAt  time.Time
+	Content   string
+	Views     sql.NullInt64
+	Favorites sql.NullInt64
+}
+
 func fetchFurAffinityProfile(c *common.Client, username string) (*furAffinityProfile, error) {
 	url := fmt.Sprintf("https://www.furaffinity.net/user/%s/", username)
 	req, err := http.NewRequest("GET", url, nil)
@@ -46,7 +57,12 @@ func fetchFurAffinityProfile(c *common.Client, username string) (*furAffinityPro
 
 	profile := &furAffinityProfile{}
 
-	doc.Find(".section-header .floatright h3 a").Each(func(i int, s *goquery.Selection) {
+	headers := doc.Find(".section-header .floatright h3 a")
+	if headers.Length() == 0 {
+		return nil, furAffinityLayout.Errorf("watcher counts not found")
+	}
+
+	headers.Each(func(i int, s *goquery.Selection) {
 		text := strings.TrimSpace(s.Text())
 		
 		if strings.Contains(text, "Watched by") {
@@ -106,6 +122,9 @@ func FetchFurAffinityPosts(dbQueries *database.Queries, c *common.Client, uid uu
 		}
 	}()
 
+	check := furAffinityLayout.NewCheck()
+	var submissions []furAffinitySubmission
+
 	processedLinks := make(map[string]struct{})
 	page := 1
 	const maxPages = 500
@@ -159,10 +178,11 @@ func FetchFurAffinityPosts(dbQueries *database.Queries, c *common.Client, uid uu
 				return
 			}
 
-			err := processSubmission(dbQueries, c, sourceId, submissionId, username)
+			submission, err := parseFurAffinitySubmission(c, check, submissionId)
 			if err != nil {
 				log.Printf("FurAffinity: Failed to process submission %s: %v", submissionId, err)
 			} else {
+				submissions = append(submissions, *submission)
 				foundNew = true
 			}
 		})
@@ -177,74 +197,106 @@ func FetchFurAffinityPosts(dbQueries *database.Queries, c *common.Client, uid uu
 		return fmt.Errorf("no content found")
 	}
 
+	if err := check.Err(context.Background(), dbQueries, sourceId); err != nil {
+		return err
+	}
+
+	for _, submission := range submissions {
+		if err := saveFurAffinitySubmission(dbQueries, sourceId, username, submission); err != nil {
+			log.Printf("FurAffinity: Failed to save submission %s: %v", submission.ID, err)
+		}
+	}
+
 	return nil
 }
 
-func processSubmission(dbQueries *database.Queries, c *common.Client, sourceId uuid.UUID, submissionId string, username string) error {
+func parseFurAffinitySubmission(c *common.Client, check *common.LayoutCheck, submissionId string) (*furAffinitySubmission, error) {
 	url := fmt.Sprintf("https://www.furaffinity.net/view/%s/", submissionId)
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return fmt.Errorf("failed to fetch submission %s: %d", submissionId, resp.StatusCode)
+		return nil, fmt.Errorf("failed to fetch submission %s: %d", submissionId, resp.StatusCode)
 	}
 
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	viewsStr := strings.TrimSpace(doc.Find(".submission-page-stats div[title='Views'] div").First().Text())
-	views, _ := strconv.Atoi(viewsStr)
+	check.Page()
+
+	views, viewsErr := strconv.Atoi(strings.ReplaceAll(strings.TrimSpace(doc.Find(".submission-page-stats div[title='Views'] div").First().Text()), ",", ""))
+	if viewsErr != nil {
+		check.Missing("views")
+	} else {
+		check.Metric(common.MetricViews, views)
+	}
 
-	favoritesStr := strings.TrimSpace(doc.Find(".submission-page-stats div[title='Favorites'] div").First().Text())
-	favorites, _ := strconv.Atoi(favoritesStr)
+	favorites, favoritesErr := strconv.Atoi(strings.ReplaceAll(strings.TrimSpace(doc.Find(".submission-page-stats div[title='Favorites'] div").First().Text()), ",", ""))
+	if favoritesErr != nil {
+		check.Missing("favorites")
+	} else {
+		check.Metric(common.MetricLikes, favorites)
+	}
 
-	title := strings.TrimSpace(doc.Find(".submission-title h2 p").First().Text())
-    
-    descriptionRaw, _ := doc.Find(".submission-description").Html()
-    description := common.StripHTMLToText(descriptionRaw)
+	titleEl := doc.Find(".submission-title h2 p").First()
+	if titleEl.Length() == 0 {
+		check.Missing("title")
+	}
+	title := strings.TrimSpace(titleEl.Text())
 
-	content := fmt.Sprintf("%s\n\n%s", title, description)
+	descriptionRaw, _ := doc.Find(".submission-description").Html()
+	description := common.StripHTMLToText(descriptionRaw)
 
 	dateEl := doc.Find(".popup_date").First()
-	
+
 	var postedAt time.Time
 	if timestampStr, exists := dateEl.Attr("data-time"); exists {
 		if timestamp, err := strconv.ParseInt(timestampStr, 10, 64); err == nil {
 			postedAt = time.Unix(timestamp, 0)
 		}
 	}
-	
+
 	if postedAt.IsZero() {
 		if dateTitle, exists := dateEl.Attr("title"); exists {
 			layout := "Jan 2, 2006 03:04:05 PM"
 			postedAt, _ = time.Parse(layout, dateTitle)
 		}
 	}
-	
+
 	if postedAt.IsZero() {
-		postedAt = time.Now()
+		check.Missing("posted date")
 	}
 
+	return &furAffinitySubmission{
+		ID:        submissionId,
+		PostedAt:  postedAt,
+		Content:   fmt.Sprintf("%s\n\n%s", title, description),
+		Views:     sql.NullInt64{Int64: int64(views), Valid: viewsErr == nil},
+		Favorites: sql.NullInt64{Int64: int64(favorites), Valid: favoritesErr == nil},
+	}, nil
+}
+
+func saveFurAffinitySubmission(dbQueries *database.Queries, sourceId uuid.UUID, username string, submission furAffinitySubmission) error {
 	postID, err := common.CreateOrUpdatePost(
 		context.Background(),
 		dbQueries,
 		sourceId,
-		submissionId,
+		submission.ID,
 		"FurAffinity",
-		postedAt,
+		submission.PostedAt,
 		"post",
 		username,
-		content,
+		submission.Content,
 	)
 	if err != nil {
 		return err
@@ -254,17 +306,11 @@ func processSubmission(dbQueries *database.Queries, c *common.Client, sourceId u
 		ID:       uuid.New(),
 		SyncedAt: time.Now(),
 		PostID:   postID,
-		Likes: sql.NullInt64{
-			Int64: int64(favorites),
-			Valid: true,
-		},
+		Likes:    submission.Favorites,
 		Reposts: sql.NullInt64{
 			Valid: false,
 		},
-		Views: sql.NullInt64{
-			Int64: int64(views),
-			Valid: true,
-		},
+		Views: submission.Views,
 	})
 
 	return err
@@ -67,19 +67,11 @@ func FetchFurTrackPosts(dbQueries *database.Queries, c *common.Client, uid uuid.
 	username := source.UserName
 	log.Printf("FurTrack: Starting sync for user %s", username)
 
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"),
-		chromedp.WindowSize(1920, 1080),
-		chromedp.Flag("headless", true),
-		chromedp.Flag("no-sandbox", true),
-		chromedp.Flag("disable-dev-shm-usage", true),
-	)
-
-	allocCtx, cancel := chromedp.NewExecAllocator(context.Background(), opts...)
-	defer cancel()
-
-	ctx, cancel := chromedp.NewContext(allocCtx)
-	defer cancel()
+	ctx, release, err := c.NewBrowserTab(context.Background())
+	if err != nil {
+		return err
+	}
+	defer release()
 
 	url := fmt.Sprintf("https://www.furtrack.com/user/%s/photography", username)
 
@@ -108,7 +100,7 @@ func FetchFurTrackPosts(dbQueries *database.Queries, c *common.Client, uid uuid.
 			})
 			return nil
 		}),
-		chromedp.Navigate(url),
+		chromedp.Navigate(c.ResolveURL(url)),
 	)
 	if err != nil {
 		return fmt.Errorf("FurTrack: Browser navigation failed: %w", err)
@@ -168,7 +160,7 @@ func FetchFurTrackPosts(dbQueries *database.Queries, c *common.Client, uid uuid.
 				})
 				return nil
 			}),
-			chromedp.Navigate(albumURL),
+			chromedp.Navigate(c.ResolveURL(albumURL)),
 		)
 		if err != nil {
 			log.Printf("FurTrack: Failed to navigate to album %d: %v", album.AlbumID, err)
@@ -9,13 +9,14 @@ import (
 
 	"github.com/fluffyriot/rpsync/internal/authhelp"
 	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/fetcher/common"
 	"github.com/google/uuid"
 	"golang.org/x/oauth2/google"
 	analyticsdata "google.golang.org/api/analyticsdata/v1beta"
 	"google.golang.org/api/option"
 )
 
-func FetchGoogleAnalyticsStats(dbQueries *database.Queries, sourceID uuid.UUID, encryptionKey []byte) error {
+func FetchGoogleAnalyticsStats(dbQueries *database.Queries, sourceID uuid.UUID, encryptionKey []byte, c *common.Client) error {
 	ctx := context.Background()
 
 	statsCheck, err := dbQueries.CountAnalyticsSiteStatsBySource(ctx, sourceID)
@@ -29,11 +30,11 @@ func FetchGoogleAnalyticsStats(dbQueries *database.Queries, sourceID uuid.UUID,
 	}
 	endDate := "today"
 
-	return FetchGoogleAnalyticsStatsWithRange(dbQueries, sourceID, encryptionKey, startDate, endDate)
+	return FetchGoogleAnalyticsStatsWithRange(dbQueries, sourceID, encryptionKey, startDate, endDate, c)
 }
 
-func FetchGoogleAnalyticsStatsWithRange(dbQueries *database.Queries, sourceID uuid.UUID, encryptionKey []byte, startDate, endDate string) error {
-	ctx := context.Background()
+func FetchGoogleAnalyticsStatsWithRange(dbQueries *database.Queries, sourceID uuid.UUID, encryptionKey []byte, startDate, endDate string, c *common.Client) error {
+	ctx := c.OAuth2Context(context.Background())
 
 	source, err := dbQueries.GetSourceById(ctx, sourceID)
 	if err != nil {
@@ -54,7 +55,7 @@ func FetchGoogleAnalyticsStatsWithRange(dbQueries *database.Queries, sourceID uu
 		return fmt.Errorf("failed to parse service account credentials: %w", err)
 	}
 
-	client, err := analyticsdata.NewService(ctx, option.WithTokenSource(jwtCfg.TokenSource(ctx)))
+	client, err := analyticsdata.NewService(ctx, option.WithHTTPClient(jwtCfg.Client(ctx)))
 	if err != nil {
 		return fmt.Errorf("failed to create analytics client: %w", err)
 	}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/fluffyriot/rpsync/internal/authhelp"
 	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/fetcher/common"
 	"github.com/google/uuid"
 
 	"golang.org/x/oauth2/google"
@@ -19,7 +20,7 @@ import (
 	webmasters "google.golang.org/api/webmasters/v3"
 )
 
-func FetchGoogleSearchConsoleStats(dbQueries *database.Queries, sourceID uuid.UUID, encryptionKey []byte) error {
+func FetchGoogleSearchConsoleStats(dbQueries *database.Queries, sourceID uuid.UUID, encryptionKey []byte, c *common.Client) error {
 	ctx := context.Background()
 
 	statsCheck, err := dbQueries.CountAnalyticsSiteStatsBySource(ctx, sourceID)
@@ -34,11 +35,11 @@ func FetchGoogleSearchConsoleStats(dbQueries *database.Queries, sourceID uuid.UU
 	}
 	endDate := now.Format("2006-01-02")
 
-	return FetchGoogleSearchConsoleStatsWithRange(dbQueries, sourceID, encryptionKey, startDate, endDate)
+	return FetchGoogleSearchConsoleStatsWithRange(dbQueries, sourceID, encryptionKey, startDate, endDate, c)
 }
 
-func FetchGoogleSearchConsoleStatsWithRange(dbQueries *database.Queries, sourceID uuid.UUID, encryptionKey []byte, startDate, endDate string) error {
-	ctx := context.Background()
+func FetchGoogleSearchConsoleStatsWithRange(dbQueries *database.Queries, sourceID uuid.UUID, encryptionKey []byte, startDate, endDate string, c *common.Client) error {
+	ctx := c.OAuth2Context(context.Background())
 
 	source, err := dbQueries.GetSourceById(ctx, sourceID)
 	if err != nil {
@@ -68,7 +69,7 @@ func FetchGoogleSearchConsoleStatsWithRange(dbQueries *database.Queries, sourceI
 		return fmt.Errorf("GSC: failed to parse service account credentials: %w", err)
 	}
 
-	svc, err := webmasters.NewService(ctx, option.WithTokenSource(jwtCfg.TokenSource(ctx)))
+	svc, err := webmasters.NewService(ctx, option.WithHTTPClient(jwtCfg.Client(ctx)))
 	if err != nil {
 		return fmt.Errorf("GSC: failed to create Search Console client: %w", err)
 	}
@@ -337,6 +337,12 @@ func FetchInstagramPosts(dbQueries *database.Queries, c *common.Client, sourceId
 		log.Printf("Instagram: Failed to update stats for source %s: %v", sourceId, err)
 	}
 
+	if !noInsights {
+		if err := FetchInstagramAccountInsights(dbQueries, c, sourceId, token, pid, ver); err != nil {
+			log.Printf("Instagram: Failed to fetch account insights for source %s: %v", sourceId, err)
+		}
+	}
+
 	return nil
 
 }
@@ -0,0 +1,226 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/fetcher/common"
+	"github.com/google/uuid"
+)
+
+// instagramInsightsLookbackDays is how many completed days are re-read on
+// every sync. Instagram keeps revising recent totals, so the window is upserted.
+const instagramInsightsLookbackDays = 7
+
+var instagramDemographicBreakdowns = []string{"age", "gender", "country", "city"}
+
+type instagramOnlineFollowersResponse struct {
+	Data []struct {
+		Name   string `json:"name"`
+		Values []struct {
+			Value   map[string]int64 `json:"value"`
+			EndTime string           `json:"end_time"`
+		} `json:"values"`
+	} `json:"data"`
+}
+
+type instagramDailyInsights struct {
+	Reach           int64
+	ProfileViews    int64
+	WebsiteClicks   int64
+	AccountsEngaged int64
+}
+
+func instagramInsightsURL(version, pid, token string, params url.Values) string {
+	params.Set("access_token", token)
+	return fmt.Sprintf("https://graph.facebook.com/%s/%s/insights?%s", version, pid, params.Encode())
+}
+
+func fetchInstagramDailyInsights(day time.Time, token, pid, version string, c *common.Client) (*instagramDailyInsights, error) {
+	params := url.Values{}
+	params.Set("metric", "reach,profile_views,website_clicks,accounts_engaged")
+	params.Set("period", "day")
+	params.Set("metric_type", "total_value")
+	params.Set("since", strconv.FormatInt(day.Unix(), 10))
+	params.Set("until", strconv.FormatInt(day.AddDate(0, 0, 1).Unix(), 10))
+
+	data, err := instagramGraphGet(instagramInsightsURL(version, pid, token, params), c)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp instagramInsightsResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+
+	insights := &instagramDailyInsights{}
+	for _, metric := range resp.Data {
+		switch metric.Name {
+		case "reach":
+			insights.Reach = metric.TotalValue.Value
+		case "profile_views":
+			insights.ProfileViews = metric.TotalValue.Value
+		case "website_clicks":
+			insights.WebsiteClicks = metric.TotalValue.Value
+		case "accounts_engaged":
+			insights.AccountsEngaged = metric.TotalValue.Value
+		}
+	}
+
+	return insights, nil
+}
+
+// fetchInstagramFollowerDemographics returns the lifetime follower split for
+// every supported breakdown, keyed by breakdown and then dimension value.
+func fetchInstagramFollowerDemographics(token, pid, version string, c *common.Client) (map[string]map[string]int64, error) {
+	demographics := make(map[string]map[string]int64)
+
+	for _, breakdown := range instagramDemographicBreakdowns {
+		params := url.Values{}
+		params.Set("metric", "follower_demographics")
+		params.Set("period", "lifetime")
+		params.Set("metric_type", "total_value")
+		params.Set("breakdown", breakdown)
+
+		data, err := instagramGraphGet(instagramInsightsURL(version, pid, token, params), c)
+		if err != nil {
+			return nil, err
+		}
+
+		var resp instagramInsightsResponse
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, err
+		}
+
+		values := make(map[string]int64)
+		for _, metric := range resp.Data {
+			for _, b := range metric.TotalValue.Breakdowns {
+				for _, result := range b.Results {
+					if len(result.DimensionValues) == 0 {
+						continue
+					}
+					values[result.DimensionValues[0]] += result.Value
+				}
+			}
+		}
+
+		if len(values) > 0 {
+			demographics[breakdown] = values
+		}
+	}
+
+	return demographics, nil
+}
+
+func syncInstagramOnlineFollowers(ctx context.Context, dbQueries *database.Queries, sourceId uuid.UUID, since, until time.Time, token, pid, version string, c *common.Client) error {
+	params := url.Values{}
+	params.Set("metric", "online_followers")
+	params.Set("period", "lifetime")
+	params.Set("since", strconv.FormatInt(since.Unix(), 10))
+	params.Set("until", strconv.FormatInt(until.Unix(), 10))
+
+	data, err := instagramGraphGet(instagramInsightsURL(version, pid, token, params), c)
+	if err != nil {
+		return err
+	}
+
+	var resp instagramOnlineFollowersResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return err
+	}
+
+	for _, metric := range resp.Data {
+		for _, v := range metric.Values {
+			endTime, err := time.Parse("2006-01-02T15:04:05-0700", v.EndTime)
+			if err != nil {
+				continue
+			}
+			// end_time marks the end of the reported day.
+			date := endTime.UTC().AddDate(0, 0, -1)
+			date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+			for hourStr, followers := range v.Value {
+				hour, err := strconv.Atoi(hourStr)
+				if err != nil || hour < 0 || hour > 23 {
+					continue
+				}
+				if err := dbQueries.UpsertInstagramOnlineFollowers(ctx, database.UpsertInstagramOnlineFollowersParams{
+					ID:        uuid.New(),
+					Date:      date,
+					SourceID:  sourceId,
+					HourOfDay: int32(hour),
+					Followers: followers,
+				}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// FetchInstagramAccountInsights stores account-level daily insights: reach,
+// profile views, website clicks, follower demographics and the hours when
+// followers are online.
+func FetchInstagramAccountInsights(dbQueries *database.Queries, c *common.Client, sourceId uuid.UUID, token, pid, version string) error {
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	since := today.AddDate(0, 0, -instagramInsightsLookbackDays)
+
+	demographicsJSON := json.RawMessage("{}")
+	demographics, err := fetchInstagramFollowerDemographics(token, pid, version, c)
+	if err != nil {
+		log.Printf("Instagram: Failed to fetch follower demographics for source %s: %v", sourceId, err)
+	} else if len(demographics) > 0 {
+		demographicsJSON, err = json.Marshal(demographics)
+		if err != nil {
+			return err
+		}
+	}
+
+	for day := since; day.Before(today); day = day.AddDate(0, 0, 1) {
+		insights, err := fetchInstagramDailyInsights(day, token, pid, version, c)
+		if err != nil {
+			return err
+		}
+
+		// Demographics are a lifetime snapshot, so only the latest day carries them.
+		dayDemographics := json.RawMessage("{}")
+		if day.Equal(today.AddDate(0, 0, -1)) {
+			dayDemographics = demographicsJSON
+		}
+
+		if err := dbQueries.UpsertInstagramAccountInsights(ctx, database.UpsertInstagramAccountInsightsParams{
+			ID:                   uuid.New(),
+			Date:                 day,
+			SourceID:             sourceId,
+			UpdatedAt:            time.Now(),
+			Reach:                insights.Reach,
+			ProfileViews:         insights.ProfileViews,
+			WebsiteClicks:        insights.WebsiteClicks,
+			AccountsEngaged:      insights.AccountsEngaged,
+			FollowerDemographics: dayDemographics,
+		}); err != nil {
+			return err
+		}
+
+		time.Sleep(common.APIRateLimit)
+	}
+
+	if err := syncInstagramOnlineFollowers(ctx, dbQueries, sourceId, since, today, token, pid, version, c); err != nil {
+		log.Printf("Instagram: Failed to fetch online followers for source %s: %v", sourceId, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,270 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package sources
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/authhelp"
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/fetcher/common"
+	"github.com/google/uuid"
+)
+
+const instagramStoryPostPrefix = "story-"
+
+type instagramStoriesFeed struct {
+	Data []struct {
+		ID        string `json:"id"`
+		Caption   string `json:"caption"`
+		MediaType string `json:"media_type"`
+		Timestamp string `json:"timestamp"`
+		Username  string `json:"username"`
+	} `json:"data"`
+}
+
+type instagramInsightsResponse struct {
+	Data []struct {
+		Name   string `json:"name"`
+		Values []struct {
+			Value int64 `json:"value"`
+		} `json:"values"`
+		TotalValue struct {
+			Value      int64 `json:"value"`
+			Breakdowns []struct {
+				Results []struct {
+					DimensionValues []string `json:"dimension_values"`
+					Value           int64    `json:"value"`
+				} `json:"results"`
+			} `json:"breakdowns"`
+		} `json:"total_value"`
+	} `json:"data"`
+}
+
+type instagramStoryInsights struct {
+	Reach             int64
+	Views             int64
+	Replies           int64
+	Shares            int64
+	TotalInteractions int64
+	TapsForward       int64
+	TapsBack          int64
+	Exits             int64
+}
+
+func instagramGraphGet(apiURL string, c *common.Client) ([]byte, error) {
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		if isFacebookSessionExpired(data) {
+			return nil, fmt.Errorf("Facebook session has expired — please reconnect your Instagram account via Source Settings")
+		}
+		return nil, fmt.Errorf("Failed to get a successfull response. %v: %v. Body: %s", resp.StatusCode, resp.Status, string(data))
+	}
+
+	return data, nil
+}
+
+func fetchInstagramStoryInsights(storyID, token, version string, c *common.Client) (*instagramStoryInsights, error) {
+	params := url.Values{}
+	params.Set("metric", "reach,views,replies,shares,total_interactions")
+	params.Set("access_token", token)
+
+	data, err := instagramGraphGet(fmt.Sprintf("https://graph.facebook.com/%s/%s/insights?%s", version, storyID, params.Encode()), c)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp instagramInsightsResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+
+	insights := &instagramStoryInsights{}
+	for _, metric := range resp.Data {
+		value := metric.TotalValue.Value
+		if len(metric.Values) > 0 {
+			value = metric.Values[0].Value
+		}
+		switch metric.Name {
+		case "reach":
+			insights.Reach = value
+		case "views":
+			insights.Views = value
+		case "replies":
+			insights.Replies = value
+		case "shares":
+			insights.Shares = value
+		case "total_interactions":
+			insights.TotalInteractions = value
+		}
+	}
+
+	params.Set("metric", "navigation")
+	params.Set("breakdown", "story_navigation_action_type")
+
+	data, err = instagramGraphGet(fmt.Sprintf("https://graph.facebook.com/%s/%s/insights?%s", version, storyID, params.Encode()), c)
+	if err != nil {
+		log.Printf("Instagram: Failed to fetch navigation insights for story %s: %v", storyID, err)
+		return insights, nil
+	}
+
+	var nav instagramInsightsResponse
+	if err := json.Unmarshal(data, &nav); err != nil {
+		return insights, nil
+	}
+
+	for _, metric := range nav.Data {
+		for _, breakdown := range metric.TotalValue.Breakdowns {
+			for _, result := range breakdown.Results {
+				if len(result.DimensionValues) == 0 {
+					continue
+				}
+				switch strings.ToLower(result.DimensionValues[0]) {
+				case "tap_forward", "swipe_forward":
+					insights.TapsForward += result.Value
+				case "tap_back":
+					insights.TapsBack += result.Value
+				case "tap_exit":
+					insights.Exits += result.Value
+				}
+			}
+		}
+	}
+
+	return insights, nil
+}
+
+// PollInstagramStories records the account's active Stories and snapshots
+// their insights. Stories expire after 24 hours, so this runs on its own
+// cadence instead of waiting for the regular sync.
+func PollInstagramStories(dbQueries *database.Queries, c *common.Client, sourceId uuid.UUID, version string, encryptionKey []byte) error {
+	ctx := context.Background()
+
+	token, pid, _, _, err := authhelp.GetSourceToken(ctx, dbQueries, encryptionKey, sourceId)
+	if err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Set("fields", "id,caption,media_type,timestamp,username")
+	params.Set("access_token", token)
+
+	data, err := instagramGraphGet(fmt.Sprintf("https://graph.facebook.com/%s/%s/stories?%s", version, pid, params.Encode()), c)
+	if err != nil {
+		return err
+	}
+
+	var feed instagramStoriesFeed
+	if err := json.Unmarshal(data, &feed); err != nil {
+		return err
+	}
+
+	exclusionMap, err := common.LoadExclusionMap(dbQueries, sourceId)
+	if err != nil {
+		return err
+	}
+
+	for _, story := range feed.Data {
+		networkID := instagramStoryPostPrefix + story.ID
+		if exclusionMap[networkID] {
+			continue
+		}
+
+		postedAt, err := time.Parse("2006-01-02T15:04:05-0700", story.Timestamp)
+		if err != nil {
+			postedAt = time.Now()
+		}
+
+		postID, err := common.CreateOrUpdatePost(
+			ctx,
+			dbQueries,
+			sourceId,
+			networkID,
+			"Instagram",
+			postedAt,
+			"story",
+			story.Username,
+			story.Caption,
+		)
+		if err != nil {
+			log.Printf("Instagram: Failed to save story %s: %v", story.ID, err)
+			continue
+		}
+
+		insights, err := fetchInstagramStoryInsights(story.ID, token, version, c)
+		if err != nil {
+			log.Printf("Instagram: Failed to fetch insights for story %s: %v", story.ID, err)
+			continue
+		}
+
+		now := time.Now()
+
+		if err := dbQueries.CreateInstagramStorySnapshot(ctx, database.CreateInstagramStorySnapshotParams{
+			ID:                uuid.New(),
+			SyncedAt:          now,
+			PostID:            postID,
+			Reach:             insights.Reach,
+			Views:             insights.Views,
+			Replies:           insights.Replies,
+			Shares:            insights.Shares,
+			TotalInteractions: insights.TotalInteractions,
+			TapsForward:       insights.TapsForward,
+			TapsBack:          insights.TapsBack,
+			Exits:             insights.Exits,
+		}); err != nil {
+			log.Printf("Instagram: Failed to save snapshot for story %s: %v", story.ID, err)
+		}
+
+		_, err = dbQueries.SyncReactions(ctx, database.SyncReactionsParams{
+			ID:       uuid.New(),
+			SyncedAt: now,
+			PostID:   postID,
+			Likes: sql.NullInt64{
+				Int64: insights.TotalInteractions,
+				Valid: true,
+			},
+			Reposts: sql.NullInt64{
+				Int64: insights.Shares,
+				Valid: true,
+			},
+			Views: sql.NullInt64{
+				Int64: insights.Views,
+				Valid: true,
+			},
+			Comments: sql.NullInt64{
+				Int64: insights.Replies,
+				Valid: true,
+			},
+		})
+		if err != nil {
+			log.Printf("Instagram: Failed to sync reactions for story %s: %v", story.ID, err)
+		}
+
+		time.Sleep(common.APIRateLimit)
+	}
+
+	return nil
+}
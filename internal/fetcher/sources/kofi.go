@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package sources
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/authhelp"
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/fetcher/common"
+	"github.com/google/uuid"
+)
+
+var ErrKofiInvalidToken = errors.New("invalid Ko-fi verification token")
+
+type KofiPayload struct {
+	VerificationToken          string `json:"verification_token"`
+	MessageID                  string `json:"message_id"`
+	Timestamp                  string `json:"timestamp"`
+	Type                       string `json:"type"`
+	IsPublic                   bool   `json:"is_public"`
+	FromName                   string `json:"from_name"`
+	Message                    string `json:"message"`
+	Amount                     string `json:"amount"`
+	URL                        string `json:"url"`
+	Email                      string `json:"email"`
+	Currency                   string `json:"currency"`
+	IsSubscriptionPayment      bool   `json:"is_subscription_payment"`
+	IsFirstSubscriptionPayment bool   `json:"is_first_subscription_payment"`
+	KofiTransactionID          string `json:"kofi_transaction_id"`
+	TierName                   string `json:"tier_name"`
+}
+
+func kofiEventType(t string) string {
+	switch strings.ToLower(t) {
+	case "subscription":
+		return "subscription"
+	case "commission":
+		return "commission"
+	case "shop order":
+		return "shop_order"
+	default:
+		return "donation"
+	}
+}
+
+func ProcessKofiWebhook(dbQueries *database.Queries, encryptionKey []byte, sourceId uuid.UUID, payload KofiPayload) error {
+	ctx := context.Background()
+
+	source, err := dbQueries.GetSourceById(ctx, sourceId)
+	if err != nil {
+		return err
+	}
+	if source.Network != "Ko-fi" {
+		return fmt.Errorf("source %s is not a Ko-fi source", sourceId)
+	}
+
+	verificationToken, _, _, _, err := authhelp.GetSourceToken(ctx, dbQueries, encryptionKey, sourceId)
+	if err != nil {
+		return fmt.Errorf("Ko-fi: failed to get credentials: %w", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(verificationToken), []byte(payload.VerificationToken)) != 1 {
+		return ErrKofiInvalidToken
+	}
+
+	externalID := payload.KofiTransactionID
+	if externalID == "" {
+		externalID = payload.MessageID
+	}
+	if externalID == "" {
+		return fmt.Errorf("Ko-fi payload has no transaction or message id")
+	}
+
+	occurredAt, err := time.Parse(time.RFC3339, payload.Timestamp)
+	if err != nil {
+		occurredAt = time.Now()
+	}
+
+	amount, err := strconv.ParseFloat(payload.Amount, 64)
+	if err != nil {
+		return fmt.Errorf("Ko-fi payload has invalid amount %q", payload.Amount)
+	}
+
+	return dbQueries.CreateSupporterEvent(ctx, database.CreateSupporterEventParams{
+		ID:             uuid.New(),
+		CreatedAt:      time.Now(),
+		OccurredAt:     occurredAt,
+		SourceID:       sourceId,
+		ExternalID:     externalID,
+		EventType:      kofiEventType(payload.Type),
+		FromName:       sql.NullString{String: payload.FromName, Valid: payload.FromName != ""},
+		FromEmail:      sql.NullString{String: payload.Email, Valid: payload.Email != ""},
+		Message:        sql.NullString{String: payload.Message, Valid: payload.Message != ""},
+		AmountCents:    int64(math.Round(amount * 100)),
+		Currency:       payload.Currency,
+		IsPublic:       payload.IsPublic,
+		IsSubscription: payload.IsSubscriptionPayment,
+		TierName:       sql.NullString{String: payload.TierName, Valid: payload.TierName != ""},
+	})
+}
+
+func FetchKofiStats(dbQueries *database.Queries, sourceId uuid.UUID) error {
+	ctx := context.Background()
+
+	totals, err := dbQueries.GetSupporterEventTotalsBySource(ctx, sourceId)
+	if err != nil {
+		return err
+	}
+
+	supporters := int(totals.TotalSupporters)
+	events := int(totals.TotalEvents)
+
+	if err := common.SaveOrUpdateSourceStats(ctx, dbQueries, sourceId, &common.ProfileStats{
+		FollowersCount: &supporters,
+		PostsCount:     &events,
+	}); err != nil {
+		log.Printf("Ko-fi: failed to save stats: %v", err)
+	}
+
+	return nil
+}
@@ -25,6 +25,33 @@ type mastodonProfile struct {
 	FollowingCount int    `json:"following_count"`
 }
 
+// mastodonEmojiReaction is the Misskey-style reaction shape exposed by
+// Mastodon-compatible servers such as Fedibird, Pleroma and Akkoma.
+type mastodonEmojiReaction struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type mastodonStatusExtensions struct {
+	EmojiReactions []mastodonEmojiReaction `json:"emoji_reactions"`
+	Pleroma        struct {
+		EmojiReactions []mastodonEmojiReaction `json:"emoji_reactions"`
+	} `json:"pleroma"`
+}
+
+func (e mastodonStatusExtensions) reactionCounts() map[string]int64 {
+	counts := make(map[string]int64)
+	for _, r := range e.EmojiReactions {
+		counts[r.Name] += int64(r.Count)
+	}
+	if len(counts) == 0 {
+		for _, r := range e.Pleroma.EmojiReactions {
+			counts[r.Name] += int64(r.Count)
+		}
+	}
+	return counts
+}
+
 type mastFeed []struct {
 	ID              string    `json:"id"`
 	CreatedAt       time.Time `json:"created_at"`
@@ -32,7 +59,8 @@ type mastFeed []struct {
 	ReblogsCount    int       `json:"reblogs_count"`
 	QuotesCount     int       `json:"quotes_count"`
 	Content         string    `json:"content"`
-	Account         struct {
+	mastodonStatusExtensions
+	Account struct {
 		Id  string `json:"id"`
 		Uri string `json:"uri"`
 		Url string `json:"url"`
@@ -45,7 +73,8 @@ type mastFeed []struct {
 		ReblogsCount    int       `json:"reblogs_count"`
 		QuotesCount     int       `json:"quotes_count"`
 		Content         string    `json:"content"`
-		Account         struct {
+		mastodonStatusExtensions
+		Account struct {
 			Id  string `json:"id"`
 			Uri string `json:"uri"`
 			Url string `json:"url"`
@@ -255,12 +284,15 @@ func FetchMastodonPosts(dbQueries *database.Queries, c *common.Client, uid uuid.
 			}
 
 			var likes, reposts int
+			var reactions map[string]int64
 			if item.Reblog != nil {
 				likes = item.Reblog.FavouritesCount
 				reposts = item.Reblog.QuotesCount + item.Reblog.ReblogsCount
+				reactions = item.Reblog.reactionCounts()
 			} else {
 				likes = item.FavouritesCount
 				reposts = item.QuotesCount + item.ReblogsCount
+				reactions = item.reactionCounts()
 			}
 
 			_, err = dbQueries.SyncReactions(context.Background(), database.SyncReactionsParams{
@@ -280,6 +312,10 @@ func FetchMastodonPosts(dbQueries *database.Queries, c *common.Client, uid uuid.
 				},
 			})
 
+			if err := common.SyncReactionBreakdown(context.Background(), dbQueries, postID, reactions); err != nil {
+				log.Printf("Mastodon: Failed to sync reaction breakdown for post %s: %v", postId, err)
+			}
+
 		}
 
 	}
@@ -4,7 +4,6 @@ package sources
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -14,24 +13,23 @@ import (
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
-	"github.com/chromedp/cdproto/cdp"
 	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/chromedp"
-	"github.com/fluffyriot/rpsync/internal/authhelp"
 	"github.com/fluffyriot/rpsync/internal/database"
 	"github.com/fluffyriot/rpsync/internal/fetcher/common"
+	"github.com/fluffyriot/rpsync/internal/fetcher/cookies"
 	"github.com/google/uuid"
 )
 
-type murrCookieEntry struct {
-	Name           string   `json:"name"`
-	Value          string   `json:"value"`
-	Domain         string   `json:"domain"`
-	Path           string   `json:"path"`
-	Secure         bool     `json:"secure"`
-	HTTPOnly       bool     `json:"httpOnly"`
-	SameSite       *string  `json:"sameSite"`
-	ExpirationDate *float64 `json:"expirationDate"`
+// murrtubeLayout versions the selectors below.
+var murrtubeLayout = common.Layout{Network: "Murrtube", Version: 1}
+
+type murrtubeVideo struct {
+	ID        string
+	CreatedAt time.Time
+	Content   string
+	Likes     sql.NullInt64
+	Views     sql.NullInt64
 }
 
 func FetchMurrtubePosts(dbQueries *database.Queries, c *common.Client, sourceID uuid.UUID, encryptionKey []byte) error {
@@ -46,73 +44,27 @@ func FetchMurrtubePosts(dbQueries *database.Queries, c *common.Client, sourceID
 	}
 	username := source.UserName
 
-	cookieJSON, _, _, _, err := authhelp.GetSourceToken(context.Background(), dbQueries, encryptionKey, sourceID)
+	jar, err := cookies.Load(context.Background(), dbQueries, encryptionKey, sourceID)
 	if err != nil {
-		return fmt.Errorf("failed to load murrtube credentials: %w", err)
+		return fmt.Errorf("failed to load murrtube cookies: %w", err)
 	}
 
-	var cookies []murrCookieEntry
-	if err := json.Unmarshal([]byte(cookieJSON), &cookies); err != nil {
-		return fmt.Errorf("failed to parse murrtube cookie JSON: %w", err)
+	ctx, release, err := c.NewBrowserTab(context.Background())
+	if err != nil {
+		return err
 	}
-
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"),
-		chromedp.WindowSize(1920, 1080),
-		chromedp.Flag("headless", true),
-		chromedp.Flag("no-sandbox", true),
-		chromedp.Flag("disable-dev-shm-usage", true),
-		chromedp.Flag("disable-blink-features", "AutomationControlled"),
-		chromedp.Flag("enable-automation", false),
-		chromedp.Flag("no-first-run", true),
-		chromedp.Flag("no-default-browser-check", true),
-	)
-
-	allocCtx, allocCancel := chromedp.NewExecAllocator(context.Background(), opts...)
-	defer allocCancel()
-
-	ctx, ctxCancel := chromedp.NewContext(allocCtx)
-	defer ctxCancel()
+	defer release()
 
 	if err := chromedp.Run(ctx,
 		network.Enable(),
-		chromedp.ActionFunc(func(ctx context.Context) error {
-			for _, cookie := range cookies {
-				params := network.SetCookie(cookie.Name, cookie.Value).
-					WithDomain(cookie.Domain).
-					WithPath(cookie.Path).
-					WithSecure(cookie.Secure).
-					WithHTTPOnly(cookie.HTTPOnly)
-
-				if cookie.SameSite != nil {
-					switch strings.ToLower(*cookie.SameSite) {
-					case "no_restriction", "none":
-						params = params.WithSameSite(network.CookieSameSiteNone)
-					case "lax":
-						params = params.WithSameSite(network.CookieSameSiteLax)
-					case "strict":
-						params = params.WithSameSite(network.CookieSameSiteStrict)
-					}
-				}
-
-				if cookie.ExpirationDate != nil {
-					ts := cdp.TimeSinceEpoch(time.Unix(int64(*cookie.ExpirationDate), 0))
-					params = params.WithExpires(&ts)
-				}
-
-				if err := params.Do(ctx); err != nil {
-					return err
-				}
-			}
-			return nil
-		}),
+		cookies.SetAll(jar),
 	); err != nil {
 		return fmt.Errorf("murrtube: failed to initialise browser: %w", err)
 	}
 
 	profileURL := fmt.Sprintf("https://murrtube.net/%s", username)
 	if err := chromedp.Run(ctx,
-		chromedp.Navigate(profileURL),
+		chromedp.Navigate(c.ResolveURL(profileURL)),
 		chromedp.Sleep(3*time.Second),
 	); err != nil {
 		return fmt.Errorf("Murrtube: failed to navigate to profile: %w", err)
@@ -182,6 +134,9 @@ func FetchMurrtubePosts(dbQueries *database.Queries, c *common.Client, sourceID
 		return errors.New("no videos found: session may have expired or age check cookie missing")
 	}
 
+	check := murrtubeLayout.NewCheck()
+	var videos []murrtubeVideo
+
 	for _, id := range videoIDs {
 		if exclusionMap[id] {
 			continue
@@ -190,7 +145,7 @@ func FetchMurrtubePosts(dbQueries *database.Queries, c *common.Client, sourceID
 		videoURL := "https://murrtube.net/v/" + id
 		var videoHTML string
 		if err := chromedp.Run(ctx,
-			chromedp.Navigate(videoURL),
+			chromedp.Navigate(c.ResolveURL(videoURL)),
 			chromedp.Sleep(2*time.Second),
 			chromedp.OuterHTML("html", &videoHTML),
 		); err != nil {
@@ -204,43 +159,73 @@ func FetchMurrtubePosts(dbQueries *database.Queries, c *common.Client, sourceID
 			continue
 		}
 
-		title, _ := videoDoc.Find(`meta[property="og:title"]`).Attr("content")
+		check.Page()
+
+		title, ok := videoDoc.Find(`meta[property="og:title"]`).Attr("content")
+		if !ok {
+			check.Missing("title")
+		}
 		description, _ := videoDoc.Find(`meta[property="og:description"]`).Attr("content")
 
 		createdAt, err := extractMurrtubeCreatedAt(videoDoc)
 		if err != nil {
-			createdAt = time.Now()
+			check.Missing("created date")
+		}
+
+		pageText := videoDoc.Text()
+		videoViews, viewsErr := extractMurrNumber(pageText, `([\d,]+)\s+Views`)
+		if viewsErr != nil {
+			check.Missing("views")
+		} else {
+			check.Metric(common.MetricViews, videoViews)
+		}
+
+		videoLikes, likesErr := extractMurrNumber(pageText, `([\d,]+)\s+Likes`)
+		if likesErr != nil {
+			check.Missing("likes")
+		} else {
+			check.Metric(common.MetricLikes, videoLikes)
 		}
 
+		videos = append(videos, murrtubeVideo{
+			ID:        id,
+			CreatedAt: createdAt,
+			Content:   fmt.Sprintf("%s\n\n%s", title, description),
+			Likes:     sql.NullInt64{Int64: int64(videoLikes), Valid: likesErr == nil},
+			Views:     sql.NullInt64{Int64: int64(videoViews), Valid: viewsErr == nil},
+		})
+	}
+
+	if err := check.Err(context.Background(), dbQueries, sourceID); err != nil {
+		return err
+	}
+
+	for _, video := range videos {
 		postID, err := common.CreateOrUpdatePost(
 			context.Background(),
 			dbQueries,
 			sourceID,
-			id,
+			video.ID,
 			"Murrtube",
-			createdAt,
+			video.CreatedAt,
 			"video",
 			username,
-			fmt.Sprintf("%s\n\n%s", title, description),
+			video.Content,
 		)
 		if err != nil {
-			log.Printf("Murrtube: failed to process video %s: %v", id, err)
+			log.Printf("Murrtube: failed to process video %s: %v", video.ID, err)
 			continue
 		}
 
-		pageText := videoDoc.Text()
-		videoViews, _ := extractMurrNumber(pageText, `([\d,]+)\s+Views`)
-		videoLikes, _ := extractMurrNumber(pageText, `([\d,]+)\s+Likes`)
-
 		if _, err = dbQueries.SyncReactions(context.Background(), database.SyncReactionsParams{
 			ID:       uuid.New(),
 			SyncedAt: time.Now(),
 			PostID:   postID,
-			Likes:    sql.NullInt64{Int64: int64(videoLikes), Valid: true},
+			Likes:    video.Likes,
 			Reposts:  sql.NullInt64{Valid: false},
-			Views:    sql.NullInt64{Int64: int64(videoViews), Valid: true},
+			Views:    video.Views,
 		}); err != nil {
-			log.Printf("Murrtube: failed to sync reactions for video %s: %v", id, err)
+			log.Printf("Murrtube: failed to sync reactions for video %s: %v", video.ID, err)
 		}
 	}
 
@@ -281,9 +266,5 @@ func extractMurrNumber(text, pattern string) (int, error) {
 	}
 
 	clean := strings.ReplaceAll(match[1], ",", "")
-	value, err := strconv.Atoi(clean)
-	if err != nil {
-		return 0, nil
-	}
-	return value, nil
+	return strconv.Atoi(clean)
 }
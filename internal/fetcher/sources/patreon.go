@@ -0,0 +1,305 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package sources
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/authhelp"
+	"github.com/fluffyriot/rpsync/internal/config"
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/fetcher/common"
+	"github.com/google/uuid"
+)
+
+type patreonCampaignsResponse struct {
+	Data []struct {
+		ID         string `json:"id"`
+		Attributes struct {
+			PatronCount int    `json:"patron_count"`
+			Vanity      string `json:"vanity"`
+			URL         string `json:"url"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+type patreonCampaignResponse struct {
+	Data struct {
+		ID         string `json:"id"`
+		Attributes struct {
+			PatronCount int `json:"patron_count"`
+		} `json:"attributes"`
+	} `json:"data"`
+	Included []struct {
+		ID         string `json:"id"`
+		Type       string `json:"type"`
+		Attributes struct {
+			Title       string `json:"title"`
+			AmountCents int64  `json:"amount_cents"`
+			PatronCount int64  `json:"patron_count"`
+			Published   bool   `json:"published"`
+		} `json:"attributes"`
+	} `json:"included"`
+}
+
+type patreonPost struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		Title        string `json:"title"`
+		PublishedAt  string `json:"published_at"`
+		LikeCount    int64  `json:"like_count"`
+		CommentCount int64  `json:"comment_count"`
+		PostType     string `json:"post_type"`
+		IsPublic     bool   `json:"is_public"`
+	} `json:"attributes"`
+}
+
+type patreonPostsResponse struct {
+	Data  []patreonPost `json:"data"`
+	Links struct {
+		Next string `json:"next"`
+	} `json:"links"`
+}
+
+func patreonDoRequest(apiURL, accessToken string, c *common.Client) ([]byte, int, error) {
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	req.Header.Set("User-Agent", fmt.Sprintf("RPSync/%s (by riotphotos)", config.AppVersion))
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+
+	return body, resp.StatusCode, nil
+}
+
+func patreonGetCampaign(accessToken string, c *common.Client) (string, int, error) {
+	apiURL := "https://www.patreon.com/api/oauth2/v2/campaigns?fields%5Bcampaign%5D=patron_count,vanity,url"
+
+	body, status, err := patreonDoRequest(apiURL, accessToken, c)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if status != 200 {
+		return "", 0, fmt.Errorf("Patreon campaigns API returned %d: %s", status, string(body))
+	}
+
+	var resp patreonCampaignsResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", 0, fmt.Errorf("failed to decode Patreon campaigns response: %w", err)
+	}
+
+	if len(resp.Data) == 0 {
+		return "", 0, fmt.Errorf("no Patreon campaign found for this creator token")
+	}
+
+	return resp.Data[0].ID, resp.Data[0].Attributes.PatronCount, nil
+}
+
+func patreonSyncTiers(ctx context.Context, dbQueries *database.Queries, sourceId uuid.UUID, campaignID, accessToken string, c *common.Client) error {
+	apiURL := fmt.Sprintf(
+		"https://www.patreon.com/api/oauth2/v2/campaigns/%s?include=tiers&fields%%5Bcampaign%%5D=patron_count&fields%%5Btier%%5D=title,amount_cents,patron_count,published",
+		campaignID,
+	)
+
+	body, status, err := patreonDoRequest(apiURL, accessToken, c)
+	if err != nil {
+		return err
+	}
+
+	if status != 200 {
+		return fmt.Errorf("Patreon campaign API returned %d: %s", status, string(body))
+	}
+
+	var resp patreonCampaignResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("failed to decode Patreon campaign response: %w", err)
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	for _, tier := range resp.Included {
+		if tier.Type != "tier" || !tier.Attributes.Published {
+			continue
+		}
+
+		err := dbQueries.UpsertSupporterTierStat(ctx, database.UpsertSupporterTierStatParams{
+			ID:              uuid.New(),
+			Date:            today,
+			SourceID:        sourceId,
+			TierID:          tier.ID,
+			TierTitle:       tier.Attributes.Title,
+			AmountCents:     tier.Attributes.AmountCents,
+			SupportersCount: tier.Attributes.PatronCount,
+		})
+		if err != nil {
+			log.Printf("Patreon: failed to save tier %s stats: %v", tier.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func FetchPatreonPosts(dbQueries *database.Queries, encryptionKey []byte, sourceId uuid.UUID, c *common.Client) error {
+	ctx := context.Background()
+
+	userSource, err := dbQueries.GetSourceById(ctx, sourceId)
+	if err != nil {
+		return err
+	}
+	username := userSource.UserName
+
+	accessToken, _, _, _, err := authhelp.GetSourceToken(ctx, dbQueries, encryptionKey, sourceId)
+	if err != nil {
+		return fmt.Errorf("Patreon: failed to get credentials: %w", err)
+	}
+
+	campaignID, patronCount, err := patreonGetCampaign(accessToken, c)
+	if err != nil {
+		return err
+	}
+
+	if err := patreonSyncTiers(ctx, dbQueries, sourceId, campaignID, accessToken, c); err != nil {
+		log.Printf("Patreon: failed to sync tiers: %v", err)
+	}
+
+	exclusionMap, err := common.LoadExclusionMap(dbQueries, sourceId)
+	if err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Set("filter[campaign_id]", campaignID)
+	params.Set("filter[is_by_creator]", "true")
+	params.Set("fields[post]", "title,published_at,like_count,comment_count,post_type,is_public")
+	params.Set("sort", "-published_at")
+	params.Set("page[count]", "50")
+	nextURL := "https://www.patreon.com/api/posts?" + params.Encode()
+
+	const (
+		maxPages            = 200
+		maxRateLimitRetries = 5
+	)
+	rateLimitHits := 0
+	for page := 0; page < maxPages && nextURL != ""; {
+		time.Sleep(common.APIRateLimit)
+
+		body, status, err := patreonDoRequest(nextURL, "", c)
+		if err != nil {
+			return err
+		}
+
+		if status == 429 {
+			rateLimitHits++
+			if rateLimitHits > maxRateLimitRetries {
+				return fmt.Errorf("Patreon posts API kept rate limiting after %d retries", maxRateLimitRetries)
+			}
+			wait := time.Duration(rateLimitHits) * common.RateLimitWait
+			log.Printf("Patreon: rate limited on posts, waiting %s", wait)
+			time.Sleep(wait)
+			continue
+		}
+		rateLimitHits = 0
+		page++
+
+		if status != 200 {
+			return fmt.Errorf("Patreon posts API returned %d: %s", status, string(body))
+		}
+
+		var postsResp patreonPostsResponse
+		if err := json.Unmarshal(body, &postsResp); err != nil {
+			return fmt.Errorf("failed to decode Patreon posts response: %w", err)
+		}
+
+		for _, p := range postsResp.Data {
+			if exclusionMap[p.ID] || !p.Attributes.IsPublic {
+				continue
+			}
+
+			postedAt, err := time.Parse(time.RFC3339, p.Attributes.PublishedAt)
+			if err != nil {
+				log.Printf("Patreon: failed to parse time for post %s: %v", p.ID, err)
+				postedAt = time.Now()
+			}
+
+			postType := "post"
+			switch p.Attributes.PostType {
+			case "image_file":
+				postType = "image"
+			case "video_embed", "video_external_file":
+				postType = "video"
+			}
+
+			internalID, err := common.CreateOrUpdatePost(
+				ctx,
+				dbQueries,
+				sourceId,
+				p.ID,
+				"Patreon",
+				postedAt,
+				postType,
+				username,
+				p.Attributes.Title,
+			)
+			if err != nil {
+				log.Printf("Patreon: failed to save post %s: %v", p.ID, err)
+				continue
+			}
+
+			_, err = dbQueries.SyncReactions(ctx, database.SyncReactionsParams{
+				ID:       uuid.New(),
+				SyncedAt: time.Now(),
+				PostID:   internalID,
+				Likes: sql.NullInt64{
+					Int64: p.Attributes.LikeCount,
+					Valid: true,
+				},
+				Reposts: sql.NullInt64{Valid: false},
+				Views:   sql.NullInt64{Valid: false},
+				Comments: sql.NullInt64{
+					Int64: p.Attributes.CommentCount,
+					Valid: true,
+				},
+			})
+			if err != nil {
+				log.Printf("Patreon: failed to sync reactions for post %s: %v", p.ID, err)
+			}
+		}
+
+		nextURL = postsResp.Links.Next
+	}
+
+	avgStats, err := common.CalculateAverageStats(ctx, dbQueries, sourceId)
+	if err != nil {
+		log.Printf("Patreon: failed to calculate average stats: %v", err)
+		avgStats = &common.ProfileStats{}
+	}
+	avgStats.FollowersCount = &patronCount
+	avgStats.FollowingCount = nil
+
+	if err := common.SaveOrUpdateSourceStats(ctx, dbQueries, sourceId, avgStats); err != nil {
+		log.Printf("Patreon: failed to save stats: %v", err)
+	}
+
+	return nil
+}
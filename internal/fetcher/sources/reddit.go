@@ -11,6 +11,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -38,28 +39,68 @@ type redditListing struct {
 		After    string `json:"after"`
 		Children []struct {
 			Data struct {
-				ID         string  `json:"id"`
-				Subreddit  string  `json:"subreddit"`
-				Title      string  `json:"title"`
-				Selftext   string  `json:"selftext"`
-				Score      int     `json:"score"`
-				CreatedUTC float64 `json:"created_utc"`
-				Author     string  `json:"author"`
-				IsVideo    bool    `json:"is_video"`
+				ID            string  `json:"id"`
+				Subreddit     string  `json:"subreddit"`
+				Title         string  `json:"title"`
+				Selftext      string  `json:"selftext"`
+				Score         int     `json:"score"`
+				UpvoteRatio   float64 `json:"upvote_ratio"`
+				NumComments   int     `json:"num_comments"`
+				NumCrossposts int     `json:"num_crossposts"`
+				CreatedUTC    float64 `json:"created_utc"`
+				Author        string  `json:"author"`
+				IsVideo       bool    `json:"is_video"`
 			} `json:"data"`
 		} `json:"children"`
 	} `json:"data"`
 }
 
-func getRedditDetails(ctx context.Context, dbQueries *database.Queries, encryptionKey []byte, sid uuid.UUID) (username string, subreddits []string, err error) {
+type redditAbout struct {
+	Data struct {
+		LinkKarma    int `json:"link_karma"`
+		CommentKarma int `json:"comment_karma"`
+		TotalKarma   int `json:"total_karma"`
+	} `json:"data"`
+}
+
+// redditCredentials holds the script-app credentials of an OAuth-mode source.
+// Public sources store the literal token "public" instead.
+type redditCredentials struct {
+	ClientID     string
+	ClientSecret string
+	Password     string
+}
+
+type redditSession struct {
+	httpClient *http.Client
+	baseURL    string
+	userAgent  string
+	token      string
+	rateLimit  time.Duration
+}
+
+func parseRedditCredentials(accessToken string) *redditCredentials {
+	parts := strings.Split(accessToken, ":::")
+	if len(parts) != 3 {
+		return nil
+	}
+	return &redditCredentials{
+		ClientID:     parts[0],
+		ClientSecret: parts[1],
+		Password:     parts[2],
+	}
+}
+
+func getRedditDetails(ctx context.Context, dbQueries *database.Queries, encryptionKey []byte, sid uuid.UUID) (username string, subreddits []string, creds *redditCredentials, err error) {
 	userSource, err := dbQueries.GetSourceById(ctx, sid)
 	if err != nil {
-		return "", nil, err
+		return "", nil, nil, err
 	}
 	username = userSource.UserName
 
-	_, profileID, _, _, tokenErr := authhelp.GetSourceToken(ctx, dbQueries, encryptionKey, sid)
-	if tokenErr == nil && profileID != "" {
+	accessToken, profileID, _, _, tokenErr := authhelp.GetSourceToken(ctx, dbQueries, encryptionKey, sid)
+	if tokenErr == nil {
+		creds = parseRedditCredentials(accessToken)
 		for _, s := range strings.Split(profileID, ",") {
 			s = strings.TrimSpace(s)
 			if s != "" {
@@ -68,109 +109,106 @@ func getRedditDetails(ctx context.Context, dbQueries *database.Queries, encrypti
 		}
 	}
 
-	return username, subreddits, nil
+	return username, subreddits, creds, nil
 }
 
-func handleSubredditChanges(ctx context.Context, dbQueries *database.Queries, sourceID uuid.UUID, newSubreddits []string) {
-	if len(newSubreddits) == 0 {
-		return
-	}
+func redditGetOAuthToken(httpClient *http.Client, username, userAgent string, creds *redditCredentials) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "password")
+	form.Set("username", username)
+	form.Set("password", creds.Password)
 
-	newSet := make(map[string]bool)
-	for _, s := range newSubreddits {
-		newSet[strings.ToLower(s)] = true
+	req, err := http.NewRequest("POST", "https://www.reddit.com/api/v1/access_token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
 	}
+	req.SetBasicAuth(creds.ClientID, creds.ClientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", userAgent)
 
-	posts, err := dbQueries.GetNetworkIdsAndContentBySource(ctx, sourceID)
+	resp, err := httpClient.Do(req)
 	if err != nil {
-		log.Printf("Reddit: Failed to get existing posts for subreddit pruning: %v", err)
-		return
+		return "", err
 	}
+	defer resp.Body.Close()
 
-	removedSubreddits := make(map[string]bool)
-	for _, post := range posts {
-		if !post.Content.Valid {
-			continue
-		}
-		if !strings.HasPrefix(post.Content.String, "r/") {
-			continue
-		}
-		colon := strings.Index(post.Content.String, ":")
-		if colon < 3 {
-			continue
-		}
-		subreddit := strings.ToLower(post.Content.String[2:colon])
-		if !newSet[subreddit] {
-			removedSubreddits[subreddit] = true
-		}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
 	}
 
-	for subreddit := range removedSubreddits {
-		pattern := "r/" + subreddit + ":%"
-		err := dbQueries.DeletePostsByContentPrefix(ctx, database.DeletePostsByContentPrefixParams{
-			SourceID: sourceID,
-			Content:  sql.NullString{String: pattern, Valid: true},
-		})
-		if err != nil {
-			log.Printf("Reddit: Failed to delete posts for removed subreddit %s: %v", subreddit, err)
-		} else {
-			log.Printf("Reddit: Deleted posts for removed subreddit %s", subreddit)
-		}
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("Reddit token endpoint returned %d: %s", resp.StatusCode, string(body))
 	}
-}
-
-func FetchRedditPosts(dbQueries *database.Queries, encryptionKey []byte, sourceId uuid.UUID, _ *common.Client) error {
-	ctx := context.Background()
 
-	username, subreddits, err := getRedditDetails(ctx, dbQueries, encryptionKey, sourceId)
-	if err != nil {
-		return err
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+		Error       string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return "", fmt.Errorf("failed to decode Reddit token response: %w", err)
+	}
+	if tokenResp.AccessToken == "" {
+		return "", fmt.Errorf("Reddit token request failed: %s", tokenResp.Error)
 	}
 
-	handleSubredditChanges(ctx, dbQueries, sourceId, subreddits)
+	return tokenResp.AccessToken, nil
+}
 
+func newRedditSession(username string, creds *redditCredentials, c *common.Client) (*redditSession, error) {
 	userAgent := fmt.Sprintf("rpsync.net:%s (for /u/%s)", config.AppVersion, username)
-
-	exclusionMap, err := common.LoadExclusionMap(dbQueries, sourceId)
-	if err != nil {
-		return err
+	httpClient := c.WrapHTTPClient(redditHTTPClient)
+
+	if creds == nil {
+		return &redditSession{
+			httpClient: httpClient,
+			baseURL:    "https://www.reddit.com",
+			userAgent:  userAgent,
+			rateLimit:  common.ScraperRateLimit,
+		}, nil
 	}
 
-	subredditFilter := make(map[string]bool)
-	for _, s := range subreddits {
-		subredditFilter[s] = true
+	token, err := redditGetOAuthToken(httpClient, username, userAgent, creds)
+	if err != nil {
+		return nil, err
 	}
 
-	processedPosts := make(map[string]struct{})
-	var after string
-	const maxPages = 500
+	return &redditSession{
+		httpClient: httpClient,
+		baseURL:    "https://oauth.reddit.com",
+		userAgent:  userAgent,
+		token:      token,
+		rateLimit:  common.APIRateLimit,
+	}, nil
+}
 
-	for page := 0; page < maxPages; page++ {
-		time.Sleep(common.ScraperRateLimit)
+func (rs *redditSession) get(path string) ([]byte, error) {
+	const maxAttempts = 5
 
-		apiURL := fmt.Sprintf("https://www.reddit.com/user/%s/submitted.json?limit=100&raw_json=1", username)
-		if after != "" {
-			apiURL += "&after=" + after
-		}
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		time.Sleep(rs.rateLimit)
 
-		req, err := http.NewRequest("GET", apiURL, nil)
+		req, err := http.NewRequest("GET", rs.baseURL+path, nil)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		req.Header.Set("User-Agent", userAgent)
+		req.Header.Set("User-Agent", rs.userAgent)
 		req.Header.Set("Accept", "application/json, */*;q=0.9")
 		req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+		if rs.token != "" {
+			req.Header.Set("Authorization", "Bearer "+rs.token)
+		}
 
-		resp, err := redditHTTPClient.Do(req)
+		resp, err := rs.httpClient.Do(req)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		body, err := io.ReadAll(resp.Body)
 		resp.Body.Close()
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		if resp.StatusCode == 429 {
@@ -202,7 +240,101 @@ func FetchRedditPosts(dbQueries *database.Queries, encryptionKey []byte, sourceI
 			if len(snippet) > 300 {
 				snippet = snippet[:300] + "..."
 			}
-			return fmt.Errorf("Reddit API returned status %d: %s", resp.StatusCode, snippet)
+			return nil, fmt.Errorf("Reddit API returned status %d: %s", resp.StatusCode, snippet)
+		}
+
+		return body, nil
+	}
+
+	return nil, errors.New("Reddit API kept rate limiting requests")
+}
+
+func (rs *redditSession) fetchKarma(username string) (int, error) {
+	path := fmt.Sprintf("/user/%s/about.json?raw_json=1", username)
+	if rs.token != "" {
+		path = fmt.Sprintf("/user/%s/about?raw_json=1", username)
+	}
+
+	body, err := rs.get(path)
+	if err != nil {
+		return 0, err
+	}
+
+	var about redditAbout
+	if err := json.Unmarshal(body, &about); err != nil {
+		return 0, fmt.Errorf("failed to decode Reddit profile: %w", err)
+	}
+
+	if about.Data.TotalKarma > 0 {
+		return about.Data.TotalKarma, nil
+	}
+	return about.Data.LinkKarma + about.Data.CommentKarma, nil
+}
+
+func handleSubredditChanges(ctx context.Context, dbQueries *database.Queries, sourceID uuid.UUID, newSubreddits []string) {
+	if len(newSubreddits) == 0 {
+		return
+	}
+
+	newSet := make(map[string]bool)
+	for _, s := range newSubreddits {
+		newSet[strings.ToLower(s)] = true
+	}
+
+	posts, err := dbQueries.GetNetworkIdsAndContentBySource(ctx, sourceID)
+	if err != nil {
+		log.Printf("Reddit: Failed to get existing posts for subreddit pruning: %v", err)
+		return
+	}
+
+	removedSubreddits := make(map[string]bool)
+	for _, post := range posts {
+		if !post.Content.Valid {
+			continue
+		}
+		if !strings.HasPrefix(post.Content.String, "r/") {
+			continue
+		}
+		colon := strings.Index(post.Content.String, ":")
+		if colon < 3 {
+			continue
+		}
+		subreddit := strings.ToLower(post.Content.String[2:colon])
+		if !newSet[subreddit] {
+			removedSubreddits[subreddit] = true
+		}
+	}
+
+	for subreddit := range removedSubreddits {
+		pattern := "r/" + subreddit + ":%"
+		err := dbQueries.DeletePostsByContentPrefix(ctx, database.DeletePostsByContentPrefixParams{
+			SourceID: sourceID,
+			Content:  sql.NullString{String: pattern, Valid: true},
+		})
+		if err != nil {
+			log.Printf("Reddit: Failed to delete posts for removed subreddit %s: %v", subreddit, err)
+		} else {
+			log.Printf("Reddit: Deleted posts for removed subreddit %s", subreddit)
+		}
+	}
+}
+
+// syncRedditListing pages through a single submitted listing. Reddit caps
+// every listing at roughly 1000 items, so OAuth sources walk several sort
+// orders of the same listing to reach older submissions.
+func syncRedditListing(ctx context.Context, dbQueries *database.Queries, rs *redditSession, listingPath string, sourceId uuid.UUID, subredditFilter map[string]bool, exclusionMap map[string]bool, processedPosts map[string]struct{}) error {
+	var after string
+	const maxPages = 500
+
+	for page := 0; page < maxPages; page++ {
+		path := listingPath
+		if after != "" {
+			path += "&after=" + after
+		}
+
+		body, err := rs.get(path)
+		if err != nil {
+			return err
 		}
 
 		var listing redditListing
@@ -267,12 +399,30 @@ func FetchRedditPosts(dbQueries *database.Queries, encryptionKey []byte, sourceI
 					Int64: int64(post.Score),
 					Valid: true,
 				},
-				Reposts: sql.NullInt64{Valid: false},
-				Views:   sql.NullInt64{Valid: false},
+				Reposts: sql.NullInt64{
+					Int64: int64(post.NumCrossposts),
+					Valid: true,
+				},
+				Views: sql.NullInt64{Valid: false},
+				Comments: sql.NullInt64{
+					Int64: int64(post.NumComments),
+					Valid: true,
+				},
 			})
 			if err != nil {
 				log.Printf("Reddit: Failed to sync reactions for post %s: %v", postID, err)
 			}
+
+			err = dbQueries.UpsertRedditPostDetails(ctx, database.UpsertRedditPostDetailsParams{
+				PostID:        internalID,
+				UpdatedAt:     time.Now(),
+				Subreddit:     strings.ToLower(post.Subreddit),
+				UpvoteRatio:   post.UpvoteRatio,
+				NumCrossposts: int64(post.NumCrossposts),
+			})
+			if err != nil {
+				log.Printf("Reddit: Failed to save details for post %s: %v", postID, err)
+			}
 		}
 
 		if listing.Data.After == "" {
@@ -282,6 +432,54 @@ func FetchRedditPosts(dbQueries *database.Queries, encryptionKey []byte, sourceI
 		after = listing.Data.After
 	}
 
+	return nil
+}
+
+func FetchRedditPosts(dbQueries *database.Queries, encryptionKey []byte, sourceId uuid.UUID, c *common.Client) error {
+	ctx := context.Background()
+
+	username, subreddits, creds, err := getRedditDetails(ctx, dbQueries, encryptionKey, sourceId)
+	if err != nil {
+		return err
+	}
+
+	handleSubredditChanges(ctx, dbQueries, sourceId, subreddits)
+
+	rs, err := newRedditSession(username, creds, c)
+	if err != nil {
+		return fmt.Errorf("Reddit: failed to authenticate: %w", err)
+	}
+
+	exclusionMap, err := common.LoadExclusionMap(dbQueries, sourceId)
+	if err != nil {
+		return err
+	}
+
+	subredditFilter := make(map[string]bool)
+	for _, s := range subreddits {
+		subredditFilter[s] = true
+	}
+
+	processedPosts := make(map[string]struct{})
+
+	listings := []string{fmt.Sprintf("/user/%s/submitted.json?limit=100&raw_json=1", username)}
+	if creds != nil {
+		listings = []string{
+			fmt.Sprintf("/user/%s/submitted?limit=100&raw_json=1&sort=new", username),
+			fmt.Sprintf("/user/%s/submitted?limit=100&raw_json=1&sort=top&t=all", username),
+			fmt.Sprintf("/user/%s/submitted?limit=100&raw_json=1&sort=controversial&t=all", username),
+		}
+	}
+
+	for i, listingPath := range listings {
+		if err := syncRedditListing(ctx, dbQueries, rs, listingPath, sourceId, subredditFilter, exclusionMap, processedPosts); err != nil {
+			if i == 0 {
+				return err
+			}
+			log.Printf("Reddit: Failed to page additional listing: %v", err)
+		}
+	}
+
 	if len(processedPosts) == 0 {
 		return errors.New("no posts found for Reddit user")
 	}
@@ -289,10 +487,18 @@ func FetchRedditPosts(dbQueries *database.Queries, encryptionKey []byte, sourceI
 	avgStats, err := common.CalculateAverageStats(ctx, dbQueries, sourceId)
 	if err != nil {
 		log.Printf("Reddit: Failed to calculate average stats: %v", err)
+		avgStats = &common.ProfileStats{}
+	}
+
+	karma, err := rs.fetchKarma(username)
+	if err != nil {
+		log.Printf("Reddit: Failed to fetch karma: %v", err)
 	} else {
-		if err := common.SaveOrUpdateSourceStats(ctx, dbQueries, sourceId, avgStats); err != nil {
-			log.Printf("Reddit: Failed to save stats: %v", err)
-		}
+		avgStats.Karma = &karma
+	}
+
+	if err := common.SaveOrUpdateSourceStats(ctx, dbQueries, sourceId, avgStats); err != nil {
+		log.Printf("Reddit: Failed to save stats: %v", err)
 	}
 
 	return nil
@@ -239,6 +239,10 @@ func FetchTelegramPosts(dbQueries *database.Queries, encryptionKey []byte, sourc
 					if err != nil {
 						log.Printf("[WARN] Failed to sync reactions for post ID=%d: %v", msg.ID, err)
 					}
+
+					if err := common.SyncReactionBreakdown(ctx, dbQueries, postID, telegramReactionCounts(msg)); err != nil {
+						log.Printf("[WARN] Failed to sync reaction breakdown for post ID=%d: %v", msg.ID, err)
+					}
 				}
 			}
 
@@ -270,6 +274,30 @@ func FetchTelegramPosts(dbQueries *database.Queries, encryptionKey []byte, sourc
 	})
 }
 
+func telegramReactionCounts(msg *tg.Message) map[string]int64 {
+	counts := make(map[string]int64)
+
+	reactions, ok := msg.GetReactions()
+	if !ok {
+		return counts
+	}
+
+	for _, r := range reactions.Results {
+		var key string
+		switch reaction := r.Reaction.(type) {
+		case *tg.ReactionEmoji:
+			key = reaction.Emoticon
+		case *tg.ReactionCustomEmoji:
+			key = fmt.Sprintf("custom:%d", reaction.DocumentID)
+		case *tg.ReactionPaid:
+			key = "paid"
+		}
+		counts[key] += int64(r.Count)
+	}
+
+	return counts
+}
+
 func FetchTelegramWebStats(channel string, messageID int, c *common.Client) (int, error) {
 	url := fmt.Sprintf("https://t.me/%s/%d?embed=1&mode=tme", channel, messageID)
 
@@ -10,10 +10,10 @@ import (
 	"strings"
 	"time"
 
-	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/chromedp"
 	"github.com/fluffyriot/rpsync/internal/database"
 	"github.com/fluffyriot/rpsync/internal/fetcher/common"
+	"github.com/fluffyriot/rpsync/internal/fetcher/cookies"
 	"github.com/google/uuid"
 )
 
@@ -28,7 +28,7 @@ type ScrapedPost struct {
 	IsScraped bool   `json:"is_scraped"`
 }
 
-func FetchTikTokPosts(dbQueries *database.Queries, c *common.Client, uid uuid.UUID, sourceId uuid.UUID) error {
+func FetchTikTokPosts(dbQueries *database.Queries, encryptionKey []byte, c *common.Client, uid uuid.UUID, sourceId uuid.UUID) error {
 
 	exclusionMap, err := common.LoadExclusionMap(dbQueries, sourceId)
 	if err != nil {
@@ -42,42 +42,18 @@ func FetchTikTokPosts(dbQueries *database.Queries, c *common.Client, uid uuid.UU
 
 	username := source.UserName
 
-	cookies, err := loadCookies(username)
+	jar, err := loadTikTokCookies(context.Background(), dbQueries, encryptionKey, sourceId, username)
 	if err != nil {
 		return fmt.Errorf("failed to load cookies for %s: %w. Please re-authenticate", username, err)
 	}
 
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"),
-		chromedp.WindowSize(1920, 1080),
-		chromedp.Flag("headless", true),
-		chromedp.Flag("no-sandbox", true),
-		chromedp.Flag("disable-dev-shm-usage", true),
-	)
-
-	allocCtx, cancel := chromedp.NewExecAllocator(context.Background(), opts...)
-	defer cancel()
-
-	ctx, cancel := chromedp.NewContext(allocCtx)
-	defer cancel()
+	ctx, release, err := c.NewBrowserTab(context.Background())
+	if err != nil {
+		return err
+	}
+	defer release()
 
-	err = chromedp.Run(ctx,
-		chromedp.ActionFunc(func(ctx context.Context) error {
-			for _, cookie := range cookies {
-				err := network.SetCookie(cookie.Name, cookie.Value).
-					WithDomain(cookie.Domain).
-					WithPath(cookie.Path).
-					WithSecure(cookie.Secure).
-					WithHTTPOnly(cookie.HTTPOnly).
-					WithSameSite(cookie.SameSite).
-					Do(ctx)
-				if err != nil {
-					return err
-				}
-			}
-			return nil
-		}),
-	)
+	err = chromedp.Run(ctx, cookies.SetAll(jar))
 	if err != nil {
 		return fmt.Errorf("failed to set cookies: %w", err)
 	}
@@ -130,7 +106,7 @@ func FetchTikTokPosts(dbQueries *database.Queries, c *common.Client, uid uuid.UU
 	`
 
 	err = chromedp.Run(ctx,
-		chromedp.Navigate(url),
+		chromedp.Navigate(c.ResolveURL(url)),
 		chromedp.Sleep(5*time.Second),
 		chromedp.ActionFunc(func(ctx context.Context) error {
 			var currentURL string
@@ -249,7 +225,7 @@ func FetchTikTokPosts(dbQueries *database.Queries, c *common.Client, uid uuid.UU
 	var followersCount *int
 	var followingCount *int
 	err = chromedp.Run(ctx,
-		chromedp.Navigate(fmt.Sprintf("https://www.tiktok.com/@%s", username)),
+		chromedp.Navigate(c.ResolveURL(fmt.Sprintf("https://www.tiktok.com/@%s", username))),
 		chromedp.Sleep(3*time.Second),
 		chromedp.ActionFunc(func(ctx context.Context) error {
 			var followerText string
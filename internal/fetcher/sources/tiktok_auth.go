@@ -3,7 +3,7 @@ package sources
 
 import (
 	"context"
-	"encoding/json"
+	"database/sql"
 	"errors"
 	"fmt"
 	"log"
@@ -15,6 +15,10 @@ import (
 
 	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/chromedp"
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/fetcher/common"
+	"github.com/fluffyriot/rpsync/internal/fetcher/cookies"
+	"github.com/google/uuid"
 )
 
 type TikTokManager struct {
@@ -32,19 +36,20 @@ type LoginSession struct {
 
 var (
 	GlobalTikTokManager *TikTokManager
-	cookiesDir          = "outputs/tiktok_cookies"
+	// legacyCookiesDir is where cookies were stored before they moved to the
+	// tokens table. Files found there are migrated on first use.
+	legacyCookiesDir = "outputs/tiktok_cookies"
 )
 
 func init() {
 	GlobalTikTokManager = &TikTokManager{
 		sessions: make(map[string]*LoginSession),
 	}
-	if err := os.MkdirAll(cookiesDir, 0700); err != nil {
-		log.Printf("Failed to create cookies directory: %v", err)
-	}
 }
 
-func (tm *TikTokManager) StartLoginSession(username string) ([]byte, error) {
+// StartLoginSession opens a login page and returns its QR code. ctx bounds
+// the wait for a browser tab; the login keeps running in the background.
+func (tm *TikTokManager) StartLoginSession(ctx context.Context, dbQueries *database.Queries, encryptionKey []byte, sourceID uuid.UUID, username string, c *common.Client) ([]byte, error) {
 	tm.mu.Lock()
 	session := &LoginSession{
 		Username:  username,
@@ -54,30 +59,20 @@ func (tm *TikTokManager) StartLoginSession(username string) ([]byte, error) {
 	tm.sessions[username] = session
 	tm.mu.Unlock()
 
-	allocCtx, allocCancel := chromedp.NewExecAllocator(context.Background(), append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/121.0.0.0 Safari/537.36"),
-		chromedp.Flag("headless", true),
-		chromedp.Flag("disable-gpu", true),
-		chromedp.Flag("no-sandbox", true),
-		chromedp.Flag("disable-setuid-sandbox", true),
-		chromedp.Flag("disable-dev-shm-usage", true),
-		chromedp.Flag("enable-automation", false),
-		chromedp.Flag("disable-blink-features", "AutomationControlled"),
-		chromedp.Flag("disable-infobars", true),
-		chromedp.Flag("no-first-run", true),
-		chromedp.Flag("no-default-browser-check", true),
-		chromedp.Flag("enable-features", "NetworkService,NetworkServiceInProcess"),
-		chromedp.WindowSize(1280, 800),
-	)...)
-
-	ctx, ctxCancel := chromedp.NewContext(allocCtx)
-
-	qrCodeChan := make(chan []byte)
-	errChan := make(chan error)
+	ctx, release, err := c.NewBrowserTab(ctx)
+	if err != nil {
+		tm.mu.Lock()
+		session.Status = "failed"
+		session.Error = err.Error()
+		tm.mu.Unlock()
+		return nil, err
+	}
+
+	qrCodeChan := make(chan []byte, 1)
+	errChan := make(chan error, 1)
 
 	go func() {
-		defer allocCancel()
-		defer ctxCancel()
+		defer release()
 
 		var screenshot []byte
 		var title string
@@ -168,8 +163,12 @@ func (tm *TikTokManager) StartLoginSession(username string) ([]byte, error) {
 			return
 		}
 
-		if err := saveCookies(username, networkCookies); err != nil {
+		if err := cookies.Store(context.Background(), dbQueries, encryptionKey, sourceID, cookies.FromCDP(networkCookies)); err != nil {
 			log.Printf("Failed to save cookies: %v", err)
+			tm.mu.Lock()
+			session.Status = "failed"
+			session.Error = "Failed to save session cookies"
+			tm.mu.Unlock()
 			return
 		}
 
@@ -198,39 +197,45 @@ func (tm *TikTokManager) CheckStatus(username string) (string, string, error) {
 	return session.Status, session.Error, nil
 }
 
-func saveCookies(username string, cookies []*network.Cookie) error {
-	safeUsername, err := sanitizeUsername(username)
-	if err != nil {
-		return fmt.Errorf("invalid username: %w", err)
+// loadTikTokCookies reads the session cookies stored for a source, moving
+// them over from the legacy cookie file if there is no token yet.
+func loadTikTokCookies(ctx context.Context, dbQueries *database.Queries, encryptionKey []byte, sourceID uuid.UUID, username string) ([]cookies.Cookie, error) {
+	jar, err := cookies.Load(ctx, dbQueries, encryptionKey, sourceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return migrateLegacyTikTokCookies(ctx, dbQueries, encryptionKey, sourceID, username)
 	}
-
-	data, err := json.Marshal(cookies)
-	if err != nil {
-		return err
-	}
-	path := filepath.Join(cookiesDir, fmt.Sprintf("tiktok_%s.json", safeUsername))
-	if err := os.WriteFile(path, data, 0600); err != nil {
-		return err
-	}
-	return os.Chmod(path, 0600)
+	return jar, err
 }
 
-func loadCookies(username string) ([]*network.Cookie, error) {
+func migrateLegacyTikTokCookies(ctx context.Context, dbQueries *database.Queries, encryptionKey []byte, sourceID uuid.UUID, username string) ([]cookies.Cookie, error) {
 	safeUsername, err := sanitizeUsername(username)
 	if err != nil {
 		return nil, fmt.Errorf("invalid username: %w", err)
 	}
 
-	path := filepath.Join(cookiesDir, fmt.Sprintf("tiktok_%s.json", safeUsername))
+	path := filepath.Join(legacyCookiesDir, fmt.Sprintf("tiktok_%s.json", safeUsername))
 	data, err := os.ReadFile(path)
 	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, errors.New("no session cookies stored")
+		}
 		return nil, err
 	}
-	var cookies []*network.Cookie
-	if err := json.Unmarshal(data, &cookies); err != nil {
+
+	jar, _, err := cookies.Parse(data)
+	if err != nil {
 		return nil, err
 	}
-	return cookies, nil
+
+	if err := cookies.Store(ctx, dbQueries, encryptionKey, sourceID, jar); err != nil {
+		return nil, fmt.Errorf("failed to migrate cookie file: %w", err)
+	}
+	if err := os.Remove(path); err != nil {
+		log.Printf("TikTok: Failed to remove migrated cookie file %s: %v", path, err)
+	}
+	log.Printf("TikTok: Moved cookies for %s into the database", username)
+
+	return jar, nil
 }
 
 func sanitizeUsername(username string) (string, error) {
@@ -407,6 +407,8 @@ func FetchTwitchPosts(dbQueries *database.Queries, encryptionKey []byte, sourceI
 		clipCursor = clipsResp.Pagination.Cursor
 	}
 
+	syncTwitchStreamPosts(ctx, dbQueries, sourceId, username, exclusionMap, processedPosts)
+
 	if len(processedPosts) == 0 {
 		return fmt.Errorf("no content found for Twitch user %q", username)
 	}
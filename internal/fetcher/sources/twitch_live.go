@@ -0,0 +1,277 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package sources
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/authhelp"
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/fetcher/common"
+	"github.com/google/uuid"
+)
+
+const twitchStreamPostPrefix = "stream-"
+
+type twitchStream struct {
+	ID          string `json:"id"`
+	UserLogin   string `json:"user_login"`
+	GameName    string `json:"game_name"`
+	Type        string `json:"type"`
+	Title       string `json:"title"`
+	ViewerCount int64  `json:"viewer_count"`
+	StartedAt   string `json:"started_at"`
+}
+
+type twitchStreamsResponse struct {
+	Data []twitchStream `json:"data"`
+}
+
+type twitchCachedToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+var (
+	twitchTokenCacheMu sync.Mutex
+	twitchTokenCache   = make(map[string]twitchCachedToken)
+)
+
+func twitchGetCachedAppToken(clientID, clientSecret string, c *common.Client) (string, error) {
+	twitchTokenCacheMu.Lock()
+	cached, ok := twitchTokenCache[clientID]
+	twitchTokenCacheMu.Unlock()
+
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.token, nil
+	}
+
+	token, err := twitchGetAppToken(clientID, clientSecret, c)
+	if err != nil {
+		return "", err
+	}
+
+	twitchTokenCacheMu.Lock()
+	twitchTokenCache[clientID] = twitchCachedToken{token: token, expiresAt: time.Now().Add(time.Hour)}
+	twitchTokenCacheMu.Unlock()
+
+	return token, nil
+}
+
+func twitchStreamContent(title, category string) string {
+	if category == "" {
+		return title
+	}
+	return title + "\n\n(Streaming " + category + ")"
+}
+
+// PollTwitchLive samples the Helix streams endpoint once for a source and
+// reports whether the channel is currently live.
+func PollTwitchLive(dbQueries *database.Queries, encryptionKey []byte, sourceId uuid.UUID, c *common.Client) (bool, error) {
+	ctx := context.Background()
+
+	source, err := dbQueries.GetSourceById(ctx, sourceId)
+	if err != nil {
+		return false, err
+	}
+
+	clientSecret, clientID, _, _, err := authhelp.GetSourceToken(ctx, dbQueries, encryptionKey, sourceId)
+	if err != nil {
+		return false, fmt.Errorf("Twitch: failed to get credentials: %w", err)
+	}
+
+	appToken, err := twitchGetCachedAppToken(clientID, clientSecret, c)
+	if err != nil {
+		return false, fmt.Errorf("Twitch: failed to get app token: %w", err)
+	}
+
+	req, err := http.NewRequest("GET", "https://api.twitch.tv/helix/streams?user_login="+url.QueryEscape(source.UserName), nil)
+	if err != nil {
+		return false, err
+	}
+
+	body, status, err := twitchDoRequest(req, clientID, appToken, c)
+	if err != nil {
+		return false, err
+	}
+
+	if status == 401 {
+		twitchTokenCacheMu.Lock()
+		delete(twitchTokenCache, clientID)
+		twitchTokenCacheMu.Unlock()
+	}
+
+	if status != 200 {
+		return false, fmt.Errorf("Twitch streams API returned %d: %s", status, string(body))
+	}
+
+	var resp twitchStreamsResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return false, fmt.Errorf("failed to decode Twitch streams response: %w", err)
+	}
+
+	now := time.Now()
+
+	open, err := dbQueries.GetOpenStreamSessionBySource(ctx, sourceId)
+	hasOpen := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+
+	if len(resp.Data) == 0 || resp.Data[0].Type != "live" {
+		if hasOpen {
+			if err := dbQueries.EndStreamSession(ctx, database.EndStreamSessionParams{
+				ID:      open.ID,
+				EndedAt: sql.NullTime{Time: open.LastSeenAt, Valid: true},
+			}); err != nil {
+				return false, err
+			}
+			log.Printf("Twitch: stream %s for %s ended", open.StreamID, source.UserName)
+		}
+		return false, nil
+	}
+
+	stream := resp.Data[0]
+
+	if hasOpen && open.StreamID != stream.ID {
+		if err := dbQueries.EndStreamSession(ctx, database.EndStreamSessionParams{
+			ID:      open.ID,
+			EndedAt: sql.NullTime{Time: open.LastSeenAt, Valid: true},
+		}); err != nil {
+			return false, err
+		}
+		hasOpen = false
+	}
+
+	startedAt, err := time.Parse(time.RFC3339, stream.StartedAt)
+	if err != nil {
+		startedAt = now
+	}
+
+	postID, err := common.CreateOrUpdatePost(
+		ctx,
+		dbQueries,
+		sourceId,
+		twitchStreamPostPrefix+stream.ID,
+		"Twitch",
+		startedAt,
+		"stream",
+		source.UserName,
+		twitchStreamContent(stream.Title, stream.GameName),
+	)
+	if err != nil {
+		return true, fmt.Errorf("Twitch: failed to save stream %s: %w", stream.ID, err)
+	}
+
+	session := open
+	if !hasOpen {
+		// A stream missing from one poll and then back under the same ID
+		// reopens its session.
+		session, err = dbQueries.CreateStreamSession(ctx, database.CreateStreamSessionParams{
+			ID:         uuid.New(),
+			SourceID:   sourceId,
+			PostID:     uuid.NullUUID{UUID: postID, Valid: true},
+			StreamID:   stream.ID,
+			Title:      stream.Title,
+			Category:   stream.GameName,
+			StartedAt:  startedAt,
+			LastSeenAt: now,
+		})
+		if err != nil {
+			return true, err
+		}
+		log.Printf("Twitch: %s went live (stream %s)", source.UserName, stream.ID)
+	}
+
+	session, err = dbQueries.RecordStreamSessionSample(ctx, database.RecordStreamSessionSampleParams{
+		Title:       stream.Title,
+		Category:    stream.GameName,
+		LastSeenAt:  now,
+		ViewerCount: stream.ViewerCount,
+		ID:          session.ID,
+	})
+	if err != nil {
+		return true, err
+	}
+
+	if err := dbQueries.CreateStreamViewerSample(ctx, database.CreateStreamViewerSampleParams{
+		ID:          uuid.New(),
+		SessionID:   session.ID,
+		SampledAt:   now,
+		ViewerCount: stream.ViewerCount,
+	}); err != nil {
+		log.Printf("Twitch: failed to save viewer sample for stream %s: %v", stream.ID, err)
+	}
+
+	_, err = dbQueries.SyncReactions(ctx, database.SyncReactionsParams{
+		ID:       uuid.New(),
+		SyncedAt: now,
+		PostID:   postID,
+		Likes:    sql.NullInt64{Valid: false},
+		Reposts:  sql.NullInt64{Valid: false},
+		Views: sql.NullInt64{
+			Int64: session.PeakViewers,
+			Valid: true,
+		},
+	})
+	if err != nil {
+		log.Printf("Twitch: failed to sync reactions for stream %s: %v", stream.ID, err)
+	}
+
+	return true, nil
+}
+
+func syncTwitchStreamPosts(ctx context.Context, dbQueries *database.Queries, sourceId uuid.UUID, username string, exclusionMap map[string]bool, processedPosts map[string]struct{}) {
+	sessions, err := dbQueries.GetStreamSessionsBySource(ctx, sourceId)
+	if err != nil {
+		log.Printf("Twitch: failed to load stream sessions: %v", err)
+		return
+	}
+
+	for _, s := range sessions {
+		networkID := twitchStreamPostPrefix + s.StreamID
+		if exclusionMap[networkID] {
+			continue
+		}
+		processedPosts[networkID] = struct{}{}
+
+		postID, err := common.CreateOrUpdatePost(
+			ctx,
+			dbQueries,
+			sourceId,
+			networkID,
+			"Twitch",
+			s.StartedAt,
+			"stream",
+			username,
+			twitchStreamContent(s.Title, s.Category),
+		)
+		if err != nil {
+			log.Printf("Twitch: failed to save stream %s: %v", s.StreamID, err)
+			continue
+		}
+
+		_, err = dbQueries.SyncReactions(ctx, database.SyncReactionsParams{
+			ID:       uuid.New(),
+			SyncedAt: time.Now(),
+			PostID:   postID,
+			Likes:    sql.NullInt64{Valid: false},
+			Reposts:  sql.NullInt64{Valid: false},
+			Views: sql.NullInt64{
+				Int64: s.PeakViewers,
+				Valid: true,
+			},
+		})
+		if err != nil {
+			log.Printf("Twitch: failed to sync reactions for stream %s: %v", s.StreamID, err)
+		}
+	}
+}
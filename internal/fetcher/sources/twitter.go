@@ -16,22 +16,12 @@ import (
 	"github.com/chromedp/cdproto/cdp"
 	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/chromedp"
-	"github.com/fluffyriot/rpsync/internal/authhelp"
 	"github.com/fluffyriot/rpsync/internal/database"
 	"github.com/fluffyriot/rpsync/internal/fetcher/common"
+	"github.com/fluffyriot/rpsync/internal/fetcher/cookies"
 	"github.com/google/uuid"
 )
 
-type twitterCookieEntry struct {
-	Name     string  `json:"name"`
-	Value    string  `json:"value"`
-	Domain   string  `json:"domain"`
-	Path     string  `json:"path"`
-	Secure   bool    `json:"secure"`
-	HTTPOnly bool    `json:"httpOnly"`
-	SameSite *string `json:"sameSite"`
-}
-
 type twitterTweetsResp struct {
 	Data struct {
 		User struct {
@@ -153,33 +143,16 @@ func FetchTwitterPosts(dbQueries *database.Queries, c *common.Client, username s
 		return err
 	}
 
-	cookieJSON, _, _, _, err := authhelp.GetSourceToken(context.Background(), dbQueries, encryptionKey, sourceID)
+	jar, err := cookies.Load(context.Background(), dbQueries, encryptionKey, sourceID)
 	if err != nil {
-		return fmt.Errorf("failed to load twitter credentials: %w", err)
+		return fmt.Errorf("failed to load twitter cookies: %w", err)
 	}
 
-	var cookies []twitterCookieEntry
-	if err := json.Unmarshal([]byte(cookieJSON), &cookies); err != nil {
-		return fmt.Errorf("failed to parse twitter cookie JSON: %w", err)
+	ctx, release, err := c.NewBrowserTab(context.Background())
+	if err != nil {
+		return err
 	}
-
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"),
-		chromedp.WindowSize(1920, 1080),
-		chromedp.Flag("headless", true),
-		chromedp.Flag("no-sandbox", true),
-		chromedp.Flag("disable-dev-shm-usage", true),
-		chromedp.Flag("disable-blink-features", "AutomationControlled"),
-		chromedp.Flag("enable-automation", false),
-		chromedp.Flag("no-first-run", true),
-		chromedp.Flag("no-default-browser-check", true),
-	)
-
-	allocCtx, allocCancel := chromedp.NewExecAllocator(context.Background(), opts...)
-	defer allocCancel()
-
-	ctx, ctxCancel := chromedp.NewContext(allocCtx)
-	defer ctxCancel()
+	defer release()
 
 	bodyChan := make(chan []byte, 100)
 	var trackedRequests sync.Map
@@ -214,38 +187,14 @@ func FetchTwitterPosts(dbQueries *database.Queries, c *common.Client, username s
 
 	if err := chromedp.Run(ctx,
 		network.Enable(),
-		chromedp.ActionFunc(func(ctx context.Context) error {
-			for _, cookie := range cookies {
-				params := network.SetCookie(cookie.Name, cookie.Value).
-					WithDomain(cookie.Domain).
-					WithPath(cookie.Path).
-					WithSecure(cookie.Secure).
-					WithHTTPOnly(cookie.HTTPOnly)
-
-				if cookie.SameSite != nil {
-					switch *cookie.SameSite {
-					case "no_restriction":
-						params = params.WithSameSite(network.CookieSameSiteNone)
-					case "lax":
-						params = params.WithSameSite(network.CookieSameSiteLax)
-					case "strict":
-						params = params.WithSameSite(network.CookieSameSiteStrict)
-					}
-				}
-
-				if err := params.Do(ctx); err != nil {
-					return err
-				}
-			}
-			return nil
-		}),
+		cookies.SetAll(jar),
 	); err != nil {
 		return fmt.Errorf("twitter: failed to initialise browser: %w", err)
 	}
 
 	profileURL := fmt.Sprintf("https://twitter.com/%s", username)
 	if err := chromedp.Run(ctx,
-		chromedp.Navigate(profileURL),
+		chromedp.Navigate(c.ResolveURL(profileURL)),
 		chromedp.Sleep(5*time.Second),
 	); err != nil {
 		return fmt.Errorf("twitter: failed to navigate to profile: %w", err)
@@ -13,13 +13,14 @@ import (
 	"github.com/fluffyriot/rpsync/internal/database"
 	"github.com/fluffyriot/rpsync/internal/fetcher/common"
 	"github.com/google/uuid"
+	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/option"
 	"google.golang.org/api/youtube/v3"
 )
 
-func FetchYouTubePosts(dbQueries *database.Queries, sourceId uuid.UUID, encryptionKey []byte) error {
-	ctx := context.Background()
+func FetchYouTubePosts(dbQueries *database.Queries, sourceId uuid.UUID, encryptionKey []byte, c *common.Client) error {
+	ctx := c.OAuth2Context(context.Background())
 
 	source, err := dbQueries.GetSourceById(ctx, sourceId)
 	if err != nil {
@@ -36,7 +37,7 @@ func FetchYouTubePosts(dbQueries *database.Queries, sourceId uuid.UUID, encrypti
 		return fmt.Errorf("failed to parse credentials: %w", err)
 	}
 
-	service, err := youtube.NewService(ctx, option.WithCredentials(creds))
+	service, err := youtube.NewService(ctx, option.WithHTTPClient(oauth2.NewClient(ctx, creds.TokenSource)))
 	if err != nil {
 		return fmt.Errorf("failed to create Youtube service: %w", err)
 	}
@@ -152,5 +153,11 @@ func FetchYouTubePosts(dbQueries *database.Queries, sourceId uuid.UUID, encrypti
 		}
 	}
 
+	if youtubeAnalyticsEnabled([]byte(token)) {
+		if err := FetchYouTubeAnalytics(dbQueries, sourceId, []byte(token), c); err != nil {
+			log.Printf("YouTube Analytics: failed to fetch reports: %v", err)
+		}
+	}
+
 	return nil
 }
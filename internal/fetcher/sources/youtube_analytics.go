@@ -0,0 +1,236 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package sources
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/fetcher/common"
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/option"
+	"google.golang.org/api/youtubeanalytics/v2"
+)
+
+type youtubeDailyMetrics struct {
+	Views                   int64
+	EstimatedMinutesWatched int64
+	AverageViewDuration     float64
+	SubscribersGained       int64
+	SubscribersLost         int64
+}
+
+const youtubeAnalyticsMetrics = "views,estimatedMinutesWatched,averageViewDuration,subscribersGained,subscribersLost"
+
+// The Analytics API only accepts OAuth user credentials; service accounts
+// cannot own a channel, so reports are skipped for them.
+func youtubeAnalyticsEnabled(credsJSON []byte) bool {
+	var creds struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(credsJSON, &creds); err != nil {
+		return false
+	}
+	return creds.Type == "authorized_user"
+}
+
+func youtubeAnalyticsInt(v interface{}) int64 {
+	switch n := v.(type) {
+	case float64:
+		return int64(n)
+	case int64:
+		return n
+	default:
+		return 0
+	}
+}
+
+func youtubeAnalyticsFloat(v interface{}) float64 {
+	if n, ok := v.(float64); ok {
+		return n
+	}
+	return 0
+}
+
+func parseYouTubeDailyRows(resp *youtubeanalytics.QueryResponse) map[time.Time]youtubeDailyMetrics {
+	result := make(map[time.Time]youtubeDailyMetrics)
+
+	for _, row := range resp.Rows {
+		if len(row) < 6 {
+			continue
+		}
+		dateStr, _ := row[0].(string)
+		day, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			continue
+		}
+		result[day] = youtubeDailyMetrics{
+			Views:                   youtubeAnalyticsInt(row[1]),
+			EstimatedMinutesWatched: youtubeAnalyticsInt(row[2]),
+			AverageViewDuration:     youtubeAnalyticsFloat(row[3]),
+			SubscribersGained:       youtubeAnalyticsInt(row[4]),
+			SubscribersLost:         youtubeAnalyticsInt(row[5]),
+		}
+	}
+
+	return result
+}
+
+func FetchYouTubeAnalytics(dbQueries *database.Queries, sourceId uuid.UUID, credsJSON []byte, c *common.Client) error {
+	ctx := c.OAuth2Context(context.Background())
+
+	creds, err := google.CredentialsFromJSON(ctx, credsJSON, youtubeanalytics.YtAnalyticsReadonlyScope)
+	if err != nil {
+		return fmt.Errorf("failed to parse credentials: %w", err)
+	}
+
+	service, err := youtubeanalytics.NewService(ctx, option.WithHTTPClient(oauth2.NewClient(ctx, creds.TokenSource)))
+	if err != nil {
+		return fmt.Errorf("failed to create YouTube Analytics service: %w", err)
+	}
+
+	existing, err := dbQueries.CountYouTubeChannelDailyStatsBySource(ctx, sourceId)
+	if err != nil {
+		log.Printf("YouTube Analytics: error checking existing stats: %v", err)
+	}
+
+	now := time.Now().UTC()
+	startDate := now.AddDate(0, 0, -7).Format("2006-01-02")
+	if existing == 0 {
+		startDate = now.AddDate(-1, 0, 0).Format("2006-01-02")
+	}
+	endDate := now.Format("2006-01-02")
+
+	channelResp, err := service.Reports.Query().
+		Ids("channel==MINE").
+		StartDate(startDate).
+		EndDate(endDate).
+		Metrics(youtubeAnalyticsMetrics).
+		Dimensions("day").
+		Sort("day").
+		Do()
+	if err != nil {
+		return fmt.Errorf("failed to query channel report: %w", err)
+	}
+	channelDays := parseYouTubeDailyRows(channelResp)
+
+	shortsViews := make(map[time.Time]int64)
+	longFormViews := make(map[time.Time]int64)
+
+	splitResp, err := service.Reports.Query().
+		Ids("channel==MINE").
+		StartDate(startDate).
+		EndDate(endDate).
+		Metrics("views").
+		Dimensions("day,creatorContentType").
+		Do()
+	splitOK := err == nil
+	if err != nil {
+		log.Printf("YouTube Analytics: failed to query content type split: %v", err)
+	} else {
+		for _, row := range splitResp.Rows {
+			if len(row) < 3 {
+				continue
+			}
+			dateStr, _ := row[0].(string)
+			day, err := time.Parse("2006-01-02", dateStr)
+			if err != nil {
+				continue
+			}
+			contentType, _ := row[1].(string)
+			if strings.EqualFold(contentType, "shorts") {
+				shortsViews[day] += youtubeAnalyticsInt(row[2])
+			} else {
+				longFormViews[day] += youtubeAnalyticsInt(row[2])
+			}
+		}
+	}
+
+	for day, m := range channelDays {
+		err := dbQueries.UpsertYouTubeChannelDailyStat(ctx, database.UpsertYouTubeChannelDailyStatParams{
+			ID:                      uuid.New(),
+			Date:                    day,
+			SourceID:                sourceId,
+			Views:                   m.Views,
+			EstimatedMinutesWatched: m.EstimatedMinutesWatched,
+			AverageViewDuration:     m.AverageViewDuration,
+			SubscribersGained:       m.SubscribersGained,
+			SubscribersLost:         m.SubscribersLost,
+			ShortsViews:             sql.NullInt64{Int64: shortsViews[day], Valid: splitOK},
+			LongFormViews:           sql.NullInt64{Int64: longFormViews[day], Valid: splitOK},
+		})
+		if err != nil {
+			log.Printf("YouTube Analytics: error saving channel stat for %s: %v", day.Format("2006-01-02"), err)
+		}
+	}
+
+	topResp, err := service.Reports.Query().
+		Ids("channel==MINE").
+		StartDate(startDate).
+		EndDate(endDate).
+		Metrics("views").
+		Dimensions("video").
+		Sort("-views").
+		MaxResults(200).
+		Do()
+	if err != nil {
+		return fmt.Errorf("failed to query video report: %w", err)
+	}
+
+	for _, row := range topResp.Rows {
+		if len(row) < 2 {
+			continue
+		}
+		videoId, _ := row[0].(string)
+		if videoId == "" || youtubeAnalyticsInt(row[1]) == 0 {
+			continue
+		}
+
+		post, err := dbQueries.GetPostBySourceAndNetworkId(ctx, database.GetPostBySourceAndNetworkIdParams{
+			NetworkInternalID: videoId,
+			SourceID:          sourceId,
+		})
+		if err != nil {
+			continue
+		}
+
+		videoResp, err := service.Reports.Query().
+			Ids("channel==MINE").
+			StartDate(startDate).
+			EndDate(endDate).
+			Metrics(youtubeAnalyticsMetrics).
+			Dimensions("day").
+			Filters("video==" + videoId).
+			Sort("day").
+			Do()
+		if err != nil {
+			log.Printf("YouTube Analytics: failed to query report for video %s: %v", videoId, err)
+			continue
+		}
+
+		for day, m := range parseYouTubeDailyRows(videoResp) {
+			err := dbQueries.UpsertYouTubeVideoDailyStat(ctx, database.UpsertYouTubeVideoDailyStatParams{
+				ID:                      uuid.New(),
+				Date:                    day,
+				PostID:                  post.ID,
+				Views:                   m.Views,
+				EstimatedMinutesWatched: m.EstimatedMinutesWatched,
+				AverageViewDuration:     m.AverageViewDuration,
+				SubscribersGained:       m.SubscribersGained,
+				SubscribersLost:         m.SubscribersLost,
+			})
+			if err != nil {
+				log.Printf("YouTube Analytics: error saving stat for video %s on %s: %v", videoId, day.Format("2006-01-02"), err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package fetcher
+
+import (
+	"context"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/fetcher/common"
+	"github.com/fluffyriot/rpsync/internal/fetcher/sources"
+)
+
+var StoryNetworks = []string{"Instagram"}
+
+func PollStoriesBySource(source database.Source, dbQueries *database.Queries, c *common.Client, ver string, encryptionKey []byte) error {
+	c, err := ClientForSource(context.Background(), dbQueries, c, encryptionKey, source.ID)
+	if err != nil {
+		return err
+	}
+
+	switch source.Network {
+	case "Instagram":
+		return sources.PollInstagramStories(dbQueries, c, source.ID, ver, encryptionKey)
+
+	default:
+		return nil
+	}
+}
@@ -4,6 +4,7 @@ package fetcher
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"time"
 
 	"github.com/fluffyriot/rpsync/internal/database"
@@ -31,9 +32,17 @@ func executeSync(
 
 	err = syncFunc()
 	if err != nil {
+		// A changed layout will not fix itself on retry, so report it
+		// straight away.
+		status := "Failed"
+		if errors.Is(err, common.ErrLayoutChanged) {
+			status = "Layout Changed"
+			isLastRetry = true
+		}
+
 		_, _ = dbQueries.UpdateSourceSyncStatusById(ctx, database.UpdateSourceSyncStatusByIdParams{
 			ID:           sourceID,
-			SyncStatus:   "Failed",
+			SyncStatus:   status,
 			StatusReason: sql.NullString{String: err.Error(), Valid: true},
 			LastSynced:   sql.NullTime{Time: time.Now(), Valid: true},
 		})
@@ -73,9 +82,14 @@ func SyncBySource(sid uuid.UUID, dbQueries *database.Queries, c *common.Client,
 	}
 
 	return executeSync(context.Background(), dbQueries, source.ID, func() error {
+		c, err := ClientForSource(context.Background(), dbQueries, c, encryptionKey, source.ID)
+		if err != nil {
+			return err
+		}
+
 		switch source.Network {
 		case "Bluesky":
-			return sources.FetchBlueskyPosts(dbQueries, c, source.UserID, source.ID)
+			return sources.FetchBlueskyPosts(dbQueries, c, encryptionKey, source.ID)
 
 		case "Instagram":
 			if err := sources.FetchInstagramTags(dbQueries, c, source.ID, ver, encryptionKey); err != nil {
@@ -93,7 +107,7 @@ func SyncBySource(sid uuid.UUID, dbQueries *database.Queries, c *common.Client,
 			return sources.FetchBadpupsPosts(source.UserID, dbQueries, c, source.ID)
 
 		case "TikTok":
-			return sources.FetchTikTokPosts(dbQueries, c, source.UserID, source.ID)
+			return sources.FetchTikTokPosts(dbQueries, encryptionKey, c, source.UserID, source.ID)
 
 		case "Mastodon":
 			return sources.FetchMastodonPosts(dbQueries, c, source.UserID, source.ID)
@@ -102,10 +116,10 @@ func SyncBySource(sid uuid.UUID, dbQueries *database.Queries, c *common.Client,
 			return sources.FetchTelegramPosts(dbQueries, encryptionKey, source.ID, c)
 
 		case "Google Analytics":
-			return sources.FetchGoogleAnalyticsStats(dbQueries, source.ID, encryptionKey)
+			return sources.FetchGoogleAnalyticsStats(dbQueries, source.ID, encryptionKey, c)
 
 		case "YouTube":
-			return sources.FetchYouTubePosts(dbQueries, source.ID, encryptionKey)
+			return sources.FetchYouTubePosts(dbQueries, source.ID, encryptionKey, c)
 
 		case "FurTrack":
 			return sources.FetchFurTrackPosts(dbQueries, c, source.UserID, source.ID)
@@ -132,7 +146,7 @@ func SyncBySource(sid uuid.UUID, dbQueries *database.Queries, c *common.Client,
 			return sources.FetchWeasylPosts(dbQueries, encryptionKey, source.ID, c)
 
 		case "Google Search Console":
-			return sources.FetchGoogleSearchConsoleStats(dbQueries, source.ID, encryptionKey)
+			return sources.FetchGoogleSearchConsoleStats(dbQueries, source.ID, encryptionKey, c)
 
 		case "Threads":
 			return sources.FetchThreadsPosts(dbQueries, encryptionKey, source.ID, c)
@@ -140,6 +154,12 @@ func SyncBySource(sid uuid.UUID, dbQueries *database.Queries, c *common.Client,
 		case "Twitter":
 			return sources.FetchTwitterPosts(dbQueries, c, source.UserName, source.ID, encryptionKey)
 
+		case "Patreon":
+			return sources.FetchPatreonPosts(dbQueries, encryptionKey, source.ID, c)
+
+		case "Ko-fi":
+			return sources.FetchKofiStats(dbQueries, source.ID)
+
 		default:
 			return nil
 		}
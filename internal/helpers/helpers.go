@@ -17,6 +17,19 @@ type SourceNetwork struct {
 type TargetNetwork struct {
 	Name  string
 	Color string
+	// Fields lists the setup form inputs the target uses.
+	Fields []TargetField
+}
+
+// TargetField describes one setup form input. Name is the form field name:
+// "db_id", "api_token" or "host_url". Multiline fields, e.g. pasted JSON
+// keys, get a textarea.
+type TargetField struct {
+	Name        string `json:"name"`
+	Label       string `json:"label"`
+	Placeholder string `json:"placeholder"`
+	Optional    bool   `json:"optional"`
+	Multiline   bool   `json:"multiline"`
 }
 
 var AvailableSources = []SourceNetwork{
@@ -40,6 +53,8 @@ var AvailableSources = []SourceNetwork{
 	{Name: "Weasyl", Color: "#990000", EngagementSupported: true, ViewsSupported: true, FollowersTracked: true},
 	{Name: "FurTrack", Color: "#2d0e4c", EngagementSupported: true, ViewsSupported: false, FollowersTracked: false},
 	{Name: "FurAffinity", Color: "#f9af3B", EngagementSupported: true, ViewsSupported: true, FollowersTracked: true},
+	{Name: "Patreon", Color: "#ff424d", EngagementSupported: true, ViewsSupported: false, FollowersTracked: true},
+	{Name: "Ko-fi", Color: "#72a4f2", EngagementSupported: false, ViewsSupported: false, FollowersTracked: true},
 }
 
 func GetSourceByName(name string) *SourceNetwork {
@@ -51,10 +66,8 @@ func GetSourceByName(name string) *SourceNetwork {
 	return nil
 }
 
-var AvailableTargets = []TargetNetwork{
-	{Name: "NocoDB", Color: "#4351e8"},
-	{Name: "CSV", Color: "#45b058"},
-}
+// AvailableTargets is filled by targets.Register.
+var AvailableTargets []TargetNetwork
 
 func ConvNetworkToURL(network, username string) (string, error) {
 	switch network {
@@ -99,6 +112,10 @@ func ConvNetworkToURL(network, username string) (string, error) {
 		return "https://www.weasyl.com/~" + username, nil
 	case "Google Search Console":
 		return "https://search.google.com/search-console/", nil
+	case "Patreon":
+		return "https://www.patreon.com/" + username, nil
+	case "Ko-fi":
+		return "https://ko-fi.com/" + username, nil
 	default:
 		return "", fmt.Errorf("network %v not recognized", network)
 	}
@@ -107,6 +124,9 @@ func ConvNetworkToURL(network, username string) (string, error) {
 func ConvPostToURL(network, author, networkId string) (string, error) {
 	switch network {
 	case "Instagram":
+		if strings.HasPrefix(networkId, "story-") {
+			return "https://www.instagram.com/stories/" + author + "/" + strings.TrimPrefix(networkId, "story-") + "/", nil
+		}
 		return "https://instagram.com/p/" + networkId, nil
 	case "Bluesky":
 		return "https://bsky.app/profile/" + author + "/post/" + networkId, nil
@@ -138,6 +158,9 @@ func ConvPostToURL(network, author, networkId string) (string, error) {
 	case "Reddit":
 		return "https://reddit.com/comments/" + networkId, nil
 	case "Twitch":
+		if strings.HasPrefix(networkId, "stream-") {
+			return "https://www.twitch.tv/" + author, nil
+		}
 		isNumeric := len(networkId) > 0
 		for _, ch := range networkId {
 			if ch < '0' || ch > '9' {
@@ -157,6 +180,8 @@ func ConvPostToURL(network, author, networkId string) (string, error) {
 		return "https://www.deviantart.com/" + author + "/art/" + networkId, nil
 	case "Weasyl":
 		return "https://www.weasyl.com/~" + author + "/submissions/" + networkId, nil
+	case "Patreon":
+		return "https://www.patreon.com/posts/" + networkId, nil
 	default:
 		return "", fmt.Errorf("network %v not recognized", network)
 	}
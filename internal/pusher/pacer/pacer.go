@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+// Package pacer spaces out the requests targets make to rate-limited APIs.
+package pacer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Pacer spaces out the requests made for one target.
+type Pacer struct {
+	mu       sync.Mutex
+	interval time.Duration
+	lastCall time.Time
+}
+
+// Wait blocks until the interval has passed since the previous call.
+func (p *Pacer) Wait() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if wait := p.interval - time.Since(p.lastCall); wait > 0 {
+		time.Sleep(wait)
+	}
+	p.lastCall = time.Now()
+}
+
+// Group holds the pacers of one target type. Every step of a sync builds its
+// own client, so the pacers are kept per target to hold the interval across
+// steps and across syncs running at the same time.
+type Group struct {
+	interval time.Duration
+
+	mu     sync.Mutex
+	pacers map[uuid.UUID]*Pacer
+}
+
+var (
+	groupsMu sync.Mutex
+	groups   []*Group
+)
+
+// NewGroup returns a group whose pacers space requests interval apart.
+func NewGroup(interval time.Duration) *Group {
+	g := &Group{interval: interval, pacers: map[uuid.UUID]*Pacer{}}
+
+	groupsMu.Lock()
+	groups = append(groups, g)
+	groupsMu.Unlock()
+
+	return g
+}
+
+// For returns the pacer of a target, creating it on first use.
+func (g *Group) For(targetID uuid.UUID) *Pacer {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	p, ok := g.pacers[targetID]
+	if !ok {
+		p = &Pacer{interval: g.interval}
+		g.pacers[targetID] = p
+	}
+	return p
+}
+
+// Forget drops a deleted target's pacers from every group.
+func Forget(targetID uuid.UUID) {
+	groupsMu.Lock()
+	defer groupsMu.Unlock()
+
+	for _, g := range groups {
+		g.mu.Lock()
+		delete(g.pacers, targetID)
+		g.mu.Unlock()
+	}
+}
@@ -4,6 +4,8 @@ package pusher
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"log"
 	"time"
 
@@ -11,7 +13,14 @@ import (
 	"github.com/fluffyriot/rpsync/internal/exports"
 	"github.com/fluffyriot/rpsync/internal/pusher/common"
 	"github.com/fluffyriot/rpsync/internal/pusher/targets"
-	"github.com/fluffyriot/rpsync/internal/pusher/targets/noco"
+	_ "github.com/fluffyriot/rpsync/internal/pusher/targets/baserow"
+	_ "github.com/fluffyriot/rpsync/internal/pusher/targets/grist"
+	_ "github.com/fluffyriot/rpsync/internal/pusher/targets/noco"
+	_ "github.com/fluffyriot/rpsync/internal/pusher/targets/notion"
+	_ "github.com/fluffyriot/rpsync/internal/pusher/targets/sheets"
+	_ "github.com/fluffyriot/rpsync/internal/pusher/targets/timeseries"
+	_ "github.com/fluffyriot/rpsync/internal/pusher/targets/warehouse"
+	_ "github.com/fluffyriot/rpsync/internal/pusher/targets/webhook"
 	"github.com/google/uuid"
 )
 
@@ -27,7 +36,7 @@ func RemoveByTarget(tid, sid uuid.UUID, dbQueries *database.Queries, c *common.C
 		return err
 	}
 
-	err = startDbRemoval(dbQueries, c, target.ID, encryptionKey, target, source)
+	err = startDbRemoval(dbQueries, c, encryptionKey, target, source)
 	if err != nil {
 		return err
 	}
@@ -50,82 +59,7 @@ func PullByTarget(tid uuid.UUID, dbQueries *database.Queries, c *common.Client,
 		return err
 	}
 
-	var finalErr error
-
-	switch target.TargetType {
-
-	case "NocoDB":
-
-		export, err := exports.CreateLogAutoExport(target.UserID, dbQueries, target.TargetType, target.ID)
-		if err != nil {
-			log.Println("Error creating export log:", err)
-		}
-
-		err = startDbSync(dbQueries, c, encryptionKey, target)
-		if err != nil {
-			exports.UpdateLogAutoExport(export, dbQueries, "Failed", err.Error(), "")
-			finalErr = err
-		} else {
-			exports.UpdateLogAutoExport(export, dbQueries, "Completed", "", "")
-		}
-
-	case "CSV":
-
-		hasPosts, err := targets.HasPosts(dbQueries, target.UserID)
-		if err != nil {
-			finalErr = err
-		} else if hasPosts {
-			exportPosts, err := exports.CreateLogAutoExport(target.UserID, dbQueries, "CSV - Posts", target.ID)
-			if err != nil {
-				log.Println("Error creating posts export log:", err)
-			} else {
-				filename, err := targets.GeneratePostsCsv(dbQueries, target, exportPosts)
-				if err != nil {
-					exports.UpdateLogAutoExport(exportPosts, dbQueries, "Failed", err.Error(), filename)
-					finalErr = err
-				} else {
-					exports.UpdateLogAutoExport(exportPosts, dbQueries, "Completed", "", filename)
-				}
-			}
-		}
-
-		hasAnalytics, err := targets.HasAnalytics(dbQueries, target.UserID)
-		if err != nil {
-			if finalErr == nil {
-				finalErr = err
-			}
-		} else if hasAnalytics {
-			exportWeb, err := exports.CreateLogAutoExport(target.UserID, dbQueries, "CSV - Website", target.ID)
-			if err != nil {
-				log.Println("Error creating website export log:", err)
-			} else {
-				filename, err := targets.GenerateWebsiteCsv(dbQueries, target, exportWeb)
-				if err != nil {
-					exports.UpdateLogAutoExport(exportWeb, dbQueries, "Failed", err.Error(), filename)
-					if finalErr == nil {
-						finalErr = err
-					}
-				} else {
-					exports.UpdateLogAutoExport(exportWeb, dbQueries, "Completed", "", filename)
-				}
-			}
-
-			exportPages, err := exports.CreateLogAutoExport(target.UserID, dbQueries, "CSV - Pages", target.ID)
-			if err != nil {
-				log.Println("Error creating website pages export log:", err)
-			} else {
-				filename, err := targets.GeneratePageViewsCsv(dbQueries, target, exportPages)
-				if err != nil {
-					exports.UpdateLogAutoExport(exportPages, dbQueries, "Failed", err.Error(), filename)
-					if finalErr == nil {
-						finalErr = err
-					}
-				} else {
-					exports.UpdateLogAutoExport(exportPages, dbQueries, "Completed", "", filename)
-				}
-			}
-		}
-	}
+	finalErr := push(dbQueries, c, encryptionKey, target)
 
 	status := "Synced"
 	var reason sql.NullString
@@ -155,29 +89,94 @@ func PullByTarget(tid uuid.UUID, dbQueries *database.Queries, c *common.Client,
 	return finalErr
 }
 
-func startDbSync(dbQueries *database.Queries, c *common.Client, encryptionKey []byte, target database.Target) error {
+// push runs one sync of a target through its registered provider.
+func push(dbQueries *database.Queries, c *common.Client, encryptionKey []byte, target database.Target) error {
+	if target.TargetType == targets.None {
+		return nil
+	}
 
-	_, err := dbQueries.GetTableMappingsByTargetAndName(context.Background(), database.GetTableMappingsByTargetAndNameParams{
-		TargetID:        target.ID,
-		TargetTableName: "Analytics_Page_Stats",
-	})
+	provider, err := targets.Get(target.TargetType)
 	if err != nil {
-		err := noco.InitializeNoco(dbQueries, c, encryptionKey, target)
+		return err
+	}
+
+	r := targets.Run{
+		DB:            dbQueries,
+		Client:        c,
+		EncryptionKey: encryptionKey,
+		Target:        target,
+	}
+
+	var export database.Export
+	if !provider.LogsExports {
+		export, err = exports.CreateLogAutoExport(target.UserID, dbQueries, target.TargetType, target.ID)
 		if err != nil {
-			return err
+			log.Println("Error creating export log:", err)
+		}
+	}
+
+	err = runTarget(provider, r)
+
+	if !provider.LogsExports {
+		if err != nil {
+			exports.UpdateLogAutoExport(export, dbQueries, "Failed", err.Error(), "")
+		} else {
+			exports.UpdateLogAutoExport(export, dbQueries, "Completed", "", "")
 		}
 	}
 
-	err = noco.SyncNoco(dbQueries, c, encryptionKey, target)
 	return err
+}
 
+func runTarget(p targets.Provider, r targets.Run) error {
+	t := p.Target
+	if err := t.Initialize(r); err != nil {
+		return err
+	}
+
+	steps := []func() error{
+		func() error {
+			if err := t.PushSources(r); err != nil {
+				return fmt.Errorf("failed to sync sources: %w", err)
+			}
+			return nil
+		},
+		func() error { return t.PushAnalytics(r) },
+		func() error {
+			if err := t.PushStats(r); err != nil {
+				return fmt.Errorf("failed to sync sources stats: %w", err)
+			}
+			return nil
+		},
+		func() error { return t.PushPosts(r) },
+	}
+
+	var errs []error
+	for _, step := range steps {
+		if err := step(); err != nil {
+			if !p.IndependentSteps {
+				return err
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
 }
 
-func startDbRemoval(dbQueries *database.Queries, c *common.Client, targetId uuid.UUID, encryptionKey []byte, target database.Target, source database.Source) error {
-	if target.TargetType == "CSV" {
+func startDbRemoval(dbQueries *database.Queries, c *common.Client, encryptionKey []byte, target database.Target, source database.Source) error {
+	if target.TargetType == targets.None {
 		return nil
 	}
 
-	err := noco.DeletePostsAndSourceNoco(dbQueries, c, encryptionKey, target, source)
-	return err
+	provider, err := targets.Get(target.TargetType)
+	if err != nil {
+		return err
+	}
+
+	return provider.Target.RemoveSource(targets.Run{
+		DB:            dbQueries,
+		Client:        c,
+		EncryptionKey: encryptionKey,
+		Target:        target,
+	}, source)
 }
@@ -0,0 +1,370 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package baserow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/authhelp"
+	"github.com/fluffyriot/rpsync/internal/pusher/targets"
+	"github.com/fluffyriot/rpsync/internal/pusher/targets/records"
+)
+
+const (
+	defaultHost = "https://api.baserow.io"
+	maxRetries  = 5
+	// batchSize is the most rows Baserow's batch endpoints accept.
+	batchSize = 200
+	// decimalPlaces is what Decimal columns are created with; Baserow
+	// rejects numbers with more places than the field has.
+	decimalPlaces = 2
+)
+
+var (
+	errNotFound = errors.New("not found in Baserow")
+	errExpired  = errors.New("baserow session expired")
+)
+
+type client struct {
+	r        targets.Run
+	host     string
+	email    string
+	password string
+	jwt      string
+}
+
+// newClient signs in with the account stored as the target token. Database
+// tokens only grant access to rows, and the target also creates tables and
+// fields, which needs a user session.
+func newClient(r targets.Run) (records.Store, error) {
+	token, _, _, err := authhelp.GetTargetToken(context.Background(), r.DB, r.EncryptionKey, r.Target.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Baserow credentials: %w", err)
+	}
+
+	email, password, ok := strings.Cut(token, ":")
+	if !ok {
+		return nil, errors.New("baserow credentials must be given as email:password")
+	}
+
+	host := strings.TrimSuffix(r.Target.HostUrl.String, "/")
+	if host == "" {
+		host = defaultHost
+	}
+
+	b := &client{r: r, host: host, email: strings.TrimSpace(email), password: password}
+	if err := b.signIn(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (b *client) signIn() error {
+	var resp struct {
+		AccessToken string `json:"access_token"`
+		Token       string `json:"token"`
+	}
+	body := map[string]string{"email": b.email, "password": b.password}
+	if err := b.send("POST", "/api/user/token-auth/", body, &resp, false); err != nil {
+		return fmt.Errorf("failed to sign in to Baserow: %w", err)
+	}
+	b.jwt = resp.AccessToken
+	if b.jwt == "" {
+		b.jwt = resp.Token
+	}
+	return nil
+}
+
+func (b *client) BatchSize() int {
+	return batchSize
+}
+
+type field struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"`
+	Primary bool   `json:"primary"`
+}
+
+func (b *client) CreateTable(t records.Table, linkTable string) (string, map[string]string, error) {
+	var table struct {
+		ID int64 `json:"id"`
+	}
+	// The first column becomes the primary field, so the table starts with
+	// ct_id instead of Baserow's default Name, Notes and Active fields.
+	body := map[string]any{
+		"name":             t.Name,
+		"data":             [][]string{{t.Columns[0].Name}},
+		"first_row_header": true,
+	}
+	if err := b.do("POST", "/api/database/tables/database/"+b.r.Target.DbID.String+"/", body, &table); err != nil {
+		return "", nil, err
+	}
+	tableID := strconv.FormatInt(table.ID, 10)
+
+	var fields []field
+	if err := b.do("GET", "/api/database/fields/table/"+tableID+"/", nil, &fields); err != nil {
+		return "", nil, err
+	}
+
+	columns := make(map[string]string, len(t.Columns))
+	for _, f := range fields {
+		if f.Primary {
+			columns[t.Columns[0].Name] = strconv.FormatInt(f.ID, 10)
+		}
+	}
+
+	for _, c := range t.Columns[1:] {
+		id, err := b.CreateColumn(tableID, c, linkTable)
+		if err != nil {
+			return "", nil, err
+		}
+		columns[c.Name] = id
+	}
+
+	return tableID, columns, nil
+}
+
+func (b *client) CreateColumn(tableID string, c records.Column, linkTable string) (string, error) {
+	body := map[string]any{"name": c.Name}
+	switch c.Kind {
+	case records.Text:
+		body["type"] = "text"
+	case records.LongText:
+		body["type"] = "long_text"
+	case records.Number:
+		body["type"] = "number"
+		body["number_decimal_places"] = 0
+		body["number_negative"] = true
+	case records.Decimal:
+		body["type"] = "number"
+		body["number_decimal_places"] = decimalPlaces
+		body["number_negative"] = true
+	case records.Date:
+		body["type"] = "date"
+		body["date_format"] = "ISO"
+		body["date_include_time"] = false
+	case records.DateTime:
+		body["type"] = "date"
+		body["date_format"] = "ISO"
+		body["date_include_time"] = true
+		body["date_time_format"] = "24"
+	case records.Checkbox:
+		body["type"] = "boolean"
+	case records.URL:
+		body["type"] = "url"
+	case records.Select:
+		options := make([]map[string]string, len(c.Choices))
+		for i, choice := range c.Choices {
+			options[i] = map[string]string{"value": choice, "color": "blue"}
+		}
+		body["type"] = "single_select"
+		body["select_options"] = options
+	case records.Link:
+		linkID, err := strconv.ParseInt(linkTable, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid sources table id %q", linkTable)
+		}
+		body["type"] = "link_row"
+		body["link_row_table_id"] = linkID
+		body["has_related_field"] = true
+	}
+
+	var f field
+	if err := b.do("POST", "/api/database/fields/table/"+tableID+"/", body, &f); err != nil {
+		return "", fmt.Errorf("create field %s: %w", c.Name, err)
+	}
+	return strconv.FormatInt(f.ID, 10), nil
+}
+
+func (b *client) CreateRecords(t records.Table, tableID string, rows []records.Row) ([]int64, error) {
+	items := make([]map[string]any, len(rows))
+	for i, row := range rows {
+		items[i] = encode(t, row)
+	}
+
+	var resp struct {
+		Items []struct {
+			ID int64 `json:"id"`
+		} `json:"items"`
+	}
+	if err := b.do("POST", "/api/database/rows/table/"+tableID+"/batch/?user_field_names=true", map[string]any{"items": items}, &resp); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(resp.Items))
+	for i, item := range resp.Items {
+		ids[i] = item.ID
+	}
+	return ids, nil
+}
+
+// UpdateRecords updates the batch in one request. Baserow rejects the whole
+// batch when one row is gone, so it then falls back to one request per row
+// to find the missing ones.
+func (b *client) UpdateRecords(t records.Table, tableID string, recs []records.Record) ([]int64, error) {
+	items := make([]map[string]any, len(recs))
+	for i, rec := range recs {
+		items[i] = encode(t, rec.Row)
+		items[i]["id"] = rec.ID
+	}
+
+	err := b.do("PATCH", "/api/database/rows/table/"+tableID+"/batch/?user_field_names=true", map[string]any{"items": items}, nil)
+	if !errors.Is(err, errNotFound) {
+		return nil, err
+	}
+
+	var missing []int64
+	for i, rec := range recs {
+		delete(items[i], "id")
+		path := fmt.Sprintf("/api/database/rows/table/%s/%d/?user_field_names=true", tableID, rec.ID)
+		err := b.do("PATCH", path, items[i], nil)
+		if errors.Is(err, errNotFound) {
+			missing = append(missing, rec.ID)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return missing, nil
+}
+
+func (b *client) DeleteRecords(tableID string, ids []int64) error {
+	err := b.do("POST", "/api/database/rows/table/"+tableID+"/batch-delete/", map[string]any{"items": ids}, nil)
+	if !errors.Is(err, errNotFound) {
+		return err
+	}
+
+	for _, id := range ids {
+		err := b.do("DELETE", fmt.Sprintf("/api/database/rows/table/%s/%d/", tableID, id), nil, nil)
+		if err != nil && !errors.Is(err, errNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// encode converts a row to the values Baserow expects for each field type.
+func encode(t records.Table, row records.Row) map[string]any {
+	fields := make(map[string]any, len(row))
+	for name, v := range row {
+		c, _ := t.Column(name)
+		switch val := v.(type) {
+		case nil:
+			if c.Kind == records.Link {
+				fields[name] = []int64{}
+			} else {
+				fields[name] = nil
+			}
+		case time.Time:
+			if c.Kind == records.Date {
+				fields[name] = val.Format("2006-01-02")
+			} else {
+				fields[name] = val.UTC().Format(time.RFC3339)
+			}
+		case float64:
+			fields[name] = strconv.FormatFloat(val, 'f', decimalPlaces, 64)
+		case int64:
+			if c.Kind == records.Link {
+				fields[name] = []int64{val}
+			} else {
+				fields[name] = val
+			}
+		case string:
+			if val == "" && c.Kind == records.Select {
+				fields[name] = nil
+			} else {
+				fields[name] = val
+			}
+		default:
+			fields[name] = val
+		}
+	}
+	return fields
+}
+
+func (b *client) do(method, path string, body, out any) error {
+	err := b.send(method, path, body, out, true)
+	if errors.Is(err, errExpired) {
+		// Access tokens last ten minutes; long syncs sign in again.
+		if err := b.signIn(); err != nil {
+			return err
+		}
+		return b.send(method, path, body, out, true)
+	}
+	return err
+}
+
+func (b *client) send(method, path string, body, out any, auth bool) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("marshal request: %w", err)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest(method, b.host+path, bytes.NewReader(payload))
+		if err != nil {
+			return fmt.Errorf("create request: %w", err)
+		}
+		if auth {
+			req.Header.Set("Authorization", "JWT "+b.jwt)
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := b.r.Client.HTTPClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("send request: %w", err)
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("read response body: %w", err)
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests && attempt < maxRetries:
+			delay := time.Duration(1<<attempt) * time.Second
+			if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+				delay = time.Duration(secs) * time.Second
+			}
+			log.Printf("Baserow rate limit hit, retrying in %s", delay)
+			time.Sleep(delay)
+			continue
+		case resp.StatusCode == http.StatusUnauthorized && auth:
+			return errExpired
+		case resp.StatusCode == http.StatusNotFound:
+			return fmt.Errorf("%s %s: %w", method, path, errNotFound)
+		case resp.StatusCode < 200 || resp.StatusCode > 299:
+			var apiErr struct {
+				Error  string `json:"error"`
+				Detail any    `json:"detail"`
+			}
+			if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Error != "" {
+				return fmt.Errorf("baserow %s: %v", apiErr.Error, apiErr.Detail)
+			}
+			return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(respBody))
+		}
+
+		if out == nil || len(respBody) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("decode response: %w", err)
+		}
+		return nil
+	}
+}
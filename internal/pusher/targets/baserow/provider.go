@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package baserow
+
+import (
+	"github.com/fluffyriot/rpsync/internal/helpers"
+	"github.com/fluffyriot/rpsync/internal/pusher/targets"
+	"github.com/fluffyriot/rpsync/internal/pusher/targets/records"
+)
+
+func init() {
+	targets.Register(targets.Provider{
+		Network: helpers.TargetNetwork{
+			Name:  "Baserow",
+			Color: "#5190ef",
+			Fields: []helpers.TargetField{
+				{Name: "db_id", Label: "Database Id", Placeholder: "123"},
+				{Name: "api_token", Label: "Account Email and Password", Placeholder: "you@example.com:password"},
+				{Name: "host_url", Label: "Host Url", Placeholder: "https://api.baserow.io", Optional: true},
+			},
+		},
+		// Baserow keeps the same tables as NocoDB; posts and stats link to
+		// their row in sources.
+		Target: records.Target{Open: newClient},
+	})
+}
@@ -4,225 +4,122 @@ package targets
 import (
 	"context"
 	"encoding/csv"
-	"fmt"
-	"os"
-	"strconv"
-	"time"
+	"io"
 
 	"github.com/fluffyriot/rpsync/internal/database"
 	"github.com/fluffyriot/rpsync/internal/helpers"
 	"github.com/google/uuid"
 )
 
-func HasPosts(dbQueries *database.Queries, userID uuid.UUID) (bool, error) {
-
-	count, err := dbQueries.CheckCountOfPostsForUser(context.Background(), userID)
-	if err != nil {
-		return false, err
-	}
-	return count > 0, nil
+func init() {
+	Register(Provider{
+		Network: helpers.TargetNetwork{
+			Name:  "CSV",
+			Color: "#45b058",
+			Fields: []helpers.TargetField{
+				{Name: "db_id", Label: "Export Name", Placeholder: "Export Name"},
+			},
+		},
+		Target:           CSV{},
+		LogsExports:      true,
+		IndependentSteps: true,
+	})
 }
 
-func HasAnalytics(dbQueries *database.Queries, userID uuid.UUID) (bool, error) {
-	count, err := dbQueries.CheckCountOfAnalyticsSiteStatsForUser(context.Background(), userID)
-	if err != nil {
-		return false, err
-	}
-	return count > 0, nil
-}
+// CSV writes posts, reaction history, tags, follower stats and website
+// analytics to downloadable files, one export log per file. Besides CSV it
+// can write NDJSON, Parquet and an XLSX workbook with a sheet per dataset.
+// The workbook, and with ZipArchive set the archive of all files of a sync,
+// are written with the posts.
+type CSV struct{}
+
+func (CSV) Initialize(r Run) error  { return nil }
+func (CSV) PushSources(r Run) error { return nil }
+
+func (CSV) RemoveSource(r Run, source database.Source) error { return nil }
 
-func GeneratePostsCsv(dbQueries *database.Queries, target database.Target, export database.Export) (string, error) {
-	posts, err := dbQueries.GetAllPostsWithTheLatestInfoForUser(context.Background(), target.UserID)
+func (CSV) PushPosts(r Run) error {
+	settings, err := GetFileSettings(r.DB, r.Target.ID)
 	if err != nil {
-		return "", fmt.Errorf("fetching posts: %w", err)
+		return err
 	}
-
-	if len(posts) == 0 {
-		return "", nil
+	if settings.ZipArchive {
+		return writeArchiveExport(r, settings)
 	}
 
-	filename := fmt.Sprintf("outputs/export_id_%s_posts_%s.csv", export.ID.String(), time.Now().Format("20060102_150405"))
-	file, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	hasPosts, err := HasPosts(r.DB, r.Target.UserID)
 	if err != nil {
-		return "", err
+		return err
 	}
-	defer file.Close()
-
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
-
-	if err := writer.Write([]string{
-		"ct_id",
-		"posted_at",
-		"last_updated",
-		"is_archived",
-		"network",
-		"post_type",
-		"post_author",
-		"likes",
-		"reposts",
-		"views",
-		"url",
-		"content",
-	}); err != nil {
-		return "", err
+	if hasPosts {
+		err = writeFileExports(r, settings, "posts", "reactions", "tags", "post_tags")
 	}
-
-	for _, r := range posts {
-		content := ""
-		if r.Content.Valid {
-			content = r.Content.String
-		}
-		network := ""
-		if r.Network.Valid {
-			network = r.Network.String
-		}
-		reactionsSyncedAt := ""
-		if r.ReactionsSyncedAt.Valid {
-			reactionsSyncedAt = r.ReactionsSyncedAt.Time.Format(time.RFC3339)
-		}
-		likes := ""
-		if r.Likes.Valid {
-			likes = strconv.FormatInt(r.Likes.Int64, 10)
-		}
-		reposts := ""
-		if r.Reposts.Valid {
-			reposts = strconv.FormatInt(r.Reposts.Int64, 10)
-		}
-		views := ""
-		if r.Views.Valid {
-			views = strconv.FormatInt(r.Views.Int64, 10)
-		}
-
-		url, _ := helpers.ConvPostToURL(network, r.Author, r.NetworkInternalID)
-
-		if err := writer.Write([]string{
-			r.ID.String(),
-			r.CreatedAt.Format(time.RFC3339),
-			reactionsSyncedAt,
-			strconv.FormatBool(r.IsArchived),
-			network,
-			r.PostType,
-			r.Author,
-			likes,
-			reposts,
-			views,
-			url,
-			content,
-		}); err != nil {
-			return "", err
+	if settings.wantsWorkbook() {
+		if errWorkbook := writeWorkbookExport(r, settings); err == nil {
+			err = errWorkbook
 		}
 	}
+	return err
+}
 
-	return filename, nil
+func (CSV) PushStats(r Run) error {
+	settings, err := GetFileSettings(r.DB, r.Target.ID)
+	if err != nil || settings.ZipArchive {
+		return err
+	}
+	return writeFileExports(r, settings, "followers")
 }
 
-func GenerateWebsiteCsv(dbQueries *database.Queries, target database.Target, export database.Export) (string, error) {
-	stats, err := dbQueries.GetAllAnalyticsSiteStatsForUser(context.Background(), target.UserID)
-	if err != nil {
-		return "", fmt.Errorf("fetching site stats: %w", err)
+func (CSV) PushAnalytics(r Run) error {
+	settings, err := GetFileSettings(r.DB, r.Target.ID)
+	if err != nil || settings.ZipArchive {
+		return err
 	}
 
-	if len(stats) == 0 {
-		return "", nil
+	hasAnalytics, err := HasAnalytics(r.DB, r.Target.UserID)
+	if err != nil || !hasAnalytics {
+		return err
 	}
+	return writeFileExports(r, settings, "website", "webpages")
+}
 
-	filename := fmt.Sprintf("outputs/export_id_%s_website_%s.csv", export.ID.String(), time.Now().Format("20060102_150405"))
-	file, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
-	if err != nil {
-		return "", err
+func writeCsv(w io.Writer, d dataset, cols []int, rows [][]any) error {
+	writer := csv.NewWriter(w)
+
+	record := make([]string, len(cols))
+	for i, c := range cols {
+		record[i] = d.columns[c].name
 	}
-	defer file.Close()
-
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
-
-	if err := writer.Write([]string{
-		"ct_id",
-		"date",
-		"analytics_type",
-		"visitors",
-		"avg_session_duration",
-		"impressions",
-		"source_network",
-		"source_username",
-	}); err != nil {
-		return "", err
+	if err := writer.Write(record); err != nil {
+		return err
 	}
 
-	for _, s := range stats {
-		impressions := ""
-		if s.Impressions.Valid {
-			impressions = strconv.FormatInt(s.Impressions.Int64, 10)
+	for _, row := range rows {
+		for i, c := range cols {
+			record[i] = formatValue(d.columns[c].kind, row[c])
 		}
-		if err := writer.Write([]string{
-			s.ID.String(),
-			s.Date.Format("2006-01-02"),
-			s.AnalyticsType,
-			strconv.FormatInt(s.Visitors, 10),
-			fmt.Sprintf("%f", s.AvgSessionDuration),
-			impressions,
-			s.SourceNetwork,
-			s.SourceUserName,
-		}); err != nil {
-			return "", err
+		if err := writer.Write(record); err != nil {
+			return err
 		}
 	}
 
-	return filename, nil
+	writer.Flush()
+	return writer.Error()
 }
 
-func GeneratePageViewsCsv(dbQueries *database.Queries, target database.Target, export database.Export) (string, error) {
-	stats, err := dbQueries.GetAllAnalyticsPageStatsForUser(context.Background(), target.UserID)
-	if err != nil {
-		return "", fmt.Errorf("fetching pages stats: %w", err)
-	}
-
-	if len(stats) == 0 {
-		return "", nil
-	}
+func HasPosts(dbQueries *database.Queries, userID uuid.UUID) (bool, error) {
 
-	filename := fmt.Sprintf("outputs/export_id_%s_webpages_%s.csv", export.ID.String(), time.Now().Format("20060102_150405"))
-	file, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	count, err := dbQueries.CheckCountOfPostsForUser(context.Background(), userID)
 	if err != nil {
-		return "", err
-	}
-	defer file.Close()
-
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
-
-	if err := writer.Write([]string{
-		"ct_id",
-		"date",
-		"analytics_type",
-		"url_path",
-		"views",
-		"impressions",
-		"source_network",
-		"source_username",
-	}); err != nil {
-		return "", err
+		return false, err
 	}
+	return count > 0, nil
+}
 
-	for _, s := range stats {
-		impressions := ""
-		if s.Impressions.Valid {
-			impressions = strconv.FormatInt(s.Impressions.Int64, 10)
-		}
-		if err := writer.Write([]string{
-			s.ID.String(),
-			s.Date.Format("2006-01-02"),
-			s.AnalyticsType,
-			s.UrlPath,
-			strconv.FormatInt(s.Views, 10),
-			impressions,
-			s.SourceNetwork,
-			s.SourceUserName,
-		}); err != nil {
-			return "", err
-		}
+func HasAnalytics(dbQueries *database.Queries, userID uuid.UUID) (bool, error) {
+	count, err := dbQueries.CheckCountOfAnalyticsSiteStatsForUser(context.Background(), userID)
+	if err != nil {
+		return false, err
 	}
-
-	return filename, nil
+	return count > 0, nil
 }
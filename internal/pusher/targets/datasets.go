@@ -0,0 +1,472 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package targets
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/helpers"
+	"github.com/google/uuid"
+)
+
+type columnKind int
+
+const (
+	kindText columnKind = iota
+	kindInt
+	kindFloat
+	kindBool
+	kindTime
+	kindDate
+)
+
+type column struct {
+	name string
+	kind columnKind
+}
+
+// dataset is a table the file targets export. Each row holds one value per
+// column: a string, int64, float64, bool or time.Time, or nil when empty.
+type dataset struct {
+	name    string
+	label   string
+	columns []column
+	rows    func(db *database.Queries, userID uuid.UUID) ([][]any, error)
+}
+
+var datasets = []dataset{
+	{
+		name:  "posts",
+		label: "Posts",
+		columns: []column{
+			{"ct_id", kindText},
+			{"posted_at", kindTime},
+			{"last_updated", kindTime},
+			{"is_archived", kindBool},
+			{"network", kindText},
+			{"post_type", kindText},
+			{"post_author", kindText},
+			{"likes", kindInt},
+			{"reposts", kindInt},
+			{"views", kindInt},
+			{"url", kindText},
+			{"content", kindText},
+		},
+		rows: postsRows,
+	},
+	{
+		name:  "reactions",
+		label: "Reactions",
+		columns: []column{
+			{"ct_id", kindText},
+			{"synced_at", kindTime},
+			{"post_id", kindText},
+			{"likes", kindInt},
+			{"reposts", kindInt},
+			{"views", kindInt},
+			{"comments", kindInt},
+		},
+		rows: reactionsRows,
+	},
+	{
+		name:  "tags",
+		label: "Tags",
+		columns: []column{
+			{"ct_id", kindText},
+			{"name", kindText},
+			{"classification", kindText},
+			{"created_at", kindTime},
+		},
+		rows: tagsRows,
+	},
+	{
+		name:  "post_tags",
+		label: "Post Tags",
+		columns: []column{
+			{"post_id", kindText},
+			{"tag_id", kindText},
+			{"tag_name", kindText},
+			{"classification", kindText},
+		},
+		rows: postTagsRows,
+	},
+	{
+		name:  "followers",
+		label: "Followers",
+		columns: []column{
+			{"ct_id", kindText},
+			{"date", kindDate},
+			{"source_network", kindText},
+			{"source_username", kindText},
+			{"followers_count", kindInt},
+			{"following_count", kindInt},
+			{"posts_count", kindInt},
+			{"followers_delta", kindInt},
+			{"following_delta", kindInt},
+			{"karma", kindInt},
+			{"average_likes", kindFloat},
+			{"average_reposts", kindFloat},
+			{"average_views", kindFloat},
+		},
+		rows: followersRows,
+	},
+	{
+		name:  "website",
+		label: "Website",
+		columns: []column{
+			{"ct_id", kindText},
+			{"date", kindDate},
+			{"analytics_type", kindText},
+			{"visitors", kindInt},
+			{"avg_session_duration", kindFloat},
+			{"impressions", kindInt},
+			{"source_network", kindText},
+			{"source_username", kindText},
+		},
+		rows: websiteRows,
+	},
+	{
+		name:  "webpages",
+		label: "Pages",
+		columns: []column{
+			{"ct_id", kindText},
+			{"date", kindDate},
+			{"analytics_type", kindText},
+			{"url_path", kindText},
+			{"views", kindInt},
+			{"impressions", kindInt},
+			{"source_network", kindText},
+			{"source_username", kindText},
+		},
+		rows: webpagesRows,
+	},
+}
+
+func getDataset(name string) (dataset, bool) {
+	for _, d := range datasets {
+		if d.name == name {
+			return d, true
+		}
+	}
+	return dataset{}, false
+}
+
+// DatasetInfo describes a dataset for the file target settings.
+type DatasetInfo struct {
+	Name    string   `json:"name"`
+	Label   string   `json:"label"`
+	Columns []string `json:"columns"`
+}
+
+func Datasets() []DatasetInfo {
+	infos := make([]DatasetInfo, 0, len(datasets))
+	for _, d := range datasets {
+		info := DatasetInfo{Name: d.name, Label: d.label}
+		for _, c := range d.columns {
+			info.Columns = append(info.Columns, c.name)
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// selectColumns returns the columns of a dataset picked in the settings, in
+// dataset order. Datasets missing from the settings export every column; an
+// empty list turns the file off.
+func selectColumns(d dataset, columns map[string][]string) []int {
+	picked, ok := columns[d.name]
+	if !ok {
+		picked = nil
+		for _, c := range d.columns {
+			picked = append(picked, c.name)
+		}
+	}
+	want := make(map[string]bool, len(picked))
+	for _, name := range picked {
+		want[name] = true
+	}
+	var idx []int
+	for i, c := range d.columns {
+		if want[c.name] {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// FileSettings are the files and columns a file target exports.
+type FileSettings struct {
+	// Columns maps dataset names to the columns to export.
+	Columns map[string][]string `json:"columns"`
+	// ZipArchive bundles all files of a sync into one archive.
+	ZipArchive bool `json:"zip_archive"`
+	// Formats are the file formats to write, see fileFormats.
+	Formats []string `json:"formats"`
+}
+
+func (s FileSettings) Validate() error {
+	if len(s.Formats) == 0 {
+		return errors.New("pick at least one file format")
+	}
+	for _, f := range s.Formats {
+		if !slices.Contains(FileFormats(), f) {
+			return fmt.Errorf("unknown file format %q", f)
+		}
+	}
+	for name, cols := range s.Columns {
+		d, ok := getDataset(name)
+		if !ok {
+			return fmt.Errorf("unknown dataset %q", name)
+		}
+		for _, col := range cols {
+			found := false
+			for _, c := range d.columns {
+				if c.name == col {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("unknown column %q in %s", col, d.label)
+			}
+		}
+	}
+	return nil
+}
+
+// GetFileSettings loads the settings of a file target. Targets that were
+// never configured export every file as CSV with all columns.
+func GetFileSettings(db *database.Queries, targetID uuid.UUID) (FileSettings, error) {
+	s := FileSettings{Formats: []string{"csv"}}
+	row, err := db.GetFileTargetSettings(context.Background(), targetID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("failed to get file settings: %w", err)
+	}
+	if err := json.Unmarshal(row.Columns, &s.Columns); err != nil {
+		return s, fmt.Errorf("failed to read file columns: %w", err)
+	}
+	if err := json.Unmarshal(row.Formats, &s.Formats); err != nil {
+		return s, fmt.Errorf("failed to read file formats: %w", err)
+	}
+	s.ZipArchive = row.ZipArchive
+	return s, nil
+}
+
+func nullInt(v sql.NullInt64) any {
+	if !v.Valid {
+		return nil
+	}
+	return v.Int64
+}
+
+func nullFloat(v sql.NullFloat64) any {
+	if !v.Valid {
+		return nil
+	}
+	return v.Float64
+}
+
+func nullString(v sql.NullString) any {
+	if !v.Valid {
+		return nil
+	}
+	return v.String
+}
+
+func nullTime(v sql.NullTime) any {
+	if !v.Valid {
+		return nil
+	}
+	return v.Time
+}
+
+func postsRows(db *database.Queries, userID uuid.UUID) ([][]any, error) {
+	posts, err := db.GetAllPostsWithTheLatestInfoForUser(context.Background(), userID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching posts: %w", err)
+	}
+	rows := make([][]any, 0, len(posts))
+	for _, p := range posts {
+		url, _ := helpers.ConvPostToURL(p.Network.String, p.Author, p.NetworkInternalID)
+		rows = append(rows, []any{
+			p.ID.String(),
+			p.CreatedAt,
+			nullTime(p.ReactionsSyncedAt),
+			p.IsArchived,
+			p.Network.String,
+			p.PostType,
+			p.Author,
+			nullInt(p.Likes),
+			nullInt(p.Reposts),
+			nullInt(p.Views),
+			url,
+			p.Content.String,
+		})
+	}
+	return rows, nil
+}
+
+func reactionsRows(db *database.Queries, userID uuid.UUID) ([][]any, error) {
+	history, err := db.BackupGetReactionsForUser(context.Background(), userID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching reactions: %w", err)
+	}
+	rows := make([][]any, 0, len(history))
+	for _, h := range history {
+		rows = append(rows, []any{
+			h.ID.String(),
+			h.SyncedAt,
+			h.PostID.String(),
+			nullInt(h.Likes),
+			nullInt(h.Reposts),
+			nullInt(h.Views),
+			nullInt(h.Comments),
+		})
+	}
+	return rows, nil
+}
+
+func tagsRows(db *database.Queries, userID uuid.UUID) ([][]any, error) {
+	tags, err := db.GetTagsForUser(context.Background(), userID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching tags: %w", err)
+	}
+	rows := make([][]any, 0, len(tags))
+	for _, t := range tags {
+		rows = append(rows, []any{
+			t.ID.String(),
+			t.Name,
+			nullString(t.ClassificationName),
+			t.CreatedAt,
+		})
+	}
+	return rows, nil
+}
+
+func postTagsRows(db *database.Queries, userID uuid.UUID) ([][]any, error) {
+	postTags, err := db.GetAllPostTagsForUser(context.Background(), userID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching post tags: %w", err)
+	}
+	rows := make([][]any, 0, len(postTags))
+	for _, pt := range postTags {
+		rows = append(rows, []any{
+			pt.PostID.String(),
+			pt.TagID.String(),
+			pt.TagName,
+			nullString(pt.ClassificationName),
+		})
+	}
+	return rows, nil
+}
+
+func followersRows(db *database.Queries, userID uuid.UUID) ([][]any, error) {
+	sources, err := db.GetUserSources(context.Background(), userID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching sources: %w", err)
+	}
+	byID := make(map[uuid.UUID]database.Source, len(sources))
+	for _, s := range sources {
+		byID[s.ID] = s
+	}
+
+	stats, err := db.BackupGetSourcesStatsForUser(context.Background(), userID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching follower stats: %w", err)
+	}
+	rows := make([][]any, 0, len(stats))
+	for _, s := range stats {
+		source := byID[s.SourceID]
+		rows = append(rows, []any{
+			s.ID.String(),
+			s.Date,
+			source.Network,
+			source.UserName,
+			nullInt(s.FollowersCount),
+			nullInt(s.FollowingCount),
+			nullInt(s.PostsCount),
+			nullInt(s.FollowersDelta),
+			nullInt(s.FollowingDelta),
+			nullInt(s.Karma),
+			nullFloat(s.AverageLikes),
+			nullFloat(s.AverageReposts),
+			nullFloat(s.AverageViews),
+		})
+	}
+	return rows, nil
+}
+
+func websiteRows(db *database.Queries, userID uuid.UUID) ([][]any, error) {
+	stats, err := db.GetAllAnalyticsSiteStatsForUser(context.Background(), userID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching site stats: %w", err)
+	}
+	rows := make([][]any, 0, len(stats))
+	for _, s := range stats {
+		rows = append(rows, []any{
+			s.ID.String(),
+			s.Date,
+			s.AnalyticsType,
+			s.Visitors,
+			s.AvgSessionDuration,
+			nullInt(s.Impressions),
+			s.SourceNetwork,
+			s.SourceUserName,
+		})
+	}
+	return rows, nil
+}
+
+func webpagesRows(db *database.Queries, userID uuid.UUID) ([][]any, error) {
+	stats, err := db.GetAllAnalyticsPageStatsForUser(context.Background(), userID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching pages stats: %w", err)
+	}
+	rows := make([][]any, 0, len(stats))
+	for _, s := range stats {
+		rows = append(rows, []any{
+			s.ID.String(),
+			s.Date,
+			s.AnalyticsType,
+			s.UrlPath,
+			s.Views,
+			nullInt(s.Impressions),
+			s.SourceNetwork,
+			s.SourceUserName,
+		})
+	}
+	return rows, nil
+}
+
+// formatValue renders a value the way the CSV files always have: RFC 3339
+// timestamps, plain dates and empty cells for missing values.
+func formatValue(kind columnKind, v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return fmt.Sprintf("%f", v)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		if kind == kindDate {
+			return v.Format("2006-01-02")
+		}
+		return v.Format(time.RFC3339)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,217 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package targets
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/exports"
+	"github.com/fluffyriot/rpsync/internal/storage"
+)
+
+// table is a dataset with its picked columns and rows, read once per sync.
+type table struct {
+	d    dataset
+	cols []int
+	rows [][]any
+}
+
+// writeFileExports writes each dataset to a file per picked format, each
+// logged as an export. Rows are read once per dataset. It carries on past
+// failures and returns the first.
+func writeFileExports(r Run, settings FileSettings, names ...string) error {
+	formats := settings.pickedFormats()
+	if len(formats) == 0 {
+		return nil
+	}
+
+	var firstErr error
+	for _, name := range names {
+		d, _ := getDataset(name)
+		cols := selectColumns(d, settings.Columns)
+		if len(cols) == 0 {
+			continue
+		}
+
+		logs := make([]database.Export, 0, len(formats))
+		logged := make([]fileFormat, 0, len(formats))
+		for _, f := range formats {
+			method := f.label + " - " + d.label
+			export, err := exports.CreateLogAutoExport(r.Target.UserID, r.DB, method, r.Target.ID)
+			if err != nil {
+				log.Printf("Error creating %s export log: %v", method, err)
+				continue
+			}
+			logs = append(logs, export)
+			logged = append(logged, f)
+		}
+		if len(logs) == 0 {
+			continue
+		}
+
+		rows, err := d.rows(r.DB, r.Target.UserID)
+		for i, export := range logs {
+			var filename string
+			if err == nil {
+				filename, err = generateFile(export, d, logged[i], cols, rows)
+			}
+			if err != nil {
+				exports.UpdateLogAutoExport(export, r.DB, "Failed", err.Error(), "")
+				if firstErr == nil {
+					firstErr = err
+				}
+				err = nil
+				continue
+			}
+			completeFileExport(r, export, filename)
+		}
+	}
+	return firstErr
+}
+
+// completeFileExport marks an export completed, first uploading its file when
+// the user has remote storage. A failed upload leaves the local file as the
+// download and notes the error.
+func completeFileExport(r Run, export database.Export, filename string) {
+	downloadURL, err := storage.Publish(r.DB, r.EncryptionKey, r.Target.UserID, filename)
+	reason := ""
+	if err != nil {
+		log.Printf("Error uploading export %s: %v", export.ID, err)
+		reason = "Upload to remote storage failed: " + err.Error()
+	}
+	exports.UpdateLogAutoExport(export, r.DB, "Completed", reason, downloadURL)
+}
+
+func generateFile(export database.Export, d dataset, f fileFormat, cols []int, rows [][]any) (string, error) {
+	if len(rows) == 0 {
+		return "", nil
+	}
+
+	filename := fmt.Sprintf("outputs/export_id_%s_%s_%s.%s", export.ID.String(), d.name, time.Now().Format("20060102_150405"), f.name)
+	return filename, writeFile(filename, func(w io.Writer) error {
+		return f.write(w, d, cols, rows)
+	})
+}
+
+// writeFile creates an export file, removing it again if writing fails.
+func writeFile(filename string, write func(w io.Writer) error) error {
+	file, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	err = write(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(filename)
+	}
+	return err
+}
+
+// loadTables reads every dataset with columns picked and rows to export.
+func loadTables(r Run, settings FileSettings) ([]table, error) {
+	var tables []table
+	for _, d := range datasets {
+		cols := selectColumns(d, settings.Columns)
+		if len(cols) == 0 {
+			continue
+		}
+		rows, err := d.rows(r.DB, r.Target.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == 0 {
+			continue
+		}
+		tables = append(tables, table{d: d, cols: cols, rows: rows})
+	}
+	return tables, nil
+}
+
+func writeWorkbookExport(r Run, settings FileSettings) error {
+	export, err := exports.CreateLogAutoExport(r.Target.UserID, r.DB, "XLSX - Workbook", r.Target.ID)
+	if err != nil {
+		log.Printf("Error creating XLSX - Workbook export log: %v", err)
+		return nil
+	}
+
+	filename, err := generateWorkbook(r, settings, export)
+	if err != nil {
+		exports.UpdateLogAutoExport(export, r.DB, "Failed", err.Error(), "")
+		return err
+	}
+	completeFileExport(r, export, filename)
+	return nil
+}
+
+func generateWorkbook(r Run, settings FileSettings, export database.Export) (string, error) {
+	tables, err := loadTables(r, settings)
+	if err != nil || len(tables) == 0 {
+		return "", err
+	}
+
+	filename := fmt.Sprintf("outputs/export_id_%s_workbook_%s.xlsx", export.ID.String(), time.Now().Format("20060102_150405"))
+	return filename, writeFile(filename, func(w io.Writer) error {
+		return writeWorkbook(w, tables)
+	})
+}
+
+// writeArchiveExport writes every file of a sync into one zip, logged as a
+// single export.
+func writeArchiveExport(r Run, settings FileSettings) error {
+	method := settings.archiveMethod()
+	export, err := exports.CreateLogAutoExport(r.Target.UserID, r.DB, method, r.Target.ID)
+	if err != nil {
+		log.Printf("Error creating %s export log: %v", method, err)
+		return nil
+	}
+
+	filename, err := generateArchive(r, settings, export)
+	if err != nil {
+		exports.UpdateLogAutoExport(export, r.DB, "Failed", err.Error(), "")
+		return err
+	}
+	completeFileExport(r, export, filename)
+	return nil
+}
+
+func generateArchive(r Run, settings FileSettings, export database.Export) (string, error) {
+	tables, err := loadTables(r, settings)
+	if err != nil || len(tables) == 0 {
+		return "", err
+	}
+
+	filename := fmt.Sprintf("outputs/export_id_%s_archive_%s.zip", export.ID.String(), time.Now().Format("20060102_150405"))
+	return filename, writeFile(filename, func(w io.Writer) error {
+		archive := zip.NewWriter(w)
+		for _, t := range tables {
+			for _, f := range settings.pickedFormats() {
+				entry := t.d.name + "." + f.name
+				ew, err := archive.Create(entry)
+				if err == nil {
+					err = f.write(ew, t.d, t.cols, t.rows)
+				}
+				if err != nil {
+					return fmt.Errorf("writing %s: %w", entry, err)
+				}
+			}
+		}
+		if settings.wantsWorkbook() {
+			ew, err := archive.Create("workbook.xlsx")
+			if err == nil {
+				err = writeWorkbook(ew, tables)
+			}
+			if err != nil {
+				return fmt.Errorf("writing workbook.xlsx: %w", err)
+			}
+		}
+		return archive.Close()
+	})
+}
@@ -0,0 +1,196 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package targets
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// fileFormat writes a dataset to a file of its own. The XLSX workbook holds
+// every dataset instead and is written by writeWorkbook.
+type fileFormat struct {
+	name  string
+	label string
+	write func(w io.Writer, d dataset, cols []int, rows [][]any) error
+}
+
+var fileFormats = []fileFormat{
+	{name: "csv", label: "CSV", write: writeCsv},
+	{name: "ndjson", label: "NDJSON", write: writeNdjson},
+	{name: "parquet", label: "Parquet", write: writeParquet},
+}
+
+const workbookFormat = "xlsx"
+
+// FileFormats lists the formats a file target can write.
+func FileFormats() []string {
+	names := make([]string, 0, len(fileFormats)+1)
+	for _, f := range fileFormats {
+		names = append(names, f.name)
+	}
+	return append(names, workbookFormat)
+}
+
+// pickedFormats returns the per-dataset formats picked in the settings.
+func (s FileSettings) pickedFormats() []fileFormat {
+	var picked []fileFormat
+	for _, f := range fileFormats {
+		if slices.Contains(s.Formats, f.name) {
+			picked = append(picked, f)
+		}
+	}
+	return picked
+}
+
+func (s FileSettings) wantsWorkbook() bool {
+	return slices.Contains(s.Formats, workbookFormat)
+}
+
+// archiveMethod names the export of a sync's archive after the formats it
+// holds, e.g. "CSV, Parquet - Archive".
+func (s FileSettings) archiveMethod() string {
+	var labels []string
+	for _, f := range s.pickedFormats() {
+		labels = append(labels, f.label)
+	}
+	if s.wantsWorkbook() {
+		labels = append(labels, "XLSX")
+	}
+	return strings.Join(labels, ", ") + " - Archive"
+}
+
+// writeNdjson writes one JSON object per row, with keys in column order.
+// Numbers and booleans keep their type, timestamps are RFC 3339 in UTC and
+// missing values are null.
+func writeNdjson(w io.Writer, d dataset, cols []int, rows [][]any) error {
+	bw := bufio.NewWriter(w)
+
+	keys := make([][]byte, len(cols))
+	for i, c := range cols {
+		key, err := json.Marshal(d.columns[c].name)
+		if err != nil {
+			return err
+		}
+		keys[i] = key
+	}
+
+	for _, row := range rows {
+		bw.WriteByte('{')
+		for i, c := range cols {
+			if i > 0 {
+				bw.WriteByte(',')
+			}
+			bw.Write(keys[i])
+			bw.WriteByte(':')
+
+			v := row[c]
+			if t, ok := v.(time.Time); ok {
+				if d.columns[c].kind == kindDate {
+					v = t.Format("2006-01-02")
+				} else {
+					v = t.UTC().Format(time.RFC3339)
+				}
+			}
+			value, err := json.Marshal(v)
+			if err != nil {
+				return fmt.Errorf("encoding %s: %w", d.columns[c].name, err)
+			}
+			bw.Write(value)
+		}
+		bw.WriteString("}\n")
+	}
+
+	return bw.Flush()
+}
+
+// parquetBatchSize is the number of rows handed to the writer at once.
+const parquetBatchSize = 1000
+
+// writeParquet writes a zstd compressed Parquet file with one optional
+// column per dataset column: strings, int64, double, boolean, microsecond
+// UTC timestamps and dates.
+func writeParquet(w io.Writer, d dataset, cols []int, rows [][]any) error {
+	group := parquet.Group{}
+	byName := make(map[string]int, len(cols))
+	for _, c := range cols {
+		col := d.columns[c]
+		group[col.name] = parquet.Optional(parquetNode(col.kind))
+		byName[col.name] = c
+	}
+	schema := parquet.NewSchema(d.name, group)
+
+	// A group orders its fields by name, which sets the column indexes.
+	fields := schema.Fields()
+	order := make([]int, len(fields))
+	for i, f := range fields {
+		order[i] = byName[f.Name()]
+	}
+
+	pw := parquet.NewWriter(w, schema, parquet.Compression(&parquet.Zstd))
+	batch := make([]parquet.Row, 0, parquetBatchSize)
+	for _, row := range rows {
+		prow := make(parquet.Row, len(order))
+		for i, c := range order {
+			if row[c] == nil {
+				prow[i] = parquet.NullValue().Level(0, 0, i)
+				continue
+			}
+			prow[i] = parquetValue(d.columns[c].kind, row[c]).Level(0, 1, i)
+		}
+		batch = append(batch, prow)
+
+		if len(batch) == parquetBatchSize {
+			if _, err := pw.WriteRows(batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if _, err := pw.WriteRows(batch); err != nil {
+		return err
+	}
+	return pw.Close()
+}
+
+func parquetNode(kind columnKind) parquet.Node {
+	switch kind {
+	case kindInt:
+		return parquet.Leaf(parquet.Int64Type)
+	case kindFloat:
+		return parquet.Leaf(parquet.DoubleType)
+	case kindBool:
+		return parquet.Leaf(parquet.BooleanType)
+	case kindTime:
+		return parquet.Timestamp(parquet.Microsecond)
+	case kindDate:
+		return parquet.Date()
+	}
+	return parquet.String()
+}
+
+func parquetValue(kind columnKind, v any) parquet.Value {
+	switch v := v.(type) {
+	case int64:
+		return parquet.Int64Value(v)
+	case float64:
+		return parquet.DoubleValue(v)
+	case bool:
+		return parquet.BooleanValue(v)
+	case time.Time:
+		if kind == kindDate {
+			y, m, d := v.Date()
+			return parquet.Int32Value(int32(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / 86400))
+		}
+		return parquet.Int64Value(v.UnixMicro())
+	case string:
+		return parquet.ByteArrayValue([]byte(v))
+	}
+	return parquet.ByteArrayValue([]byte(fmt.Sprint(v)))
+}
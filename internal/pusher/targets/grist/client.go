@@ -0,0 +1,326 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package grist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/authhelp"
+	"github.com/fluffyriot/rpsync/internal/pusher/targets"
+	"github.com/fluffyriot/rpsync/internal/pusher/targets/records"
+)
+
+const (
+	defaultHost = "https://docs.getgrist.com"
+	maxRetries  = 5
+	batchSize   = 500
+)
+
+type client struct {
+	r     targets.Run
+	host  string
+	token string
+	// rowIDs caches the row IDs of each table read during this step, so
+	// updates and deletes can skip rows removed by hand.
+	rowIDs map[string]map[int64]bool
+}
+
+func newClient(r targets.Run) (records.Store, error) {
+	token, _, _, err := authhelp.GetTargetToken(context.Background(), r.DB, r.EncryptionKey, r.Target.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Grist API key: %w", err)
+	}
+
+	host := strings.TrimSuffix(r.Target.HostUrl.String, "/")
+	if host == "" {
+		host = defaultHost
+	}
+
+	return &client{r: r, host: host, token: token, rowIDs: map[string]map[int64]bool{}}, nil
+}
+
+func (g *client) BatchSize() int {
+	return batchSize
+}
+
+type column struct {
+	ID     string         `json:"id"`
+	Fields map[string]any `json:"fields"`
+}
+
+func gristColumn(c records.Column, linkTable string) column {
+	fields := map[string]any{"label": c.Name}
+	widget := map[string]any{}
+
+	switch c.Kind {
+	case records.Text:
+		fields["type"] = "Text"
+	case records.LongText:
+		fields["type"] = "Text"
+		widget["wrap"] = true
+	case records.Number:
+		fields["type"] = "Int"
+	case records.Decimal:
+		fields["type"] = "Numeric"
+	case records.Date:
+		fields["type"] = "Date"
+		widget["dateFormat"] = "YYYY-MM-DD"
+	case records.DateTime:
+		fields["type"] = "DateTime:UTC"
+		widget["dateFormat"] = "YYYY-MM-DD"
+		widget["timeFormat"] = "HH:mm:ss"
+	case records.Checkbox:
+		fields["type"] = "Bool"
+	case records.URL:
+		fields["type"] = "Text"
+		widget["widget"] = "HyperLink"
+	case records.Select:
+		fields["type"] = "Choice"
+		widget["choices"] = c.Choices
+	case records.Link:
+		fields["type"] = "Ref:" + linkTable
+	}
+
+	// Grist stores widget options as a JSON string.
+	if len(widget) > 0 {
+		options, _ := json.Marshal(widget)
+		fields["widgetOptions"] = string(options)
+	}
+	return column{ID: c.Name, Fields: fields}
+}
+
+func (g *client) CreateTable(t records.Table, linkTable string) (string, map[string]string, error) {
+	columns := make([]column, len(t.Columns))
+	for i, c := range t.Columns {
+		columns[i] = gristColumn(c, linkTable)
+	}
+
+	var resp struct {
+		Tables []struct {
+			ID string `json:"id"`
+		} `json:"tables"`
+	}
+	body := map[string]any{"tables": []any{map[string]any{"id": t.Name, "columns": columns}}}
+	if err := g.do("POST", g.docPath("/tables"), body, &resp); err != nil {
+		return "", nil, err
+	}
+	if len(resp.Tables) == 0 {
+		return "", nil, fmt.Errorf("grist did not return the id of table %s", t.Name)
+	}
+
+	// Grist capitalizes table IDs but keeps the column IDs given.
+	ids := make(map[string]string, len(t.Columns))
+	for _, c := range t.Columns {
+		ids[c.Name] = c.Name
+	}
+	return resp.Tables[0].ID, ids, nil
+}
+
+func (g *client) CreateColumn(tableID string, c records.Column, linkTable string) (string, error) {
+	var resp struct {
+		Columns []struct {
+			ID string `json:"id"`
+		} `json:"columns"`
+	}
+	body := map[string]any{"columns": []column{gristColumn(c, linkTable)}}
+	if err := g.do("POST", g.docPath("/tables/"+tableID+"/columns"), body, &resp); err != nil {
+		return "", fmt.Errorf("create column %s: %w", c.Name, err)
+	}
+	if len(resp.Columns) == 0 {
+		return c.Name, nil
+	}
+	return resp.Columns[0].ID, nil
+}
+
+func (g *client) CreateRecords(t records.Table, tableID string, rows []records.Row) ([]int64, error) {
+	recs := make([]map[string]any, len(rows))
+	for i, row := range rows {
+		recs[i] = map[string]any{"fields": encode(t, row)}
+	}
+
+	var resp struct {
+		Records []struct {
+			ID int64 `json:"id"`
+		} `json:"records"`
+	}
+	if err := g.do("POST", g.docPath("/tables/"+tableID+"/records"), map[string]any{"records": recs}, &resp); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(resp.Records))
+	for i, rec := range resp.Records {
+		ids[i] = rec.ID
+		if known, ok := g.rowIDs[tableID]; ok {
+			known[rec.ID] = true
+		}
+	}
+	return ids, nil
+}
+
+// UpdateRecords patches the rows that still exist. Grist rejects a whole
+// request naming a missing row, so the table's row IDs are read first.
+func (g *client) UpdateRecords(t records.Table, tableID string, recs []records.Record) ([]int64, error) {
+	known, err := g.existingRows(tableID)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []int64
+	var patch []map[string]any
+	for _, rec := range recs {
+		if !known[rec.ID] {
+			missing = append(missing, rec.ID)
+			continue
+		}
+		patch = append(patch, map[string]any{"id": rec.ID, "fields": encode(t, rec.Row)})
+	}
+
+	if len(patch) > 0 {
+		if err := g.do("PATCH", g.docPath("/tables/"+tableID+"/records"), map[string]any{"records": patch}, nil); err != nil {
+			return nil, err
+		}
+	}
+	return missing, nil
+}
+
+func (g *client) DeleteRecords(tableID string, ids []int64) error {
+	known, err := g.existingRows(tableID)
+	if err != nil {
+		return err
+	}
+
+	var remove []int64
+	for _, id := range ids {
+		if known[id] {
+			remove = append(remove, id)
+			delete(known, id)
+		}
+	}
+	if len(remove) == 0 {
+		return nil
+	}
+	return g.do("POST", g.docPath("/tables/"+tableID+"/data/delete"), remove, nil)
+}
+
+func (g *client) existingRows(tableID string) (map[int64]bool, error) {
+	if known, ok := g.rowIDs[tableID]; ok {
+		return known, nil
+	}
+
+	var resp struct {
+		Records []struct {
+			Fields struct {
+				ID int64 `json:"id"`
+			} `json:"fields"`
+		} `json:"records"`
+	}
+	query := url.Values{"q": {`SELECT id FROM "` + tableID + `"`}}
+	if err := g.do("GET", g.docPath("/sql?"+query.Encode()), nil, &resp); err != nil {
+		return nil, fmt.Errorf("list rows of %s: %w", tableID, err)
+	}
+
+	known := make(map[int64]bool, len(resp.Records))
+	for _, rec := range resp.Records {
+		known[rec.Fields.ID] = true
+	}
+	g.rowIDs[tableID] = known
+	return known, nil
+}
+
+// encode converts a row to Grist's cell values: dates are seconds since the
+// epoch and an empty reference is row 0.
+func encode(t records.Table, row records.Row) map[string]any {
+	fields := make(map[string]any, len(row))
+	for name, v := range row {
+		c, _ := t.Column(name)
+		switch val := v.(type) {
+		case nil:
+			if c.Kind == records.Link {
+				fields[name] = 0
+			} else {
+				fields[name] = nil
+			}
+		case time.Time:
+			if c.Kind == records.Date {
+				y, m, d := val.Date()
+				fields[name] = time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix()
+			} else {
+				fields[name] = val.Unix()
+			}
+		default:
+			fields[name] = val
+		}
+	}
+	return fields
+}
+
+func (g *client) docPath(path string) string {
+	return "/api/docs/" + url.PathEscape(g.r.Target.DbID.String) + path
+}
+
+func (g *client) do(method, path string, body, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("marshal request: %w", err)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest(method, g.host+path, bytes.NewReader(payload))
+		if err != nil {
+			return fmt.Errorf("create request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+g.token)
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := g.r.Client.HTTPClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("send request: %w", err)
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("read response body: %w", err)
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests && attempt < maxRetries:
+			delay := time.Duration(1<<attempt) * time.Second
+			if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+				delay = time.Duration(secs) * time.Second
+			}
+			log.Printf("Grist rate limit hit, retrying in %s", delay)
+			time.Sleep(delay)
+			continue
+		case resp.StatusCode != http.StatusOK:
+			var apiErr struct {
+				Error string `json:"error"`
+			}
+			if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Error != "" {
+				return fmt.Errorf("grist %s %s: %s", method, path, apiErr.Error)
+			}
+			return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(respBody))
+		}
+
+		if out == nil || len(respBody) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("decode response: %w", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package grist
+
+import (
+	"github.com/fluffyriot/rpsync/internal/helpers"
+	"github.com/fluffyriot/rpsync/internal/pusher/targets"
+	"github.com/fluffyriot/rpsync/internal/pusher/targets/records"
+)
+
+func init() {
+	targets.Register(targets.Provider{
+		Network: helpers.TargetNetwork{
+			Name:  "Grist",
+			Color: "#16b378",
+			Fields: []helpers.TargetField{
+				{Name: "db_id", Label: "Document Id", Placeholder: "aBcDeFgHiJkLmNoPqRsTuV"},
+				{Name: "api_token", Label: "API Key", Placeholder: "xxxxxxxxxxxxxxxxxxxxxx"},
+				{Name: "host_url", Label: "Host Url", Placeholder: "https://docs.getgrist.com", Optional: true},
+			},
+		},
+		// Grist keeps the same tables as NocoDB; posts and stats reference
+		// their row in Sources.
+		Target: records.Target{Open: newClient},
+	})
+}
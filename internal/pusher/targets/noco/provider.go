@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package noco
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/helpers"
+	"github.com/fluffyriot/rpsync/internal/pusher/targets"
+)
+
+func init() {
+	targets.Register(targets.Provider{
+		Network: helpers.TargetNetwork{
+			Name:  "NocoDB",
+			Color: "#4351e8",
+			Fields: []helpers.TargetField{
+				{Name: "db_id", Label: "Database Id", Placeholder: "Database Id"},
+				{Name: "api_token", Label: "API Bearer Token", Placeholder: "xxxxxxxxxxxxxxxxxxxxxx"},
+				{Name: "host_url", Label: "Host Url", Placeholder: "http://127.0.0.1"},
+			},
+		},
+		Target: NocoDB{},
+	})
+}
+
+// NocoDB mirrors sources, posts, stats and analytics into tables of a NocoDB
+// base, keeping the record IDs in the mapping tables so later syncs update
+// them in place.
+type NocoDB struct{}
+
+func (NocoDB) Initialize(r targets.Run) error {
+	_, err := r.DB.GetTableMappingsByTargetAndName(context.Background(), database.GetTableMappingsByTargetAndNameParams{
+		TargetID:        r.Target.ID,
+		TargetTableName: "Analytics_Page_Stats",
+	})
+	if err == nil {
+		return nil
+	}
+	return InitializeNoco(r.DB, r.Client, r.EncryptionKey, r.Target)
+}
+
+func (NocoDB) PushSources(r targets.Run) error {
+	sourcesTable, err := r.DB.GetTableMappingsByTargetAndName(context.Background(), database.GetTableMappingsByTargetAndNameParams{
+		TargetID:        r.Target.ID,
+		TargetTableName: "sources",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get target source table: %w", err)
+	}
+
+	return syncNocoSources(r.Client, r.DB, r.EncryptionKey, r.Target, sourcesTable.TargetTableCode.String)
+}
+
+func (NocoDB) PushAnalytics(r targets.Run) error {
+	if err := syncNocoAnalyticsSiteStats(r.DB, r.Client, r.EncryptionKey, r.Target); err != nil {
+		return fmt.Errorf("failed to sync site stats: %w", err)
+	}
+	if err := syncNocoAnalyticsPageStats(r.DB, r.Client, r.EncryptionKey, r.Target); err != nil {
+		return fmt.Errorf("failed to sync page stats: %w", err)
+	}
+	return nil
+}
+
+func (NocoDB) PushStats(r targets.Run) error {
+	return syncNocoSourcesStats(r.DB, r.Client, r.EncryptionKey, r.Target)
+}
+
+func (NocoDB) PushPosts(r targets.Run) error {
+	return syncNocoPosts(r.DB, r.Client, r.EncryptionKey, r.Target)
+}
+
+func (NocoDB) RemoveSource(r targets.Run, source database.Source) error {
+	return DeletePostsAndSourceNoco(r.DB, r.Client, r.EncryptionKey, r.Target, source)
+}
@@ -14,7 +14,7 @@ import (
 	"github.com/google/uuid"
 )
 
-func SyncNoco(dbQueries *database.Queries, c *common.Client, encryptionKey []byte, target database.Target) error {
+func syncNocoPosts(dbQueries *database.Queries, c *common.Client, encryptionKey []byte, target database.Target) error {
 
 	sourcesTable, err := dbQueries.GetTableMappingsByTargetAndName(context.Background(), database.GetTableMappingsByTargetAndNameParams{
 		TargetID:        target.ID,
@@ -24,23 +24,6 @@ func SyncNoco(dbQueries *database.Queries, c *common.Client, encryptionKey []byt
 		return fmt.Errorf("failed to get target source table: %w", err)
 	}
 
-	err = syncNocoSources(c, dbQueries, encryptionKey, target, sourcesTable.TargetTableCode.String)
-	if err != nil {
-		return fmt.Errorf("failed to sync sources: %w", err)
-	}
-
-	if err := syncNocoAnalyticsSiteStats(dbQueries, c, encryptionKey, target); err != nil {
-		return fmt.Errorf("failed to sync site stats: %w", err)
-	}
-
-	if err := syncNocoAnalyticsPageStats(dbQueries, c, encryptionKey, target); err != nil {
-		return fmt.Errorf("failed to sync page stats: %w", err)
-	}
-
-	if err := syncNocoSourcesStats(dbQueries, c, encryptionKey, target); err != nil {
-		return fmt.Errorf("failed to sync sources stats: %w", err)
-	}
-
 	posts, err := dbQueries.GetAllPostsWithTheLatestInfoForUser(context.Background(), target.UserID)
 	if err != nil {
 		return err
@@ -188,6 +188,54 @@ func syncNocoSources(c *common.Client, dbQueries *database.Queries, encryptionKe
 		return err
 	}
 
+	// Mapped rows are refreshed so renamed sources show their new handle.
+	var recordsUpdate []NocoTableRecord
+
+	flushUpdate := func() error {
+		if len(recordsUpdate) == 0 {
+			return nil
+		}
+		if err := updateNocoRecords(c, dbQueries, encryptionKey, target, tableId, recordsUpdate); err != nil {
+			return err
+		}
+		recordsUpdate = recordsUpdate[:0]
+		return nil
+	}
+
+	for id, mSource := range mappedMap {
+		source, ok := internMap[id]
+		if !ok {
+			continue
+		}
+		recordId, err := strconv.Atoi(mSource.TargetSourceID)
+		if err != nil {
+			continue
+		}
+
+		url, _ := helpers.ConvNetworkToURL(source.Network, source.UserName)
+
+		recordsUpdate = append(recordsUpdate, NocoTableRecord{
+			Id: recordId,
+			Fields: NocoRecordFields{
+				ID:         source.ID.String(),
+				LastSynced: source.LastSynced.Time,
+				Network:    source.Network,
+				Username:   source.UserName,
+				URL:        url,
+			},
+		})
+
+		if len(recordsUpdate) == batchSize {
+			if err := flushUpdate(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := flushUpdate(); err != nil {
+		return err
+	}
+
 	var recordsDelete []NocoDeleteRecord
 
 	flushDelete := func() error {
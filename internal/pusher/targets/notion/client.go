@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package notion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/authhelp"
+	"github.com/fluffyriot/rpsync/internal/pusher/pacer"
+	"github.com/fluffyriot/rpsync/internal/pusher/targets"
+)
+
+const (
+	apiURL     = "https://api.notion.com/v1"
+	apiVersion = "2022-06-28"
+
+	// requestInterval keeps a sync under Notion's average limit of three
+	// requests per second per integration.
+	requestInterval = 350 * time.Millisecond
+	maxRetries      = 5
+)
+
+// errNotFound is returned for pages the user deleted or did not share with
+// the integration.
+var errNotFound = errors.New("not found in Notion")
+
+var notionIDPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{12}`)
+
+type client struct {
+	r     targets.Run
+	token string
+	pace  *pacer.Pacer
+}
+
+var pacers = pacer.NewGroup(requestInterval)
+
+func newClient(r targets.Run) (*client, error) {
+	token, _, _, err := authhelp.GetTargetToken(context.Background(), r.DB, r.EncryptionKey, r.Target.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Notion token: %w", err)
+	}
+	return &client{r: r, token: token, pace: pacers.For(r.Target.ID)}, nil
+}
+
+// parentPageID accepts a bare page ID or a page URL as copied from Notion.
+func parentPageID(dbID string) (string, error) {
+	matches := notionIDPattern.FindAllString(dbID, -1)
+	if len(matches) == 0 {
+		return "", fmt.Errorf("%q is not a Notion page ID or URL", dbID)
+	}
+	return strings.ReplaceAll(matches[len(matches)-1], "-", ""), nil
+}
+
+func (n *client) createDatabase(s schema, parentID string) (*databaseResponse, error) {
+	body := map[string]any{
+		"parent":      map[string]any{"type": "page_id", "page_id": parentID},
+		"title":       []any{textObject(s.Name)},
+		"description": []any{textObject(s.Description)},
+		"properties":  s.Properties,
+	}
+
+	var resp databaseResponse
+	if err := n.do("POST", "/databases", body, &resp); err != nil {
+		return nil, fmt.Errorf("create %s database: %w", s.Name, err)
+	}
+	return &resp, nil
+}
+
+func (n *client) createPage(databaseID string, props properties) (string, error) {
+	body := map[string]any{
+		"parent":     map[string]any{"database_id": databaseID},
+		"properties": props,
+	}
+
+	var resp pageResponse
+	if err := n.do("POST", "/pages", body, &resp); err != nil {
+		return "", err
+	}
+	return resp.ID, nil
+}
+
+func (n *client) updatePage(pageID string, props properties) error {
+	return n.do("PATCH", "/pages/"+pageID, map[string]any{"properties": props}, nil)
+}
+
+// archivePage moves a page to the trash. Pages that are already gone are
+// ignored.
+func (n *client) archivePage(pageID string) error {
+	err := n.do("PATCH", "/pages/"+pageID, map[string]any{"archived": true}, nil)
+	if errors.Is(err, errNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (n *client) do(method, path string, body, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		n.pace.Wait()
+
+		req, err := http.NewRequest(method, apiURL+path, bytes.NewReader(payload))
+		if err != nil {
+			return fmt.Errorf("create request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+n.token)
+		req.Header.Set("Notion-Version", apiVersion)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := n.r.Client.HTTPClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("send request: %w", err)
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("read response body: %w", err)
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests && attempt < maxRetries:
+			delay := time.Second
+			if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+				delay = time.Duration(secs) * time.Second
+			}
+			log.Printf("Notion rate limit hit, retrying in %s", delay)
+			time.Sleep(delay)
+			continue
+		case resp.StatusCode >= http.StatusInternalServerError && attempt < maxRetries:
+			delay := time.Duration(1<<attempt) * time.Second
+			log.Printf("Notion returned status %d, retrying in %s", resp.StatusCode, delay)
+			time.Sleep(delay)
+			continue
+		case resp.StatusCode == http.StatusNotFound:
+			return fmt.Errorf("%s %s: %w", method, path, errNotFound)
+		case resp.StatusCode != http.StatusOK:
+			var apiErr struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			}
+			if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Message != "" {
+				return fmt.Errorf("notion %s: %s", apiErr.Code, apiErr.Message)
+			}
+			return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(respBody))
+		}
+
+		if out == nil {
+			return nil
+		}
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("decode response: %w", err)
+		}
+		return nil
+	}
+}
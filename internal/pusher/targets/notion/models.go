@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package notion
+
+import (
+	"database/sql"
+	"time"
+)
+
+// maxTextLength is the longest text Notion accepts in one rich text object.
+const maxTextLength = 2000
+
+// properties maps property names to Notion property values or, when
+// creating a database, to property schemas.
+type properties map[string]map[string]any
+
+type schema struct {
+	Name        string
+	Description string
+	Properties  properties
+}
+
+type databaseResponse struct {
+	ID         string `json:"id"`
+	Properties map[string]struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"properties"`
+}
+
+type pageResponse struct {
+	ID string `json:"id"`
+}
+
+func textObject(s string) map[string]any {
+	return map[string]any{
+		"type": "text",
+		"text": map[string]any{"content": truncate(s, maxTextLength)},
+	}
+}
+
+func titleValue(s string) map[string]any {
+	return map[string]any{"title": []any{textObject(s)}}
+}
+
+func textValue(s string) map[string]any {
+	if s == "" {
+		return map[string]any{"rich_text": []any{}}
+	}
+	return map[string]any{"rich_text": []any{textObject(s)}}
+}
+
+func dateValue(t time.Time) map[string]any {
+	if t.IsZero() {
+		return map[string]any{"date": nil}
+	}
+	return map[string]any{"date": map[string]any{"start": t.Format(time.RFC3339)}}
+}
+
+func dayValue(t time.Time) map[string]any {
+	return map[string]any{"date": map[string]any{"start": t.Format("2006-01-02")}}
+}
+
+func numberValue(v any) map[string]any {
+	switch n := v.(type) {
+	case sql.NullInt64:
+		if !n.Valid {
+			return map[string]any{"number": nil}
+		}
+		return map[string]any{"number": n.Int64}
+	case sql.NullFloat64:
+		if !n.Valid {
+			return map[string]any{"number": nil}
+		}
+		return map[string]any{"number": n.Float64}
+	default:
+		return map[string]any{"number": v}
+	}
+}
+
+func urlValue(s string) map[string]any {
+	if s == "" {
+		return map[string]any{"url": nil}
+	}
+	return map[string]any{"url": s}
+}
+
+func checkboxValue(b bool) map[string]any {
+	return map[string]any{"checkbox": b}
+}
+
+func selectValue(s string) map[string]any {
+	if s == "" {
+		return map[string]any{"select": nil}
+	}
+	return map[string]any{"select": map[string]any{"name": s}}
+}
+
+func relationValue(pageID string) map[string]any {
+	if pageID == "" {
+		return map[string]any{"relation": []any{}}
+	}
+	return map[string]any{"relation": []any{map[string]any{"id": pageID}}}
+}
+
+func truncate(s string, length int) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+	return string(runes[:length-1]) + "…"
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package notion
+
+import (
+	"fmt"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/helpers"
+	"github.com/fluffyriot/rpsync/internal/pusher/targets"
+)
+
+func init() {
+	targets.Register(targets.Provider{
+		Network: helpers.TargetNetwork{
+			Name:  "Notion",
+			Color: "#000000",
+			Fields: []helpers.TargetField{
+				{Name: "db_id", Label: "Parent Page URL or Id", Placeholder: "https://www.notion.so/Workspace/Social-Stats-0123456789abcdef0123456789abcdef"},
+				{Name: "api_token", Label: "Integration Secret", Placeholder: "ntn_xxxxxxxxxxxxxxxxxxxxxx"},
+			},
+		},
+		Target: Notion{},
+	})
+}
+
+// Notion keeps one database each for sources, posts, source stats and website
+// stats under a page shared with the integration. Page IDs are tracked in the
+// same mapping tables NocoDB record IDs are; pages for removed rows are
+// archived rather than deleted.
+type Notion struct{}
+
+func (Notion) Initialize(r targets.Run) error {
+	n, err := newClient(r)
+	if err != nil {
+		return err
+	}
+	return initializeNotion(n)
+}
+
+func (Notion) PushSources(r targets.Run) error {
+	n, err := newClient(r)
+	if err != nil {
+		return err
+	}
+	return syncNotionSources(n)
+}
+
+func (Notion) PushAnalytics(r targets.Run) error {
+	n, err := newClient(r)
+	if err != nil {
+		return err
+	}
+	if err := syncNotionAnalyticsSiteStats(n); err != nil {
+		return fmt.Errorf("failed to sync site stats: %w", err)
+	}
+	if err := syncNotionAnalyticsPageStats(n); err != nil {
+		return fmt.Errorf("failed to sync page stats: %w", err)
+	}
+	return nil
+}
+
+func (Notion) PushStats(r targets.Run) error {
+	n, err := newClient(r)
+	if err != nil {
+		return err
+	}
+	return syncNotionSourcesStats(n)
+}
+
+func (Notion) PushPosts(r targets.Run) error {
+	n, err := newClient(r)
+	if err != nil {
+		return err
+	}
+	return syncNotionPosts(n)
+}
+
+func (Notion) RemoveSource(r targets.Run, source database.Source) error {
+	n, err := newClient(r)
+	if err != nil {
+		return err
+	}
+	return removeNotionSource(n, source)
+}
@@ -0,0 +1,207 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package notion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/pusher/targets/records"
+	"github.com/google/uuid"
+)
+
+func siteStatProperties(stat database.AnalyticsSiteStat, sourcePage string) properties {
+	return properties{
+		"Name":                 titleValue(stat.Date.Format("2006-01-02")),
+		"ct_id":                textValue(stat.ID.String()),
+		"source":               relationValue(sourcePage),
+		"date":                 dayValue(stat.Date),
+		"analytics_type":       selectValue(stat.AnalyticsType),
+		"visitors":             numberValue(stat.Visitors),
+		"avg_session_duration": numberValue(stat.AvgSessionDuration),
+		"impressions":          numberValue(stat.Impressions),
+	}
+}
+
+func pageStatProperties(stat database.AnalyticsPageStat, sourcePage string) properties {
+	return properties{
+		"Name":           titleValue(stat.UrlPath),
+		"ct_id":          textValue(stat.ID.String()),
+		"source":         relationValue(sourcePage),
+		"date":           dayValue(stat.Date),
+		"analytics_type": selectValue(stat.AnalyticsType),
+		"page_path":      textValue(stat.UrlPath),
+		"views":          numberValue(stat.Views),
+		"impressions":    numberValue(stat.Impressions),
+	}
+}
+
+func syncNotionAnalyticsSiteStats(n *client) error {
+	databaseID, err := databaseID(n, "analytics_site_stats")
+	if err != nil {
+		return err
+	}
+
+	pages, err := sourcePages(n)
+	if err != nil {
+		return err
+	}
+
+	dateThreshold := time.Now().AddDate(0, 0, -records.AnalyticsUpdateDays)
+
+	for sourceID, sourcePage := range pages {
+		syncedStats, err := n.r.DB.GetSyncedSiteStatsForUpdate(context.Background(), database.GetSyncedSiteStatsForUpdateParams{
+			TargetID: n.r.Target.ID,
+			SourceID: sourceID,
+			Date:     dateThreshold,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, row := range syncedStats {
+			stat := database.AnalyticsSiteStat{
+				ID:                 row.ID,
+				Date:               row.Date,
+				Visitors:           row.Visitors,
+				AvgSessionDuration: row.AvgSessionDuration,
+				SourceID:           row.SourceID,
+				AnalyticsType:      row.AnalyticsType,
+				Impressions:        row.Impressions,
+			}
+			if err := n.updatePage(row.TargetRecordID, siteStatProperties(stat, sourcePage)); err != nil && !errors.Is(err, errNotFound) {
+				return err
+			}
+		}
+
+		unsyncedStats, err := n.r.DB.GetUnsyncedSiteStatsForTarget(context.Background(), database.GetUnsyncedSiteStatsForTargetParams{
+			TargetID: n.r.Target.ID,
+			SourceID: sourceID,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, stat := range unsyncedStats {
+			pageID, err := n.createPage(databaseID, siteStatProperties(stat, sourcePage))
+			if err != nil {
+				return err
+			}
+
+			if _, err := n.r.DB.AddAnalyticsSiteStatToTarget(context.Background(), database.AddAnalyticsSiteStatToTargetParams{
+				ID:             uuid.New(),
+				SyncedAt:       time.Now(),
+				StatID:         uuid.NullUUID{UUID: stat.ID, Valid: true},
+				TargetID:       n.r.Target.ID,
+				TargetRecordID: pageID,
+			}); err != nil {
+				return fmt.Errorf("failed to map site stat: %w", err)
+			}
+		}
+	}
+
+	// Stats replaced by a re-fetch leave mappings without a stat behind.
+	mappings, err := n.r.DB.GetSiteStatsOnTarget(context.Background(), n.r.Target.ID)
+	if err != nil {
+		return err
+	}
+	for _, m := range mappings {
+		if m.StatID.Valid {
+			continue
+		}
+		if err := n.archivePage(m.TargetRecordID); err != nil {
+			return err
+		}
+		if err := n.r.DB.DeleteAnalyticsSiteStatOnTarget(context.Background(), m.ID); err != nil {
+			log.Printf("Warning: failed to delete site stat mapping %s: %v", m.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func syncNotionAnalyticsPageStats(n *client) error {
+	databaseID, err := databaseID(n, "analytics_page_stats")
+	if err != nil {
+		return err
+	}
+
+	pages, err := sourcePages(n)
+	if err != nil {
+		return err
+	}
+
+	dateThreshold := time.Now().AddDate(0, 0, -records.AnalyticsUpdateDays)
+
+	for sourceID, sourcePage := range pages {
+		syncedStats, err := n.r.DB.GetSyncedPageStatsForUpdate(context.Background(), database.GetSyncedPageStatsForUpdateParams{
+			TargetID: n.r.Target.ID,
+			SourceID: sourceID,
+			Date:     dateThreshold,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, row := range syncedStats {
+			stat := database.AnalyticsPageStat{
+				ID:            row.ID,
+				Date:          row.Date,
+				UrlPath:       row.UrlPath,
+				Views:         row.Views,
+				SourceID:      row.SourceID,
+				AnalyticsType: row.AnalyticsType,
+				Impressions:   row.Impressions,
+			}
+			if err := n.updatePage(row.TargetRecordID, pageStatProperties(stat, sourcePage)); err != nil && !errors.Is(err, errNotFound) {
+				return err
+			}
+		}
+
+		unsyncedStats, err := n.r.DB.GetUnsyncedPageStatsForTarget(context.Background(), database.GetUnsyncedPageStatsForTargetParams{
+			TargetID: n.r.Target.ID,
+			SourceID: sourceID,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, stat := range unsyncedStats {
+			pageID, err := n.createPage(databaseID, pageStatProperties(stat, sourcePage))
+			if err != nil {
+				return err
+			}
+
+			if _, err := n.r.DB.AddAnalyticsPageStatToTarget(context.Background(), database.AddAnalyticsPageStatToTargetParams{
+				ID:             uuid.New(),
+				SyncedAt:       time.Now(),
+				StatID:         uuid.NullUUID{UUID: stat.ID, Valid: true},
+				TargetID:       n.r.Target.ID,
+				TargetRecordID: pageID,
+			}); err != nil {
+				return fmt.Errorf("failed to map page stat: %w", err)
+			}
+		}
+	}
+
+	mappings, err := n.r.DB.GetPageStatsOnTarget(context.Background(), n.r.Target.ID)
+	if err != nil {
+		return err
+	}
+	for _, m := range mappings {
+		if m.StatID.Valid {
+			continue
+		}
+		if err := n.archivePage(m.TargetRecordID); err != nil {
+			return err
+		}
+		if err := n.r.DB.DeleteAnalyticsPageStatOnTarget(context.Background(), m.ID); err != nil {
+			log.Printf("Warning: failed to delete page stat mapping %s: %v", m.ID, err)
+		}
+	}
+
+	return nil
+}
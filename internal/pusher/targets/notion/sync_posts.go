@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package notion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/helpers"
+	"github.com/google/uuid"
+)
+
+// postTitleLength keeps post titles readable in Notion's table view.
+const postTitleLength = 100
+
+func postProperties(post database.GetAllPostsWithTheLatestInfoForUserRow, sourcePage string) (properties, error) {
+	url, err := helpers.ConvPostToURL(post.Network.String, post.Author, post.NetworkInternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	title, _, _ := strings.Cut(strings.TrimSpace(post.Content.String), "\n")
+	if title == "" {
+		title = post.Network.String + " " + post.PostType + " " + post.NetworkInternalID
+	}
+
+	return properties{
+		"Name":                titleValue(truncate(title, postTitleLength)),
+		"ct_id":               textValue(post.ID.String()),
+		"source":              relationValue(sourcePage),
+		"created_at":          dateValue(post.CreatedAt),
+		"last_synced":         dateValue(time.Now()),
+		"is_archived":         checkboxValue(post.IsArchived),
+		"network_internal_id": textValue(post.NetworkInternalID),
+		"post_type":           selectValue(post.PostType),
+		"author":              textValue(post.Author),
+		"content":             textValue(post.Content.String),
+		"likes":               numberValue(post.Likes),
+		"views":               numberValue(post.Views),
+		"reposts":             numberValue(post.Reposts),
+		"URL":                 urlValue(url),
+	}, nil
+}
+
+// fullRefresh reports whether every mapped post should be rewritten. Notion
+// only takes a few requests per second, so other syncs only rewrite posts
+// whose reactions changed since the last one. The first sync of each day and
+// the one after a failure rewrite everything to pick up edited content,
+// archiving and renamed authors.
+func fullRefresh(target database.Target, now time.Time) bool {
+	if target.SyncStatus != "Synced" || !target.LastSynced.Valid {
+		return true
+	}
+	y1, m1, d1 := target.LastSynced.Time.Date()
+	y2, m2, d2 := now.Date()
+	return y1 != y2 || m1 != m2 || d1 != d2
+}
+
+func syncNotionPosts(n *client) error {
+	databaseID, err := databaseID(n, "posts")
+	if err != nil {
+		return err
+	}
+
+	pages, err := sourcePages(n)
+	if err != nil {
+		return err
+	}
+
+	posts, err := n.r.DB.GetAllPostsWithTheLatestInfoForUser(context.Background(), n.r.Target.UserID)
+	if err != nil {
+		return err
+	}
+
+	mappedPosts, err := n.r.DB.GetPostsPreviouslySynced(context.Background(), n.r.Target.ID)
+	if err != nil {
+		return fmt.Errorf("error fetching mapped posts: %w", err)
+	}
+
+	var removePosts []database.PostsOnTarget
+	mappedMap := make(map[uuid.UUID]database.PostsOnTarget, len(mappedPosts))
+	for _, m := range mappedPosts {
+		if !m.PostID.Valid {
+			removePosts = append(removePosts, m)
+		} else {
+			mappedMap[m.PostID.UUID] = m
+		}
+	}
+
+	now := time.Now()
+	refreshAll := fullRefresh(n.r.Target, now)
+	localMap := make(map[uuid.UUID]bool, len(posts))
+
+	for _, post := range posts {
+		localMap[post.ID] = true
+
+		mapped, isMapped := mappedMap[post.ID]
+		if isMapped && !refreshAll && !post.ReactionsSyncedAt.Time.After(n.r.Target.LastSynced.Time) {
+			continue
+		}
+
+		props, err := postProperties(post, pages[post.SourceID])
+		if err != nil {
+			return err
+		}
+
+		if isMapped {
+			err := n.updatePage(mapped.TargetPostID, props)
+			if err == nil {
+				continue
+			}
+			if !errors.Is(err, errNotFound) {
+				return err
+			}
+			log.Printf("Notion page for post %s is gone, recreating it", post.ID)
+			if err := n.r.DB.DeletePostOnTarget(context.Background(), mapped.ID); err != nil {
+				return fmt.Errorf("failed to delete posts_on_target mapping: %w", err)
+			}
+		}
+
+		pageID, err := n.createPage(databaseID, props)
+		if err != nil {
+			return err
+		}
+
+		if _, err := n.r.DB.AddPostToTarget(context.Background(), database.AddPostToTargetParams{
+			ID:            uuid.New(),
+			FirstSyncedAt: time.Now(),
+			PostID:        uuid.NullUUID{UUID: post.ID, Valid: true},
+			TargetID:      n.r.Target.ID,
+			TargetPostID:  pageID,
+		}); err != nil {
+			return fmt.Errorf("failed to map post: %w", err)
+		}
+	}
+
+	for id, m := range mappedMap {
+		if !localMap[id] {
+			removePosts = append(removePosts, m)
+		}
+	}
+
+	for _, m := range removePosts {
+		if err := n.archivePage(m.TargetPostID); err != nil {
+			return err
+		}
+		if err := n.r.DB.DeletePostOnTarget(context.Background(), m.ID); err != nil {
+			log.Printf("Warning: Failed to delete posts_on_target mapping: %v", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package notion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/helpers"
+	"github.com/google/uuid"
+)
+
+func sourceProperties(source database.Source) properties {
+	url, _ := helpers.ConvNetworkToURL(source.Network, source.UserName)
+
+	return properties{
+		"Name":        titleValue(source.UserName),
+		"ct_id":       textValue(source.ID.String()),
+		"network":     selectValue(source.Network),
+		"username":    textValue(source.UserName),
+		"URL":         urlValue(url),
+		"last_synced": dateValue(source.LastSynced.Time),
+	}
+}
+
+// syncNotionSources creates a page for every new source, refreshes the
+// existing ones so renamed sources show their new handle, and archives pages
+// of sources that no longer exist.
+func syncNotionSources(n *client) error {
+	databaseID, err := databaseID(n, "sources")
+	if err != nil {
+		return err
+	}
+
+	userSources, err := n.r.DB.GetUserSources(context.Background(), n.r.Target.UserID)
+	if err != nil {
+		return fmt.Errorf("error fetching user sources: %w", err)
+	}
+
+	mappedSources, err := n.r.DB.GetTargetSources(context.Background(), n.r.Target.ID)
+	if err != nil {
+		return fmt.Errorf("error fetching mapped sources: %w", err)
+	}
+
+	mappedMap := make(map[uuid.UUID]database.SourcesOnTarget, len(mappedSources))
+	for _, m := range mappedSources {
+		mappedMap[m.SourceID] = m
+	}
+
+	internMap := make(map[uuid.UUID]bool, len(userSources))
+	for _, source := range userSources {
+		internMap[source.ID] = true
+		props := sourceProperties(source)
+
+		if m, ok := mappedMap[source.ID]; ok {
+			err := n.updatePage(m.TargetSourceID, props)
+			if err == nil {
+				continue
+			}
+			if !errors.Is(err, errNotFound) {
+				return err
+			}
+			log.Printf("Notion page for source %s is gone, recreating it", source.ID)
+			if err := n.r.DB.DeleteSourceTarget(context.Background(), database.DeleteSourceTargetParams{
+				TargetID: n.r.Target.ID,
+				SourceID: source.ID,
+			}); err != nil {
+				return fmt.Errorf("failed to delete source target mapping: %w", err)
+			}
+		}
+
+		pageID, err := n.createPage(databaseID, props)
+		if err != nil {
+			return err
+		}
+
+		if _, err := n.r.DB.AddSourceToTarget(context.Background(), database.AddSourceToTargetParams{
+			ID:             uuid.New(),
+			SourceID:       source.ID,
+			TargetID:       n.r.Target.ID,
+			TargetSourceID: pageID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	for sourceID, m := range mappedMap {
+		if internMap[sourceID] {
+			continue
+		}
+		if err := n.archivePage(m.TargetSourceID); err != nil {
+			return err
+		}
+		if err := n.r.DB.DeleteSourceTarget(context.Background(), database.DeleteSourceTargetParams{
+			TargetID: n.r.Target.ID,
+			SourceID: sourceID,
+		}); err != nil {
+			return fmt.Errorf("failed to delete source target mapping: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// removeNotionSource archives a source's page and the pages of its posts.
+func removeNotionSource(n *client, source database.Source) error {
+	sourceMapping, err := n.r.DB.GetTargetSourceBySource(context.Background(), database.GetTargetSourceBySourceParams{
+		TargetID: n.r.Target.ID,
+		SourceID: source.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("error fetching source mapping: %w", err)
+	}
+
+	postsToArchive, err := n.r.DB.GetPostsBySourceAndTarget(context.Background(), database.GetPostsBySourceAndTargetParams{
+		TargetID: n.r.Target.ID,
+		SourceID: source.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, post := range postsToArchive {
+		if err := n.archivePage(post.TargetPostID); err != nil {
+			return err
+		}
+	}
+
+	if err := n.archivePage(sourceMapping.TargetSourceID); err != nil {
+		return err
+	}
+
+	if err := n.r.DB.DeletePostsOnTargetAndSource(context.Background(), database.DeletePostsOnTargetAndSourceParams{
+		TargetID: n.r.Target.ID,
+		SourceID: source.ID,
+	}); err != nil {
+		return err
+	}
+
+	return n.r.DB.DeleteSourceTarget(context.Background(), database.DeleteSourceTargetParams{
+		TargetID: n.r.Target.ID,
+		SourceID: source.ID,
+	})
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package notion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/google/uuid"
+)
+
+func sourcesStatProperties(stat database.SourcesStat, sourcePage string) properties {
+	return properties{
+		"Name":            titleValue(stat.Date.Format("2006-01-02")),
+		"ct_id":           textValue(stat.ID.String()),
+		"source":          relationValue(sourcePage),
+		"date":            dayValue(stat.Date),
+		"followers_count": numberValue(stat.FollowersCount),
+		"following_count": numberValue(stat.FollowingCount),
+		"posts_count":     numberValue(stat.PostsCount),
+		"average_likes":   numberValue(stat.AverageLikes),
+		"average_reposts": numberValue(stat.AverageReposts),
+		"average_views":   numberValue(stat.AverageViews),
+	}
+}
+
+// syncNotionSourcesStats rewrites the last two days of profile stats, which
+// may still change, and creates pages for stats not pushed yet.
+func syncNotionSourcesStats(n *client) error {
+	databaseID, err := databaseID(n, "sources_stats")
+	if err != nil {
+		return err
+	}
+
+	pages, err := sourcePages(n)
+	if err != nil {
+		return err
+	}
+
+	dateThreshold := time.Now().AddDate(0, 0, -2)
+
+	for sourceID, sourcePage := range pages {
+		syncedStats, err := n.r.DB.GetSyncedSourcesStatsForUpdate(context.Background(), database.GetSyncedSourcesStatsForUpdateParams{
+			TargetID: n.r.Target.ID,
+			SourceID: sourceID,
+			Date:     dateThreshold,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, row := range syncedStats {
+			stat := database.SourcesStat{
+				ID:             row.ID,
+				Date:           row.Date,
+				SourceID:       row.SourceID,
+				FollowersCount: row.FollowersCount,
+				FollowingCount: row.FollowingCount,
+				PostsCount:     row.PostsCount,
+				AverageLikes:   row.AverageLikes,
+				AverageReposts: row.AverageReposts,
+				AverageViews:   row.AverageViews,
+			}
+			if err := n.updatePage(row.TargetRecordID, sourcesStatProperties(stat, sourcePage)); err != nil && !errors.Is(err, errNotFound) {
+				return err
+			}
+		}
+
+		unsyncedStats, err := n.r.DB.GetUnsyncedSourcesStatsForTarget(context.Background(), database.GetUnsyncedSourcesStatsForTargetParams{
+			SourceID: sourceID,
+			TargetID: n.r.Target.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, stat := range unsyncedStats {
+			pageID, err := n.createPage(databaseID, sourcesStatProperties(stat, sourcePage))
+			if err != nil {
+				return err
+			}
+
+			if _, err := n.r.DB.AddSourcesStatToTarget(context.Background(), database.AddSourcesStatToTargetParams{
+				ID:             uuid.New(),
+				SyncedAt:       time.Now(),
+				StatID:         stat.ID,
+				TargetID:       n.r.Target.ID,
+				TargetRecordID: pageID,
+			}); err != nil {
+				return fmt.Errorf("failed to map sources stat: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
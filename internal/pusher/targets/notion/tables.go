@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package notion
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/google/uuid"
+)
+
+var emptySchema = map[string]any{}
+
+// initializeNotion creates the databases that are not mapped yet under the
+// target's parent page. The other databases relate to sources, so it is
+// created first.
+func initializeNotion(n *client) error {
+	sourcesID, err := ensureDatabase(n, schema{
+		Name:        "sources",
+		Description: "Social media sources",
+		Properties: properties{
+			"Name":        {"title": emptySchema},
+			"ct_id":       {"rich_text": emptySchema},
+			"network":     {"select": emptySchema},
+			"username":    {"rich_text": emptySchema},
+			"URL":         {"url": emptySchema},
+			"last_synced": {"date": emptySchema},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	source := map[string]any{
+		"relation": map[string]any{
+			"database_id":     sourcesID,
+			"type":            "single_property",
+			"single_property": emptySchema,
+		},
+	}
+
+	schemas := []schema{
+		{
+			Name:        "posts",
+			Description: "Posts from your social networks",
+			Properties: properties{
+				"Name":                {"title": emptySchema},
+				"ct_id":               {"rich_text": emptySchema},
+				"source":              source,
+				"created_at":          {"date": emptySchema},
+				"last_synced":         {"date": emptySchema},
+				"is_archived":         {"checkbox": emptySchema},
+				"network_internal_id": {"rich_text": emptySchema},
+				"post_type":           {"select": emptySchema},
+				"author":              {"rich_text": emptySchema},
+				"content":             {"rich_text": emptySchema},
+				"likes":               {"number": emptySchema},
+				"views":               {"number": emptySchema},
+				"reposts":             {"number": emptySchema},
+				"URL":                 {"url": emptySchema},
+			},
+		},
+		{
+			Name:        "sources_stats",
+			Description: "Daily profile statistics (followers, following, averages)",
+			Properties: properties{
+				"Name":            {"title": emptySchema},
+				"ct_id":           {"rich_text": emptySchema},
+				"source":          source,
+				"date":            {"date": emptySchema},
+				"followers_count": {"number": emptySchema},
+				"following_count": {"number": emptySchema},
+				"posts_count":     {"number": emptySchema},
+				"average_likes":   {"number": emptySchema},
+				"average_reposts": {"number": emptySchema},
+				"average_views":   {"number": emptySchema},
+			},
+		},
+		{
+			Name:        "analytics_site_stats",
+			Description: "Daily website analytics (visitors, session duration)",
+			Properties: properties{
+				"Name":                 {"title": emptySchema},
+				"ct_id":                {"rich_text": emptySchema},
+				"source":               source,
+				"date":                 {"date": emptySchema},
+				"analytics_type":       {"select": emptySchema},
+				"visitors":             {"number": emptySchema},
+				"avg_session_duration": {"number": emptySchema},
+				"impressions":          {"number": emptySchema},
+			},
+		},
+		{
+			Name:        "analytics_page_stats",
+			Description: "Daily page view analytics",
+			Properties: properties{
+				"Name":           {"title": emptySchema},
+				"ct_id":          {"rich_text": emptySchema},
+				"source":         source,
+				"date":           {"date": emptySchema},
+				"analytics_type": {"select": emptySchema},
+				"page_path":      {"rich_text": emptySchema},
+				"views":          {"number": emptySchema},
+				"impressions":    {"number": emptySchema},
+			},
+		},
+	}
+
+	for _, s := range schemas {
+		if _, err := ensureDatabase(n, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureDatabase returns the ID of the database mapped to s.Name, creating
+// the database and its table and column mappings when there is none.
+func ensureDatabase(n *client, s schema) (string, error) {
+	tm, err := n.r.DB.GetTableMappingsByTargetAndName(context.Background(), database.GetTableMappingsByTargetAndNameParams{
+		TargetID:        n.r.Target.ID,
+		TargetTableName: s.Name,
+	})
+	if err == nil {
+		return tm.TargetTableCode.String, nil
+	}
+
+	parentID, err := parentPageID(n.r.Target.DbID.String)
+	if err != nil {
+		return "", err
+	}
+
+	log.Printf("Creating Notion database %s for target %s", s.Name, n.r.Target.ID)
+	resp, err := n.createDatabase(s, parentID)
+	if err != nil {
+		return "", err
+	}
+
+	mapping, err := n.r.DB.CreateMappingForTable(context.Background(), database.CreateMappingForTableParams{
+		ID:              uuid.New(),
+		CreatedAt:       time.Now(),
+		SourceTableName: s.Name,
+		TargetTableName: s.Name,
+		TargetTableCode: sql.NullString{String: resp.ID, Valid: true},
+		TargetID:        n.r.Target.ID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("create %s table mapping: %w", s.Name, err)
+	}
+
+	for name, prop := range resp.Properties {
+		_, err := n.r.DB.CreateMappingForColumn(context.Background(), database.CreateMappingForColumnParams{
+			ID:               uuid.New(),
+			CreatedAt:        time.Now(),
+			TableMappingID:   mapping.ID,
+			SourceColumnName: name,
+			TargetColumnName: name,
+			TargetColumnCode: sql.NullString{String: prop.ID, Valid: true},
+		})
+		if err != nil {
+			return "", fmt.Errorf("create %s column mapping %s: %w", s.Name, name, err)
+		}
+	}
+
+	return resp.ID, nil
+}
+
+func databaseID(n *client, name string) (string, error) {
+	tm, err := n.r.DB.GetTableMappingsByTargetAndName(context.Background(), database.GetTableMappingsByTargetAndNameParams{
+		TargetID:        n.r.Target.ID,
+		TargetTableName: name,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s database mapping: %w", name, err)
+	}
+	return tm.TargetTableCode.String, nil
+}
+
+// sourcePages maps the target's sources to their Notion page IDs.
+func sourcePages(n *client) (map[uuid.UUID]string, error) {
+	mapped, err := n.r.DB.GetTargetSources(context.Background(), n.r.Target.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching mapped sources: %w", err)
+	}
+
+	pages := make(map[uuid.UUID]string, len(mapped))
+	for _, m := range mapped {
+		pages[m.SourceID] = m.TargetSourceID
+	}
+	return pages, nil
+}
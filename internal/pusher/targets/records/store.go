@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+// Package records pushes sources, posts and stats to table databases that
+// address rows by a numeric ID, such as Baserow and Grist. Each database only
+// implements Store; creating tables, linking rows to their source and the
+// incremental create, update and delete passes live here and keep their
+// bookkeeping in the same mapping tables the NocoDB target uses.
+package records
+
+import (
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/pusher/targets"
+)
+
+// Kind is the type of a column. Stores map it to their own field types.
+type Kind int
+
+const (
+	Text Kind = iota
+	LongText
+	Number
+	Decimal
+	Date
+	DateTime
+	Checkbox
+	URL
+	Select
+	// Link columns reference a row of the sources table.
+	Link
+)
+
+type Column struct {
+	Name    string
+	Kind    Kind
+	Choices []string
+}
+
+type Table struct {
+	Name        string
+	Description string
+	// Columns start with ct_id, the ID of the row in this app.
+	Columns []Column
+}
+
+func (t Table) Column(name string) (Column, bool) {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Column{}, false
+}
+
+// Row holds values by column name: nil, string, int64, float64, bool or
+// time.Time, and for Link columns the row ID of the source or nil.
+type Row map[string]any
+
+// Record is a row that already exists in the store.
+type Record struct {
+	ID  int64
+	Row Row
+}
+
+// Store is a database the records are pushed to. Table and column IDs are
+// whatever the store uses to address them and are kept in the mapping
+// tables; linkTable is the ID of the sources table Link columns point to.
+type Store interface {
+	// CreateTable creates the table and returns its ID and the IDs of its
+	// columns by name.
+	CreateTable(t Table, linkTable string) (string, map[string]string, error)
+	// CreateColumn adds a column introduced after the table was created.
+	CreateColumn(tableID string, c Column, linkTable string) (string, error)
+	// CreateRecords returns the IDs of the new rows in the order given.
+	CreateRecords(t Table, tableID string, rows []Row) ([]int64, error)
+	// UpdateRecords returns the IDs of records that no longer exist, e.g.
+	// because they were deleted by hand; the others are updated.
+	UpdateRecords(t Table, tableID string, records []Record) ([]int64, error)
+	// DeleteRecords deletes the rows, ignoring the ones already gone.
+	DeleteRecords(tableID string, ids []int64) error
+	// BatchSize is the most rows a single request may carry.
+	BatchSize() int
+}
+
+// Target implements targets.Target on top of a Store opened for each step.
+type Target struct {
+	Open func(r targets.Run) (Store, error)
+}
+
+type run struct {
+	targets.Run
+	store Store
+}
+
+func (t Target) open(r targets.Run) (*run, error) {
+	store, err := t.Open(r)
+	if err != nil {
+		return nil, err
+	}
+	return &run{Run: r, store: store}, nil
+}
+
+func (t Target) Initialize(r targets.Run) error {
+	s, err := t.open(r)
+	if err != nil {
+		return err
+	}
+	return s.initialize()
+}
+
+func (t Target) PushSources(r targets.Run) error {
+	s, err := t.open(r)
+	if err != nil {
+		return err
+	}
+	return s.syncSources()
+}
+
+func (t Target) PushAnalytics(r targets.Run) error {
+	s, err := t.open(r)
+	if err != nil {
+		return err
+	}
+	return s.syncAnalytics()
+}
+
+func (t Target) PushStats(r targets.Run) error {
+	s, err := t.open(r)
+	if err != nil {
+		return err
+	}
+	return s.syncSourcesStats()
+}
+
+func (t Target) PushPosts(r targets.Run) error {
+	s, err := t.open(r)
+	if err != nil {
+		return err
+	}
+	return s.syncPosts()
+}
+
+func (t Target) RemoveSource(r targets.Run, source database.Source) error {
+	s, err := t.open(r)
+	if err != nil {
+		return err
+	}
+	return s.removeSource(source)
+}
@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package records
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/helpers"
+	"github.com/google/uuid"
+)
+
+func postRow(post database.GetAllPostsWithTheLatestInfoForUserRow, sources map[uuid.UUID]int64) (Row, error) {
+	url, err := helpers.ConvPostToURL(post.Network.String, post.Author, post.NetworkInternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	return Row{
+		"ct_id":               post.ID.String(),
+		"source":              link(sources, post.SourceID),
+		"created_at":          post.CreatedAt,
+		"last_synced":         time.Now(),
+		"is_archived":         post.IsArchived,
+		"network_internal_id": post.NetworkInternalID,
+		"post_type":           post.PostType,
+		"author":              post.Author,
+		"content":             post.Content.String,
+		"likes":               nullInt(post.Likes),
+		"views":               nullInt(post.Views),
+		"reposts":             nullInt(post.Reposts),
+		"URL":                 url,
+	}, nil
+}
+
+// syncPosts creates rows for new posts, rewrites every mapped post to pick up
+// new reaction counts and edits, and deletes rows of removed posts.
+func (s *run) syncPosts() error {
+	tableID, err := s.tableID(postsTable)
+	if err != nil {
+		return err
+	}
+
+	sources, err := s.sourceRecords()
+	if err != nil {
+		return err
+	}
+
+	posts, err := s.DB.GetAllPostsWithTheLatestInfoForUser(context.Background(), s.Target.UserID)
+	if err != nil {
+		return err
+	}
+
+	mappedPosts, err := s.DB.GetPostsPreviouslySynced(context.Background(), s.Target.ID)
+	if err != nil {
+		return fmt.Errorf("error fetching mapped posts: %w", err)
+	}
+
+	var removePosts []database.PostsOnTarget
+	mappedMap := make(map[uuid.UUID]database.PostsOnTarget, len(mappedPosts))
+	for _, m := range mappedPosts {
+		if !m.PostID.Valid {
+			removePosts = append(removePosts, m)
+		} else {
+			mappedMap[m.PostID.UUID] = m
+		}
+	}
+
+	var creates []Row
+	var updates []Record
+	updated := make(map[int64]database.PostsOnTarget)
+	updatedRows := make(map[int64]Row)
+	localMap := make(map[uuid.UUID]bool, len(posts))
+	for _, post := range posts {
+		localMap[post.ID] = true
+
+		row, err := postRow(post, sources)
+		if err != nil {
+			return err
+		}
+
+		if m, ok := mappedMap[post.ID]; ok {
+			id := recordID(m.TargetPostID)
+			updates = append(updates, Record{ID: id, Row: row})
+			updated[id] = m
+			updatedRows[id] = row
+		} else {
+			creates = append(creates, row)
+		}
+	}
+
+	missing, err := s.update(postsTable, tableID, updates)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		log.Printf("%d post rows are gone, recreating them", len(missing))
+	}
+	for _, id := range missing {
+		if err := s.DB.DeletePostOnTarget(context.Background(), updated[id].ID); err != nil {
+			return fmt.Errorf("failed to delete posts_on_target mapping: %w", err)
+		}
+		creates = append(creates, updatedRows[id])
+	}
+
+	err = s.create(postsTable, tableID, creates, func(row Row, id string) error {
+		postID, _ := uuid.Parse(row["ct_id"].(string))
+		_, err := s.DB.AddPostToTarget(context.Background(), database.AddPostToTargetParams{
+			ID:            uuid.New(),
+			FirstSyncedAt: time.Now(),
+			PostID:        uuid.NullUUID{UUID: postID, Valid: true},
+			TargetID:      s.Target.ID,
+			TargetPostID:  id,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to map post: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for id, m := range mappedMap {
+		if !localMap[id] {
+			removePosts = append(removePosts, m)
+		}
+	}
+
+	ids := make([]int64, len(removePosts))
+	for i, m := range removePosts {
+		ids[i] = recordID(m.TargetPostID)
+	}
+	if err := s.delete(tableID, ids); err != nil {
+		return err
+	}
+	for _, m := range removePosts {
+		if err := s.DB.DeletePostOnTarget(context.Background(), m.ID); err != nil {
+			log.Printf("Warning: Failed to delete posts_on_target mapping: %v", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package records
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/helpers"
+	"github.com/google/uuid"
+)
+
+func sourceRow(source database.Source) Row {
+	url, _ := helpers.ConvNetworkToURL(source.Network, source.UserName)
+
+	return Row{
+		"ct_id":       source.ID.String(),
+		"network":     source.Network,
+		"username":    source.UserName,
+		"URL":         url,
+		"last_synced": nullTime(source.LastSynced),
+	}
+}
+
+// syncSources creates a row for every new source, refreshes the existing
+// ones so renamed sources show their new handle, and deletes rows of sources
+// that no longer exist.
+func (s *run) syncSources() error {
+	tableID, err := s.tableID(sourcesTable)
+	if err != nil {
+		return err
+	}
+
+	userSources, err := s.DB.GetUserSources(context.Background(), s.Target.UserID)
+	if err != nil {
+		return fmt.Errorf("error fetching user sources: %w", err)
+	}
+
+	mappedSources, err := s.DB.GetTargetSources(context.Background(), s.Target.ID)
+	if err != nil {
+		return fmt.Errorf("error fetching mapped sources: %w", err)
+	}
+
+	mappedMap := make(map[uuid.UUID]database.SourcesOnTarget, len(mappedSources))
+	for _, m := range mappedSources {
+		mappedMap[m.SourceID] = m
+	}
+
+	var creates []database.Source
+	var updates []Record
+	updated := make(map[int64]database.Source)
+	internMap := make(map[uuid.UUID]bool, len(userSources))
+	for _, source := range userSources {
+		internMap[source.ID] = true
+		if m, ok := mappedMap[source.ID]; ok {
+			id := recordID(m.TargetSourceID)
+			updates = append(updates, Record{ID: id, Row: sourceRow(source)})
+			updated[id] = source
+		} else {
+			creates = append(creates, source)
+		}
+	}
+
+	missing, err := s.update(sourcesTable, tableID, updates)
+	if err != nil {
+		return err
+	}
+	for _, id := range missing {
+		source := updated[id]
+		log.Printf("Row for source %s is gone, recreating it", source.ID)
+		if err := s.DB.DeleteSourceTarget(context.Background(), database.DeleteSourceTargetParams{
+			TargetID: s.Target.ID,
+			SourceID: source.ID,
+		}); err != nil {
+			return fmt.Errorf("failed to delete source target mapping: %w", err)
+		}
+		creates = append(creates, source)
+	}
+
+	rows := make([]Row, len(creates))
+	for i, source := range creates {
+		rows[i] = sourceRow(source)
+	}
+	err = s.create(sourcesTable, tableID, rows, func(row Row, id string) error {
+		sourceID, _ := uuid.Parse(row["ct_id"].(string))
+		_, err := s.DB.AddSourceToTarget(context.Background(), database.AddSourceToTargetParams{
+			ID:             uuid.New(),
+			SourceID:       sourceID,
+			TargetID:       s.Target.ID,
+			TargetSourceID: id,
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	var removed []int64
+	var removedSources []uuid.UUID
+	for sourceID, m := range mappedMap {
+		if internMap[sourceID] {
+			continue
+		}
+		removed = append(removed, recordID(m.TargetSourceID))
+		removedSources = append(removedSources, sourceID)
+	}
+	if err := s.delete(tableID, removed); err != nil {
+		return err
+	}
+	for _, sourceID := range removedSources {
+		if err := s.DB.DeleteSourceTarget(context.Background(), database.DeleteSourceTargetParams{
+			TargetID: s.Target.ID,
+			SourceID: sourceID,
+		}); err != nil {
+			return fmt.Errorf("failed to delete source target mapping: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// removeSource deletes a source's row and the rows of its posts.
+func (s *run) removeSource(source database.Source) error {
+	sourceMapping, err := s.DB.GetTargetSourceBySource(context.Background(), database.GetTargetSourceBySourceParams{
+		TargetID: s.Target.ID,
+		SourceID: source.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("error fetching source mapping: %w", err)
+	}
+
+	postsTableID, err := s.tableID(postsTable)
+	if err != nil {
+		return err
+	}
+	sourcesTableID, err := s.tableID(sourcesTable)
+	if err != nil {
+		return err
+	}
+
+	postsToDelete, err := s.DB.GetPostsBySourceAndTarget(context.Background(), database.GetPostsBySourceAndTargetParams{
+		TargetID: s.Target.ID,
+		SourceID: source.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	ids := make([]int64, 0, len(postsToDelete))
+	for _, post := range postsToDelete {
+		ids = append(ids, recordID(post.TargetPostID))
+	}
+	if err := s.delete(postsTableID, ids); err != nil {
+		return err
+	}
+
+	if err := s.delete(sourcesTableID, []int64{recordID(sourceMapping.TargetSourceID)}); err != nil {
+		return err
+	}
+
+	if err := s.DB.DeletePostsOnTargetAndSource(context.Background(), database.DeletePostsOnTargetAndSourceParams{
+		TargetID: s.Target.ID,
+		SourceID: source.ID,
+	}); err != nil {
+		return err
+	}
+
+	return s.DB.DeleteSourceTarget(context.Background(), database.DeleteSourceTargetParams{
+		TargetID: s.Target.ID,
+		SourceID: source.ID,
+	})
+}
@@ -0,0 +1,331 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package records
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/google/uuid"
+)
+
+func sourcesStatRow(stat database.SourcesStat, sources map[uuid.UUID]int64) Row {
+	return Row{
+		"ct_id":           stat.ID.String(),
+		"source":          link(sources, stat.SourceID),
+		"date":            stat.Date,
+		"followers_count": nullInt(stat.FollowersCount),
+		"following_count": nullInt(stat.FollowingCount),
+		"posts_count":     nullInt(stat.PostsCount),
+		"average_likes":   nullFloat(stat.AverageLikes),
+		"average_reposts": nullFloat(stat.AverageReposts),
+		"average_views":   nullFloat(stat.AverageViews),
+	}
+}
+
+// syncSourcesStats rewrites the last two days of profile stats, which may
+// still change, and creates rows for stats not pushed yet. Rows deleted by
+// hand are not recreated.
+func (s *run) syncSourcesStats() error {
+	tableID, err := s.tableID(sourcesStatsTable)
+	if err != nil {
+		return err
+	}
+
+	sources, err := s.sourceRecords()
+	if err != nil {
+		return err
+	}
+
+	dateThreshold := time.Now().AddDate(0, 0, -2)
+
+	for sourceID := range sources {
+		syncedStats, err := s.DB.GetSyncedSourcesStatsForUpdate(context.Background(), database.GetSyncedSourcesStatsForUpdateParams{
+			TargetID: s.Target.ID,
+			SourceID: sourceID,
+			Date:     dateThreshold,
+		})
+		if err != nil {
+			return err
+		}
+
+		updates := make([]Record, len(syncedStats))
+		for i, row := range syncedStats {
+			stat := database.SourcesStat{
+				ID:             row.ID,
+				Date:           row.Date,
+				SourceID:       row.SourceID,
+				FollowersCount: row.FollowersCount,
+				FollowingCount: row.FollowingCount,
+				PostsCount:     row.PostsCount,
+				AverageLikes:   row.AverageLikes,
+				AverageReposts: row.AverageReposts,
+				AverageViews:   row.AverageViews,
+			}
+			updates[i] = Record{ID: recordID(row.TargetRecordID), Row: sourcesStatRow(stat, sources)}
+		}
+		if _, err := s.update(sourcesStatsTable, tableID, updates); err != nil {
+			return err
+		}
+
+		unsyncedStats, err := s.DB.GetUnsyncedSourcesStatsForTarget(context.Background(), database.GetUnsyncedSourcesStatsForTargetParams{
+			SourceID: sourceID,
+			TargetID: s.Target.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		rows := make([]Row, len(unsyncedStats))
+		for i, stat := range unsyncedStats {
+			rows[i] = sourcesStatRow(stat, sources)
+		}
+		err = s.create(sourcesStatsTable, tableID, rows, func(row Row, id string) error {
+			statID, _ := uuid.Parse(row["ct_id"].(string))
+			if _, err := s.DB.AddSourcesStatToTarget(context.Background(), database.AddSourcesStatToTargetParams{
+				ID:             uuid.New(),
+				SyncedAt:       time.Now(),
+				StatID:         statID,
+				TargetID:       s.Target.ID,
+				TargetRecordID: id,
+			}); err != nil {
+				return fmt.Errorf("failed to map sources stat: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AnalyticsUpdateDays is how far back website stats are rewritten; the
+// analytics providers revise recent days for about a week.
+const AnalyticsUpdateDays = 9
+
+func siteStatRow(stat database.AnalyticsSiteStat, sources map[uuid.UUID]int64) Row {
+	return Row{
+		"ct_id":                stat.ID.String(),
+		"source":               link(sources, stat.SourceID),
+		"date":                 stat.Date,
+		"analytics_type":       stat.AnalyticsType,
+		"visitors":             stat.Visitors,
+		"avg_session_duration": stat.AvgSessionDuration,
+		"impressions":          nullInt(stat.Impressions),
+	}
+}
+
+func pageStatRow(stat database.AnalyticsPageStat, sources map[uuid.UUID]int64) Row {
+	return Row{
+		"ct_id":          stat.ID.String(),
+		"source":         link(sources, stat.SourceID),
+		"date":           stat.Date,
+		"analytics_type": stat.AnalyticsType,
+		"page_path":      stat.UrlPath,
+		"views":          stat.Views,
+		"impressions":    nullInt(stat.Impressions),
+	}
+}
+
+func (s *run) syncAnalytics() error {
+	if err := s.syncSiteStats(); err != nil {
+		return fmt.Errorf("failed to sync site stats: %w", err)
+	}
+	if err := s.syncPageStats(); err != nil {
+		return fmt.Errorf("failed to sync page stats: %w", err)
+	}
+	return nil
+}
+
+func (s *run) syncSiteStats() error {
+	tableID, err := s.tableID(siteStatsTable)
+	if err != nil {
+		return err
+	}
+
+	sources, err := s.sourceRecords()
+	if err != nil {
+		return err
+	}
+
+	dateThreshold := time.Now().AddDate(0, 0, -AnalyticsUpdateDays)
+
+	for sourceID := range sources {
+		syncedStats, err := s.DB.GetSyncedSiteStatsForUpdate(context.Background(), database.GetSyncedSiteStatsForUpdateParams{
+			TargetID: s.Target.ID,
+			SourceID: sourceID,
+			Date:     dateThreshold,
+		})
+		if err != nil {
+			return err
+		}
+
+		updates := make([]Record, len(syncedStats))
+		for i, row := range syncedStats {
+			stat := database.AnalyticsSiteStat{
+				ID:                 row.ID,
+				Date:               row.Date,
+				Visitors:           row.Visitors,
+				AvgSessionDuration: row.AvgSessionDuration,
+				SourceID:           row.SourceID,
+				AnalyticsType:      row.AnalyticsType,
+				Impressions:        row.Impressions,
+			}
+			updates[i] = Record{ID: recordID(row.TargetRecordID), Row: siteStatRow(stat, sources)}
+		}
+		if _, err := s.update(siteStatsTable, tableID, updates); err != nil {
+			return err
+		}
+
+		unsyncedStats, err := s.DB.GetUnsyncedSiteStatsForTarget(context.Background(), database.GetUnsyncedSiteStatsForTargetParams{
+			TargetID: s.Target.ID,
+			SourceID: sourceID,
+		})
+		if err != nil {
+			return err
+		}
+
+		rows := make([]Row, len(unsyncedStats))
+		for i, stat := range unsyncedStats {
+			rows[i] = siteStatRow(stat, sources)
+		}
+		err = s.create(siteStatsTable, tableID, rows, func(row Row, id string) error {
+			statID, _ := uuid.Parse(row["ct_id"].(string))
+			if _, err := s.DB.AddAnalyticsSiteStatToTarget(context.Background(), database.AddAnalyticsSiteStatToTargetParams{
+				ID:             uuid.New(),
+				SyncedAt:       time.Now(),
+				StatID:         uuid.NullUUID{UUID: statID, Valid: true},
+				TargetID:       s.Target.ID,
+				TargetRecordID: id,
+			}); err != nil {
+				return fmt.Errorf("failed to map site stat: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Stats replaced by a re-fetch leave mappings without a stat behind.
+	mappings, err := s.DB.GetSiteStatsOnTarget(context.Background(), s.Target.ID)
+	if err != nil {
+		return err
+	}
+	var stale []database.AnalyticsSiteStatsOnTarget
+	var ids []int64
+	for _, m := range mappings {
+		if !m.StatID.Valid {
+			stale = append(stale, m)
+			ids = append(ids, recordID(m.TargetRecordID))
+		}
+	}
+	if err := s.delete(tableID, ids); err != nil {
+		return err
+	}
+	for _, m := range stale {
+		if err := s.DB.DeleteAnalyticsSiteStatOnTarget(context.Background(), m.ID); err != nil {
+			log.Printf("Warning: failed to delete site stat mapping %s: %v", m.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *run) syncPageStats() error {
+	tableID, err := s.tableID(pageStatsTable)
+	if err != nil {
+		return err
+	}
+
+	sources, err := s.sourceRecords()
+	if err != nil {
+		return err
+	}
+
+	dateThreshold := time.Now().AddDate(0, 0, -AnalyticsUpdateDays)
+
+	for sourceID := range sources {
+		syncedStats, err := s.DB.GetSyncedPageStatsForUpdate(context.Background(), database.GetSyncedPageStatsForUpdateParams{
+			TargetID: s.Target.ID,
+			SourceID: sourceID,
+			Date:     dateThreshold,
+		})
+		if err != nil {
+			return err
+		}
+
+		updates := make([]Record, len(syncedStats))
+		for i, row := range syncedStats {
+			stat := database.AnalyticsPageStat{
+				ID:            row.ID,
+				Date:          row.Date,
+				UrlPath:       row.UrlPath,
+				Views:         row.Views,
+				SourceID:      row.SourceID,
+				AnalyticsType: row.AnalyticsType,
+				Impressions:   row.Impressions,
+			}
+			updates[i] = Record{ID: recordID(row.TargetRecordID), Row: pageStatRow(stat, sources)}
+		}
+		if _, err := s.update(pageStatsTable, tableID, updates); err != nil {
+			return err
+		}
+
+		unsyncedStats, err := s.DB.GetUnsyncedPageStatsForTarget(context.Background(), database.GetUnsyncedPageStatsForTargetParams{
+			TargetID: s.Target.ID,
+			SourceID: sourceID,
+		})
+		if err != nil {
+			return err
+		}
+
+		rows := make([]Row, len(unsyncedStats))
+		for i, stat := range unsyncedStats {
+			rows[i] = pageStatRow(stat, sources)
+		}
+		err = s.create(pageStatsTable, tableID, rows, func(row Row, id string) error {
+			statID, _ := uuid.Parse(row["ct_id"].(string))
+			if _, err := s.DB.AddAnalyticsPageStatToTarget(context.Background(), database.AddAnalyticsPageStatToTargetParams{
+				ID:             uuid.New(),
+				SyncedAt:       time.Now(),
+				StatID:         uuid.NullUUID{UUID: statID, Valid: true},
+				TargetID:       s.Target.ID,
+				TargetRecordID: id,
+			}); err != nil {
+				return fmt.Errorf("failed to map page stat: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	mappings, err := s.DB.GetPageStatsOnTarget(context.Background(), s.Target.ID)
+	if err != nil {
+		return err
+	}
+	var stale []database.AnalyticsPageStatsOnTarget
+	var ids []int64
+	for _, m := range mappings {
+		if !m.StatID.Valid {
+			stale = append(stale, m)
+			ids = append(ids, recordID(m.TargetRecordID))
+		}
+	}
+	if err := s.delete(tableID, ids); err != nil {
+		return err
+	}
+	for _, m := range stale {
+		if err := s.DB.DeleteAnalyticsPageStatOnTarget(context.Background(), m.ID); err != nil {
+			log.Printf("Warning: failed to delete page stat mapping %s: %v", m.ID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,297 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package records
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/helpers"
+	"github.com/google/uuid"
+)
+
+var (
+	sourcesTable = Table{
+		Name:        "sources",
+		Description: "Social media sources",
+		Columns: []Column{
+			{Name: "ct_id", Kind: Text},
+			{Name: "network", Kind: Select, Choices: networkChoices()},
+			{Name: "username", Kind: Text},
+			{Name: "URL", Kind: URL},
+			{Name: "last_synced", Kind: DateTime},
+		},
+	}
+	postsTable = Table{
+		Name:        "posts",
+		Description: "Posts from your social networks",
+		Columns: []Column{
+			{Name: "ct_id", Kind: Text},
+			{Name: "source", Kind: Link},
+			{Name: "created_at", Kind: DateTime},
+			{Name: "last_synced", Kind: DateTime},
+			{Name: "is_archived", Kind: Checkbox},
+			{Name: "network_internal_id", Kind: Text},
+			{Name: "post_type", Kind: Text},
+			{Name: "author", Kind: Text},
+			{Name: "content", Kind: LongText},
+			{Name: "likes", Kind: Number},
+			{Name: "views", Kind: Number},
+			{Name: "reposts", Kind: Number},
+			{Name: "URL", Kind: URL},
+		},
+	}
+	sourcesStatsTable = Table{
+		Name:        "sources_stats",
+		Description: "Daily profile statistics (followers, following, averages)",
+		Columns: []Column{
+			{Name: "ct_id", Kind: Text},
+			{Name: "source", Kind: Link},
+			{Name: "date", Kind: Date},
+			{Name: "followers_count", Kind: Number},
+			{Name: "following_count", Kind: Number},
+			{Name: "posts_count", Kind: Number},
+			{Name: "average_likes", Kind: Decimal},
+			{Name: "average_reposts", Kind: Decimal},
+			{Name: "average_views", Kind: Decimal},
+		},
+	}
+	siteStatsTable = Table{
+		Name:        "analytics_site_stats",
+		Description: "Daily website analytics (visitors, session duration)",
+		Columns: []Column{
+			{Name: "ct_id", Kind: Text},
+			{Name: "source", Kind: Link},
+			{Name: "date", Kind: Date},
+			{Name: "analytics_type", Kind: Text},
+			{Name: "visitors", Kind: Number},
+			{Name: "avg_session_duration", Kind: Decimal},
+			{Name: "impressions", Kind: Number},
+		},
+	}
+	pageStatsTable = Table{
+		Name:        "analytics_page_stats",
+		Description: "Daily page view analytics",
+		Columns: []Column{
+			{Name: "ct_id", Kind: Text},
+			{Name: "source", Kind: Link},
+			{Name: "date", Kind: Date},
+			{Name: "analytics_type", Kind: Text},
+			{Name: "page_path", Kind: Text},
+			{Name: "views", Kind: Number},
+			{Name: "impressions", Kind: Number},
+		},
+	}
+
+	// Tables lists every table pushed. The sources table comes first since
+	// the others link to it.
+	Tables = []Table{sourcesTable, postsTable, sourcesStatsTable, siteStatsTable, pageStatsTable}
+)
+
+func networkChoices() []string {
+	choices := make([]string, len(helpers.AvailableSources))
+	for i, source := range helpers.AvailableSources {
+		choices[i] = source.Name
+	}
+	return choices
+}
+
+// initialize creates the tables that are not mapped yet and adds columns
+// missing from tables created by an older version.
+func (s *run) initialize() error {
+	var linkTable string
+	for _, t := range Tables {
+		tableID, err := s.ensureTable(t, linkTable)
+		if err != nil {
+			return err
+		}
+		if t.Name == sourcesTable.Name {
+			linkTable = tableID
+		}
+	}
+	return nil
+}
+
+func (s *run) ensureTable(t Table, linkTable string) (string, error) {
+	tm, err := s.DB.GetTableMappingsByTargetAndName(context.Background(), database.GetTableMappingsByTargetAndNameParams{
+		TargetID:        s.Target.ID,
+		TargetTableName: t.Name,
+	})
+	if err != nil {
+		log.Printf("Creating table %s for target %s", t.Name, s.Target.ID)
+		tableID, columns, err := s.store.CreateTable(t, linkTable)
+		if err != nil {
+			return "", fmt.Errorf("create %s table: %w", t.Name, err)
+		}
+
+		tm, err = s.DB.CreateMappingForTable(context.Background(), database.CreateMappingForTableParams{
+			ID:              uuid.New(),
+			CreatedAt:       time.Now(),
+			SourceTableName: t.Name,
+			TargetTableName: t.Name,
+			TargetTableCode: sql.NullString{String: tableID, Valid: true},
+			TargetID:        s.Target.ID,
+		})
+		if err != nil {
+			return "", fmt.Errorf("create %s table mapping: %w", t.Name, err)
+		}
+
+		for _, c := range t.Columns {
+			if err := s.mapColumn(tm, c.Name, columns[c.Name]); err != nil {
+				return "", err
+			}
+		}
+		return tableID, nil
+	}
+
+	mapped, err := s.DB.GetColumnMappingsByTable(context.Background(), tm.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s column mappings: %w", t.Name, err)
+	}
+	known := make(map[string]bool, len(mapped))
+	for _, m := range mapped {
+		known[m.TargetColumnName] = true
+	}
+
+	for _, c := range t.Columns {
+		if known[c.Name] {
+			continue
+		}
+		log.Printf("Adding column %s to table %s for target %s", c.Name, t.Name, s.Target.ID)
+		columnID, err := s.store.CreateColumn(tm.TargetTableCode.String, c, linkTable)
+		if err != nil {
+			return "", fmt.Errorf("create %s column %s: %w", t.Name, c.Name, err)
+		}
+		if err := s.mapColumn(tm, c.Name, columnID); err != nil {
+			return "", err
+		}
+	}
+
+	return tm.TargetTableCode.String, nil
+}
+
+func (s *run) mapColumn(tm database.TableMapping, name, columnID string) error {
+	_, err := s.DB.CreateMappingForColumn(context.Background(), database.CreateMappingForColumnParams{
+		ID:               uuid.New(),
+		CreatedAt:        time.Now(),
+		TableMappingID:   tm.ID,
+		SourceColumnName: name,
+		TargetColumnName: name,
+		TargetColumnCode: sql.NullString{String: columnID, Valid: columnID != ""},
+	})
+	if err != nil {
+		return fmt.Errorf("create %s column mapping %s: %w", tm.TargetTableName, name, err)
+	}
+	return nil
+}
+
+func (s *run) tableID(t Table) (string, error) {
+	tm, err := s.DB.GetTableMappingsByTargetAndName(context.Background(), database.GetTableMappingsByTargetAndNameParams{
+		TargetID:        s.Target.ID,
+		TargetTableName: t.Name,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s table mapping: %w", t.Name, err)
+	}
+	return tm.TargetTableCode.String, nil
+}
+
+// sourceRecords maps the target's sources to their row IDs.
+func (s *run) sourceRecords() (map[uuid.UUID]int64, error) {
+	mapped, err := s.DB.GetTargetSources(context.Background(), s.Target.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching mapped sources: %w", err)
+	}
+
+	ids := make(map[uuid.UUID]int64, len(mapped))
+	for _, m := range mapped {
+		if id, err := strconv.ParseInt(m.TargetSourceID, 10, 64); err == nil {
+			ids[m.SourceID] = id
+		}
+	}
+	return ids, nil
+}
+
+// create adds rows in batches and hands each new row ID to mapped, which
+// records the mapping.
+func (s *run) create(t Table, tableID string, rows []Row, mapped func(row Row, id string) error) error {
+	size := s.store.BatchSize()
+	for start := 0; start < len(rows); start += size {
+		batch := rows[start:min(start+size, len(rows))]
+		ids, err := s.store.CreateRecords(t, tableID, batch)
+		if err != nil {
+			return err
+		}
+		if len(ids) != len(batch) {
+			return fmt.Errorf("created %d %s records, got %d IDs back", len(batch), t.Name, len(ids))
+		}
+		for i, row := range batch {
+			if err := mapped(row, strconv.FormatInt(ids[i], 10)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// update rewrites records in batches and returns the IDs of those that no
+// longer exist.
+func (s *run) update(t Table, tableID string, records []Record) ([]int64, error) {
+	var missing []int64
+	size := s.store.BatchSize()
+	for start := 0; start < len(records); start += size {
+		gone, err := s.store.UpdateRecords(t, tableID, records[start:min(start+size, len(records))])
+		if err != nil {
+			return nil, err
+		}
+		missing = append(missing, gone...)
+	}
+	return missing, nil
+}
+
+func (s *run) delete(tableID string, ids []int64) error {
+	size := s.store.BatchSize()
+	for start := 0; start < len(ids); start += size {
+		if err := s.store.DeleteRecords(tableID, ids[start:min(start+size, len(ids))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func recordID(id string) int64 {
+	n, _ := strconv.ParseInt(id, 10, 64)
+	return n
+}
+
+func link(ids map[uuid.UUID]int64, sourceID uuid.UUID) any {
+	if id, ok := ids[sourceID]; ok {
+		return id
+	}
+	return nil
+}
+
+func nullInt(n sql.NullInt64) any {
+	if !n.Valid {
+		return nil
+	}
+	return n.Int64
+}
+
+func nullFloat(n sql.NullFloat64) any {
+	if !n.Valid {
+		return nil
+	}
+	return n.Float64
+}
+
+func nullTime(t sql.NullTime) any {
+	if !t.Valid {
+		return nil
+	}
+	return t.Time
+}
@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package sheets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/authhelp"
+	"github.com/fluffyriot/rpsync/internal/pusher/pacer"
+	"github.com/fluffyriot/rpsync/internal/pusher/targets"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	sheetsapi "google.golang.org/api/sheets/v4"
+)
+
+const (
+	// requestInterval keeps a sync under the Sheets API quota of 60 read and
+	// 60 write requests per minute per user.
+	requestInterval = time.Second
+	maxRetries      = 5
+)
+
+var spreadsheetIDPattern = regexp.MustCompile(`/spreadsheets/d/([a-zA-Z0-9_-]+)`)
+
+type client struct {
+	r             targets.Run
+	svc           *sheetsapi.Service
+	spreadsheetID string
+	email         string
+	pace          *pacer.Pacer
+}
+
+var pacers = pacer.NewGroup(requestInterval)
+
+func newClient(r targets.Run) (*client, error) {
+	ctx := context.Background()
+
+	token, _, _, err := authhelp.GetTargetToken(ctx, r.DB, r.EncryptionKey, r.Target.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Google Sheets token: %w", err)
+	}
+
+	jwtCfg, err := google.JWTConfigFromJSON([]byte(token), sheetsapi.SpreadsheetsScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse service account credentials: %w", err)
+	}
+
+	svc, err := sheetsapi.NewService(ctx, option.WithTokenSource(jwtCfg.TokenSource(ctx)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sheets client: %w", err)
+	}
+
+	return &client{
+		r:             r,
+		svc:           svc,
+		spreadsheetID: spreadsheetID(r.Target.DbID.String),
+		email:         jwtCfg.Email,
+		pace:          pacers.For(r.Target.ID),
+	}, nil
+}
+
+// spreadsheetID accepts a bare spreadsheet ID or a spreadsheet URL as copied
+// from the browser.
+func spreadsheetID(dbID string) string {
+	if m := spreadsheetIDPattern.FindStringSubmatch(dbID); m != nil {
+		return m[1]
+	}
+	return dbID
+}
+
+// call runs one API request, spacing requests out and retrying the ones
+// rejected for quota or by a transient server error.
+func (s *client) call(name string, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		s.pace.Wait()
+
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		var apiErr *googleapi.Error
+		if !errors.As(err, &apiErr) {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		switch {
+		case apiErr.Code == http.StatusForbidden || apiErr.Code == http.StatusNotFound:
+			return fmt.Errorf("%s: %w (is the spreadsheet shared with %s as an editor?)", name, err, s.email)
+		case (apiErr.Code == http.StatusTooManyRequests || apiErr.Code >= 500) && attempt < maxRetries:
+			wait := time.Duration(1<<attempt) * 5 * time.Second
+			log.Printf("Google Sheets returned %d for %s, retrying in %v", apiErr.Code, name, wait)
+			time.Sleep(wait)
+		default:
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+}
+
+// sheetProperties returns the properties of every tab by title.
+func (s *client) sheetProperties() (map[string]*sheetsapi.SheetProperties, error) {
+	var resp *sheetsapi.Spreadsheet
+	err := s.call("get spreadsheet", func() (err error) {
+		resp, err = s.svc.Spreadsheets.Get(s.spreadsheetID).Fields("sheets.properties").Do()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	props := make(map[string]*sheetsapi.SheetProperties, len(resp.Sheets))
+	for _, sheet := range resp.Sheets {
+		props[sheet.Properties.Title] = sheet.Properties
+	}
+	return props, nil
+}
+
+func (s *client) batchUpdate(requests []*sheetsapi.Request) error {
+	if len(requests) == 0 {
+		return nil
+	}
+	return s.call("update spreadsheet", func() error {
+		_, err := s.svc.Spreadsheets.BatchUpdate(s.spreadsheetID, &sheetsapi.BatchUpdateSpreadsheetRequest{
+			Requests: requests,
+		}).Do()
+		return err
+	})
+}
+
+// readValues returns the rows of each range with cells as stored rather than
+// as displayed, so they compare equal to the values written.
+func (s *client) readValues(ranges ...string) ([][][]any, error) {
+	var resp *sheetsapi.BatchGetValuesResponse
+	err := s.call("read values", func() (err error) {
+		resp, err = s.svc.Spreadsheets.Values.BatchGet(s.spreadsheetID).
+			Ranges(ranges...).
+			ValueRenderOption("UNFORMATTED_VALUE").
+			Do()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([][][]any, len(ranges))
+	for i, vr := range resp.ValueRanges {
+		if i < len(values) {
+			values[i] = vr.Values
+		}
+	}
+	return values, nil
+}
+
+// writeValues writes the ranges as raw values, so post text starting with
+// "=" is never evaluated as a formula.
+func (s *client) writeValues(data []*sheetsapi.ValueRange) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return s.call("write values", func() error {
+		_, err := s.svc.Spreadsheets.Values.BatchUpdate(s.spreadsheetID, &sheetsapi.BatchUpdateValuesRequest{
+			ValueInputOption: "RAW",
+			Data:             data,
+		}).Do()
+		return err
+	})
+}
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package sheets
+
+import (
+	"fmt"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/helpers"
+	"github.com/fluffyriot/rpsync/internal/pusher/targets"
+)
+
+func init() {
+	targets.Register(targets.Provider{
+		Network: helpers.TargetNetwork{
+			Name:  "Google Sheets",
+			Color: "#0f9d58",
+			Fields: []helpers.TargetField{
+				{Name: "db_id", Label: "Spreadsheet URL or Id", Placeholder: "https://docs.google.com/spreadsheets/d/1AbCdEfGhIjKlMnOpQrStUvWxYz/edit"},
+				{Name: "api_token", Label: "Service Account JSON Key", Placeholder: `{"type": "service_account", ...}`, Multiline: true},
+			},
+		},
+		Target: Sheets{},
+	})
+}
+
+// Sheets keeps one tab each for sources, posts, source stats and website
+// stats in a spreadsheet shared with a service account. The sheet itself is
+// the mapping: rows carry their ID in the ct_id column and are updated in
+// place on every sync.
+type Sheets struct{}
+
+func (Sheets) Initialize(r targets.Run) error {
+	s, err := newClient(r)
+	if err != nil {
+		return err
+	}
+	return initializeSheets(s)
+}
+
+func (Sheets) PushSources(r targets.Run) error {
+	return push(r, sourcesTab, sourceRows)
+}
+
+func (Sheets) PushAnalytics(r targets.Run) error {
+	if err := push(r, siteStatsTab, siteStatRows); err != nil {
+		return fmt.Errorf("failed to sync site stats: %w", err)
+	}
+	if err := push(r, pageStatsTab, pageStatRows); err != nil {
+		return fmt.Errorf("failed to sync page stats: %w", err)
+	}
+	return nil
+}
+
+func (Sheets) PushStats(r targets.Run) error {
+	return push(r, sourcesStatsTab, sourcesStatRows)
+}
+
+func (Sheets) PushPosts(r targets.Run) error {
+	return push(r, postsTab, postRows)
+}
+
+func (Sheets) RemoveSource(r targets.Run, source database.Source) error {
+	s, err := newClient(r)
+	if err != nil {
+		return err
+	}
+	return removeSourceRows(s, source.ID.String())
+}
+
+func push(r targets.Run, t tab, rows func(s *client) ([][]any, error)) error {
+	s, err := newClient(r)
+	if err != nil {
+		return err
+	}
+	values, err := rows(s)
+	if err != nil {
+		return err
+	}
+	return syncTab(s, t, values)
+}
@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package sheets
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/helpers"
+)
+
+// maxCellLength is the most characters a Google Sheets cell holds.
+const maxCellLength = 50000
+
+func sourceRows(s *client) ([][]any, error) {
+	sources, err := s.r.DB.GetUserSources(context.Background(), s.r.Target.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([][]any, 0, len(sources))
+	for _, source := range sources {
+		url, _ := helpers.ConvNetworkToURL(source.Network, source.UserName)
+		rows = append(rows, []any{
+			source.ID.String(),
+			source.Network,
+			source.UserName,
+			url,
+			source.IsActive,
+			nullTime(source.LastSynced),
+		})
+	}
+	return rows, nil
+}
+
+func postRows(s *client) ([][]any, error) {
+	posts, err := s.r.DB.GetAllPostsWithTheLatestInfoForUser(context.Background(), s.r.Target.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([][]any, 0, len(posts))
+	for _, post := range posts {
+		url, err := helpers.ConvPostToURL(post.Network.String, post.Author, post.NetworkInternalID)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, []any{
+			post.ID.String(),
+			post.SourceID.String(),
+			post.Network.String,
+			post.Author,
+			post.PostType,
+			post.CreatedAt.Format(time.RFC3339),
+			post.IsArchived,
+			post.NetworkInternalID,
+			truncate(post.Content.String, maxCellLength),
+			nullInt(post.Likes),
+			nullInt(post.Views),
+			nullInt(post.Reposts),
+			url,
+		})
+	}
+	return rows, nil
+}
+
+func sourcesStatRows(s *client) ([][]any, error) {
+	stats, err := s.r.DB.GetAllSourcesStatsForUser(context.Background(), s.r.Target.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([][]any, 0, len(stats))
+	for _, stat := range stats {
+		rows = append(rows, []any{
+			stat.ID.String(),
+			stat.SourceID.String(),
+			stat.Date.Format("2006-01-02"),
+			nullInt(stat.FollowersCount),
+			nullInt(stat.FollowingCount),
+			nullInt(stat.PostsCount),
+			nullFloat(stat.AverageLikes),
+			nullFloat(stat.AverageReposts),
+			nullFloat(stat.AverageViews),
+		})
+	}
+	return rows, nil
+}
+
+func siteStatRows(s *client) ([][]any, error) {
+	stats, err := s.r.DB.GetAllAnalyticsSiteStatsForUser(context.Background(), s.r.Target.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([][]any, 0, len(stats))
+	for _, stat := range stats {
+		rows = append(rows, []any{
+			stat.ID.String(),
+			stat.SourceID.String(),
+			stat.Date.Format("2006-01-02"),
+			stat.AnalyticsType,
+			stat.Visitors,
+			stat.AvgSessionDuration,
+			nullInt(stat.Impressions),
+		})
+	}
+	return rows, nil
+}
+
+func pageStatRows(s *client) ([][]any, error) {
+	stats, err := s.r.DB.GetAllAnalyticsPageStatsForUser(context.Background(), s.r.Target.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([][]any, 0, len(stats))
+	for _, stat := range stats {
+		rows = append(rows, []any{
+			stat.ID.String(),
+			stat.SourceID.String(),
+			stat.Date.Format("2006-01-02"),
+			stat.AnalyticsType,
+			stat.UrlPath,
+			stat.Views,
+			nullInt(stat.Impressions),
+		})
+	}
+	return rows, nil
+}
+
+func nullInt(n sql.NullInt64) any {
+	if !n.Valid {
+		return ""
+	}
+	return n.Int64
+}
+
+func nullFloat(n sql.NullFloat64) any {
+	if !n.Valid {
+		return ""
+	}
+	return n.Float64
+}
+
+func nullTime(t sql.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.Format(time.RFC3339)
+}
+
+func truncate(s string, length int) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+	return string(runes[:length-1]) + "…"
+}
@@ -0,0 +1,324 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package sheets
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	sheetsapi "google.golang.org/api/sheets/v4"
+)
+
+// batchRows caps the rows sent in one write so a request stays well under
+// the API's payload limit.
+const batchRows = 1000
+
+// tab is one sheet of the spreadsheet. The first column is always ct_id, the
+// ID of the row in this app, which is how rows are found again.
+type tab struct {
+	Name    string
+	Columns []string
+}
+
+var (
+	sourcesTab = tab{
+		Name:    "sources",
+		Columns: []string{"ct_id", "network", "username", "url", "is_active", "last_synced"},
+	}
+	postsTab = tab{
+		Name: "posts",
+		Columns: []string{"ct_id", "source_id", "network", "author", "post_type", "created_at", "is_archived",
+			"network_internal_id", "content", "likes", "views", "reposts", "url"},
+	}
+	sourcesStatsTab = tab{
+		Name: "sources_stats",
+		Columns: []string{"ct_id", "source_id", "date", "followers_count", "following_count", "posts_count",
+			"average_likes", "average_reposts", "average_views"},
+	}
+	siteStatsTab = tab{
+		Name:    "analytics_site_stats",
+		Columns: []string{"ct_id", "source_id", "date", "analytics_type", "visitors", "avg_session_duration", "impressions"},
+	}
+	pageStatsTab = tab{
+		Name:    "analytics_page_stats",
+		Columns: []string{"ct_id", "source_id", "date", "analytics_type", "page_path", "views", "impressions"},
+	}
+
+	allTabs = []tab{sourcesTab, postsTab, sourcesStatsTab, siteStatsTab, pageStatsTab}
+)
+
+// rowsRange is the A1 range of rows first to last, counted from 1.
+func (t tab) rowsRange(first, last int64) string {
+	return fmt.Sprintf("'%s'!A%d:%s%d", t.Name, first, columnName(len(t.Columns)), last)
+}
+
+// dataRange is every row below the header.
+func (t tab) dataRange() string {
+	return fmt.Sprintf("'%s'!A2:%s", t.Name, columnName(len(t.Columns)))
+}
+
+func (t tab) column(name string) int {
+	for i, c := range t.Columns {
+		if c == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// columnName converts a 1-based column number to its letters, e.g. 28 to AB.
+func columnName(n int) string {
+	name := ""
+	for n > 0 {
+		n--
+		name = string(rune('A'+n%26)) + name
+		n /= 26
+	}
+	return name
+}
+
+// initializeSheets adds the tabs that are missing and (re)writes every
+// header row, so columns added in later versions show up.
+func initializeSheets(s *client) error {
+	props, err := s.sheetProperties()
+	if err != nil {
+		return err
+	}
+
+	var requests []*sheetsapi.Request
+	for _, t := range allTabs {
+		if _, ok := props[t.Name]; ok {
+			continue
+		}
+		log.Printf("Creating Google Sheets tab %s for target %s", t.Name, s.r.Target.ID)
+		requests = append(requests, &sheetsapi.Request{
+			AddSheet: &sheetsapi.AddSheetRequest{
+				Properties: &sheetsapi.SheetProperties{
+					Title: t.Name,
+					GridProperties: &sheetsapi.GridProperties{
+						FrozenRowCount: 1,
+					},
+				},
+			},
+		})
+	}
+	if err := s.batchUpdate(requests); err != nil {
+		return err
+	}
+
+	var headers []*sheetsapi.ValueRange
+	for _, t := range allTabs {
+		header := make([]any, len(t.Columns))
+		for i, c := range t.Columns {
+			header[i] = c
+		}
+		headers = append(headers, &sheetsapi.ValueRange{
+			Range:  t.rowsRange(1, 1),
+			Values: [][]any{header},
+		})
+	}
+	return s.writeValues(headers)
+}
+
+// syncTab makes the tab hold exactly rows. Rows already in the sheet are
+// found by ct_id and rewritten in place when a value changed, new rows are
+// added at the bottom and rows whose ct_id is no longer wanted are deleted.
+// Rows without a ct_id were added by hand and are left alone.
+func syncTab(s *client, t tab, rows [][]any) error {
+	props, err := s.sheetProperties()
+	if err != nil {
+		return err
+	}
+	sheet, ok := props[t.Name]
+	if !ok {
+		return fmt.Errorf("tab %s is missing from the spreadsheet", t.Name)
+	}
+
+	values, err := s.readValues(t.dataRange())
+	if err != nil {
+		return err
+	}
+	existing := values[0]
+
+	wanted := make(map[string]int, len(rows))
+	for i, row := range rows {
+		wanted[cellString(row[0])] = i
+	}
+
+	// Row indexes below are 0-based like the API's dimension ranges; the
+	// header is row 0. Updates are addressed by the 1-based row number each
+	// kept row ends up at once the deletions before it are applied.
+	var deletes []int64
+	var updates []*sheetsapi.ValueRange
+	found := make(map[string]bool, len(existing))
+	for i, row := range existing {
+		if len(row) == 0 || cellString(row[0]) == "" {
+			continue
+		}
+		id := cellString(row[0])
+		rowIndex := int64(i + 1)
+
+		want, ok := wanted[id]
+		if !ok || found[id] {
+			deletes = append(deletes, rowIndex)
+			continue
+		}
+		found[id] = true
+
+		if !rowEqual(row, rows[want]) {
+			rowNumber := rowIndex - int64(len(deletes)) + 1
+			updates = append(updates, &sheetsapi.ValueRange{
+				Range:  t.rowsRange(rowNumber, rowNumber),
+				Values: [][]any{rows[want]},
+			})
+		}
+	}
+
+	var added [][]any
+	for _, row := range rows {
+		if !found[cellString(row[0])] {
+			added = append(added, row)
+		}
+	}
+
+	var requests []*sheetsapi.Request
+	for i := len(deletes) - 1; i >= 0; i-- {
+		requests = append(requests, &sheetsapi.Request{
+			DeleteDimension: &sheetsapi.DeleteDimensionRequest{
+				Range: &sheetsapi.DimensionRange{
+					SheetId:    sheet.SheetId,
+					Dimension:  "ROWS",
+					StartIndex: deletes[i],
+					EndIndex:   deletes[i] + 1,
+				},
+			},
+		})
+	}
+
+	lastRow := int64(len(existing)+1) - int64(len(deletes))
+	rowCount := sheet.GridProperties.RowCount - int64(len(deletes))
+	if need := lastRow + int64(len(added)); need > rowCount {
+		requests = append(requests, &sheetsapi.Request{
+			AppendDimension: &sheetsapi.AppendDimensionRequest{
+				SheetId:   sheet.SheetId,
+				Dimension: "ROWS",
+				Length:    need - rowCount,
+			},
+		})
+	}
+
+	if err := s.batchUpdate(requests); err != nil {
+		return err
+	}
+
+	changed := len(updates)
+	for start := 0; start < len(added); start += batchRows {
+		end := min(start+batchRows, len(added))
+		updates = append(updates, &sheetsapi.ValueRange{
+			Range:  t.rowsRange(lastRow+int64(start)+1, lastRow+int64(end)),
+			Values: added[start:end],
+		})
+	}
+
+	var batch []*sheetsapi.ValueRange
+	batchSize := 0
+	for _, vr := range updates {
+		batch = append(batch, vr)
+		batchSize += len(vr.Values)
+		if batchSize >= batchRows {
+			if err := s.writeValues(batch); err != nil {
+				return err
+			}
+			batch, batchSize = nil, 0
+		}
+	}
+	if err := s.writeValues(batch); err != nil {
+		return err
+	}
+
+	if len(deletes) > 0 || len(added) > 0 || changed > 0 {
+		log.Printf("Google Sheets tab %s: %d added, %d updated, %d deleted", t.Name, len(added), changed, len(deletes))
+	}
+	return nil
+}
+
+// removeSourceRows deletes a source's row and every row referencing it from
+// all tabs.
+func removeSourceRows(s *client, sourceID string) error {
+	props, err := s.sheetProperties()
+	if err != nil {
+		return err
+	}
+
+	ranges := make([]string, len(allTabs))
+	for i, t := range allTabs {
+		ranges[i] = t.dataRange()
+	}
+	values, err := s.readValues(ranges...)
+	if err != nil {
+		return err
+	}
+
+	var requests []*sheetsapi.Request
+	for i, t := range allTabs {
+		sheet, ok := props[t.Name]
+		if !ok {
+			continue
+		}
+		col := t.column("source_id")
+		if col < 0 {
+			col = t.column("ct_id")
+		}
+
+		rows := values[i]
+		for j := len(rows) - 1; j >= 0; j-- {
+			if col >= len(rows[j]) || cellString(rows[j][col]) != sourceID {
+				continue
+			}
+			requests = append(requests, &sheetsapi.Request{
+				DeleteDimension: &sheetsapi.DeleteDimensionRequest{
+					Range: &sheetsapi.DimensionRange{
+						SheetId:    sheet.SheetId,
+						Dimension:  "ROWS",
+						StartIndex: int64(j + 1),
+						EndIndex:   int64(j + 2),
+					},
+				},
+			})
+		}
+	}
+
+	return s.batchUpdate(requests)
+}
+
+func rowEqual(got, want []any) bool {
+	for i, v := range want {
+		var g any
+		if i < len(got) {
+			g = got[i]
+		}
+		if cellString(g) != cellString(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// cellString formats a cell the same way whether it was read back from the
+// API, which returns every number as a float64, or is about to be written.
+func cellString(v any) string {
+	switch c := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return c
+	case bool:
+		return strconv.FormatBool(c)
+	case int64:
+		return strconv.FormatInt(c, 10)
+	case float64:
+		return strconv.FormatFloat(c, 'f', -1, 64)
+	default:
+		return fmt.Sprint(c)
+	}
+}
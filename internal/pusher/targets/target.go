@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package targets
+
+import (
+	"fmt"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/helpers"
+	"github.com/fluffyriot/rpsync/internal/pusher/common"
+)
+
+// Run carries what a target needs to push one user's data.
+type Run struct {
+	DB            *database.Queries
+	Client        *common.Client
+	EncryptionKey []byte
+	Target        database.Target
+}
+
+// Target is a destination user data is pushed to. A sync calls Initialize and
+// then pushes sources, analytics, stats and posts in that order, stopping at
+// the first error unless the provider sets IndependentSteps. Steps a target
+// has no use for return nil.
+type Target interface {
+	// Initialize prepares the destination, e.g. creates its tables. It runs
+	// before every sync and must be a no-op once the destination is ready.
+	Initialize(r Run) error
+	PushSources(r Run) error
+	PushPosts(r Run) error
+	PushStats(r Run) error
+	PushAnalytics(r Run) error
+	// RemoveSource deletes everything pushed for a source that is about to
+	// be deleted or merged away. On a merge, the source's history and posts
+	// have already moved to the surviving source.
+	RemoveSource(r Run, source database.Source) error
+}
+
+type Provider struct {
+	Network helpers.TargetNetwork
+	Target  Target
+	// LogsExports is set for targets that log every file they write as its
+	// own export. Other targets get one export log per sync.
+	LogsExports bool
+	// IndependentSteps is set for targets whose push steps do not build on
+	// each other, like file exports. A failed step then does not keep the
+	// later ones from running and the sync returns all their errors.
+	IndependentSteps bool
+	// KeepsMergedPosts is set for targets that keep following a merged
+	// source's posts under the surviving source. Other targets drop the
+	// rows of the merged posts on their next sync and push them again.
+	KeepsMergedPosts bool
+}
+
+// None is the type of targets set up without a destination. They have
+// nothing to push or remove, so no provider is registered for them.
+const None = "None"
+
+var providers = map[string]Provider{}
+
+// Register makes a target available by its network name. It is called from
+// the init function of the package implementing the target.
+func Register(p Provider) {
+	if _, ok := providers[p.Network.Name]; ok {
+		panic("targets: " + p.Network.Name + " registered twice")
+	}
+	providers[p.Network.Name] = p
+	helpers.AvailableTargets = append(helpers.AvailableTargets, p.Network)
+}
+
+func Get(name string) (Provider, error) {
+	p, ok := providers[name]
+	if !ok {
+		return Provider{}, fmt.Errorf("target type %v not supported", name)
+	}
+	return p, nil
+}
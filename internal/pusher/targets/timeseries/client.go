@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package timeseries
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/authhelp"
+	"github.com/fluffyriot/rpsync/internal/pusher/targets"
+)
+
+const maxRetries = 5
+
+// errRejected is returned when the database accepted the request but
+// refused some of its points, e.g. because they were out of order.
+var errRejected = errors.New("points rejected")
+
+type client struct {
+	r   targets.Run
+	url string
+	// auth is the Authorization header, empty when no token is set.
+	auth string
+	// rejections are the phrases with which the database explains a 400
+	// response that refused points. Any other 400, e.g. a wrong org or
+	// bucket, fails the batch.
+	rejections []string
+}
+
+// newClient reads the optional token: user:password is sent as basic auth,
+// anything else with the given scheme.
+func newClient(r targets.Run, scheme, writeURL string, rejections []string) (*client, error) {
+	c := &client{r: r, url: writeURL, rejections: rejections}
+
+	token, _, _, err := authhelp.GetTargetToken(context.Background(), r.DB, r.EncryptionKey, r.Target.ID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return c, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+
+	token = strings.TrimSpace(token)
+	if strings.Contains(token, ":") {
+		c.auth = "Basic " + base64.StdEncoding.EncodeToString([]byte(token))
+	} else if token != "" {
+		c.auth = scheme + " " + token
+	}
+	return c, nil
+}
+
+func (c *client) post(body []byte, headers map[string]string) error {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest("POST", c.url, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("create request: %w", err)
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		if c.auth != "" {
+			req.Header.Set("Authorization", c.auth)
+		}
+
+		resp, err := c.r.Client.HTTPClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("send request: %w", err)
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("read response body: %w", err)
+		}
+
+		switch {
+		case (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500) && attempt < maxRetries:
+			delay := time.Duration(1<<attempt) * time.Second
+			if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+				delay = time.Duration(secs) * time.Second
+			}
+			log.Printf("%s returned %d, retrying in %s", c.r.Target.TargetType, resp.StatusCode, delay)
+			time.Sleep(delay)
+			continue
+		case resp.StatusCode == http.StatusUnprocessableEntity,
+			resp.StatusCode == http.StatusBadRequest && c.refusedPoints(respBody):
+			return fmt.Errorf("%w: %s", errRejected, strings.TrimSpace(string(respBody)))
+		case resp.StatusCode < 200 || resp.StatusCode > 299:
+			return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(respBody))
+		}
+		return nil
+	}
+}
+
+// refusedPoints reports whether a 400 response body explains which points
+// were refused, rather than why the whole request was.
+func (c *client) refusedPoints(body []byte) bool {
+	msg := strings.ToLower(string(body))
+	for _, r := range c.rejections {
+		if strings.Contains(msg, r) {
+			return true
+		}
+	}
+	return false
+}
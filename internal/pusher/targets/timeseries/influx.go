@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package timeseries
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fluffyriot/rpsync/internal/pusher/targets"
+)
+
+// influxBatchSize is the number of lines per request InfluxDB recommends.
+const influxBatchSize = 5000
+
+// influxRejections mark the 400 responses of all three versions that
+// refused some points but wrote the rest. InfluxDB 2.x answers 422 when
+// every point is beyond the retention period.
+var influxRejections = []string{"partial write", "beyond retention"}
+
+type influxWriter struct {
+	c *client
+}
+
+// newInfluxWriter works with the write endpoints of InfluxDB 1.x
+// (/write?db=) and 2.x and 3.x (/api/v2/write?org=&bucket=). Points are
+// written with second precision.
+func newInfluxWriter(r targets.Run) (writer, error) {
+	u, err := url.Parse(r.Target.HostUrl.String)
+	if err != nil {
+		return nil, fmt.Errorf("invalid write URL: %w", err)
+	}
+	q := u.Query()
+	q.Set("precision", "s")
+	u.RawQuery = q.Encode()
+
+	c, err := newClient(r, "Token", u.String(), influxRejections)
+	if err != nil {
+		return nil, err
+	}
+	return influxWriter{c: c}, nil
+}
+
+func (w influxWriter) write(points []point) error {
+	var rejected []error
+	for start := 0; start < len(points); start += influxBatchSize {
+		var b strings.Builder
+		for _, p := range points[start:min(start+influxBatchSize, len(points))] {
+			writeLine(&b, p)
+		}
+		err := w.c.post([]byte(b.String()), map[string]string{"Content-Type": "text/plain; charset=utf-8"})
+		if errors.Is(err, errRejected) {
+			rejected = append(rejected, err)
+		} else if err != nil {
+			return err
+		}
+	}
+	return errors.Join(rejected...)
+}
+
+var (
+	measurementEscaper = strings.NewReplacer(`,`, `\,`, ` `, `\ `, "\n", `\n`)
+	tagEscaper         = strings.NewReplacer(`,`, `\,`, `=`, `\=`, ` `, `\ `, "\n", `\n`)
+)
+
+// writeLine writes a point in line protocol. Empty tag values are left out,
+// as InfluxDB rejects them.
+func writeLine(b *strings.Builder, p point) {
+	b.WriteString(measurementEscaper.Replace(p.measurement))
+
+	keys := make([]string, 0, len(p.tags))
+	for k := range p.tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if p.tags[k] == "" {
+			continue
+		}
+		b.WriteString("," + tagEscaper.Replace(k) + "=" + tagEscaper.Replace(p.tags[k]))
+	}
+
+	for i, f := range p.fields {
+		if i == 0 {
+			b.WriteByte(' ')
+		} else {
+			b.WriteByte(',')
+		}
+		b.WriteString(tagEscaper.Replace(f.name) + "=")
+		switch v := f.value.(type) {
+		case int64:
+			b.WriteString(strconv.FormatInt(v, 10) + "i")
+		case float64:
+			b.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+		}
+	}
+
+	b.WriteString(" " + strconv.FormatInt(p.time.Unix(), 10) + "\n")
+}
@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package timeseries
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/fluffyriot/rpsync/internal/pusher/targets"
+	"github.com/klauspost/compress/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// prometheusBatchSize is the number of points per request; each field of a
+// point is a sample of its own.
+const prometheusBatchSize = 1000
+
+// metricPrefixes name the metric of a field, e.g. the likes field of a
+// post_reactions point is rpsync_post_likes.
+var metricPrefixes = map[string]string{
+	"followers":      "rpsync_",
+	"reactions":      "rpsync_source_",
+	"post_reactions": "rpsync_post_",
+	"site_stats":     "rpsync_site_",
+}
+
+// prometheusRejections mark the 400 responses that refused samples for
+// their timestamps; the other samples of the request are still written.
+var prometheusRejections = []string{"out of order", "out-of-order", "duplicate sample", "too old", "out of bounds"}
+
+type prometheusWriter struct {
+	c *client
+}
+
+// newPrometheusWriter speaks remote write 1.0, which Prometheus (with
+// --web.enable-remote-write-receiver), Mimir, Thanos and VictoriaMetrics
+// accept.
+func newPrometheusWriter(r targets.Run) (writer, error) {
+	c, err := newClient(r, "Bearer", r.Target.HostUrl.String, prometheusRejections)
+	if err != nil {
+		return nil, err
+	}
+	return prometheusWriter{c: c}, nil
+}
+
+type label struct {
+	name, value string
+}
+
+type sample struct {
+	value     float64
+	timestamp int64
+}
+
+type timeSeries struct {
+	labels  []label
+	samples []sample
+}
+
+func (w prometheusWriter) write(points []point) error {
+	headers := map[string]string{
+		"Content-Type":                      "application/x-protobuf",
+		"Content-Encoding":                  "snappy",
+		"X-Prometheus-Remote-Write-Version": "0.1.0",
+	}
+	var rejected []error
+	for start := 0; start < len(points); start += prometheusBatchSize {
+		series := groupSeries(points[start:min(start+prometheusBatchSize, len(points))])
+		body := snappy.Encode(nil, encodeWriteRequest(series))
+		err := w.c.post(body, headers)
+		if errors.Is(err, errRejected) {
+			rejected = append(rejected, err)
+		} else if err != nil {
+			return err
+		}
+	}
+	return errors.Join(rejected...)
+}
+
+// groupSeries turns points, oldest first, into one series per metric and
+// label set, keeping the samples of each in order.
+func groupSeries(points []point) []*timeSeries {
+	var series []*timeSeries
+	byKey := make(map[string]*timeSeries)
+	for _, p := range points {
+		for _, f := range p.fields {
+			labels := []label{{"__name__", metricPrefixes[p.measurement] + f.name}}
+			for k, v := range p.tags {
+				if v != "" {
+					labels = append(labels, label{k, v})
+				}
+			}
+			sort.Slice(labels, func(i, j int) bool { return labels[i].name < labels[j].name })
+
+			var key strings.Builder
+			for _, l := range labels {
+				key.WriteString(l.name + "\xff" + l.value + "\xff")
+			}
+			ts, ok := byKey[key.String()]
+			if !ok {
+				ts = &timeSeries{labels: labels}
+				byKey[key.String()] = ts
+				series = append(series, ts)
+			}
+
+			var value float64
+			switch v := f.value.(type) {
+			case int64:
+				value = float64(v)
+			case float64:
+				value = v
+			}
+			ts.samples = append(ts.samples, sample{value: value, timestamp: p.time.UnixMilli()})
+		}
+	}
+	return series
+}
+
+// encodeWriteRequest encodes a prometheus.WriteRequest message:
+// timeseries = 1, with labels = 1 (name = 1, value = 2) and samples = 2
+// (value = 1, timestamp = 2).
+func encodeWriteRequest(series []*timeSeries) []byte {
+	var req []byte
+	for _, ts := range series {
+		var msg []byte
+		for _, l := range ts.labels {
+			var lb []byte
+			lb = protowire.AppendTag(lb, 1, protowire.BytesType)
+			lb = protowire.AppendString(lb, l.name)
+			lb = protowire.AppendTag(lb, 2, protowire.BytesType)
+			lb = protowire.AppendString(lb, l.value)
+			msg = protowire.AppendTag(msg, 1, protowire.BytesType)
+			msg = protowire.AppendBytes(msg, lb)
+		}
+		for _, s := range ts.samples {
+			var sb []byte
+			sb = protowire.AppendTag(sb, 1, protowire.Fixed64Type)
+			sb = protowire.AppendFixed64(sb, math.Float64bits(s.value))
+			sb = protowire.AppendTag(sb, 2, protowire.VarintType)
+			sb = protowire.AppendVarint(sb, uint64(s.timestamp))
+			msg = protowire.AppendTag(msg, 2, protowire.BytesType)
+			msg = protowire.AppendBytes(msg, sb)
+		}
+		req = protowire.AppendTag(req, 1, protowire.BytesType)
+		req = protowire.AppendBytes(req, msg)
+	}
+	return req
+}
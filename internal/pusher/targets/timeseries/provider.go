@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package timeseries
+
+import (
+	"context"
+	"errors"
+	"net/url"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/helpers"
+	"github.com/fluffyriot/rpsync/internal/pusher/targets"
+)
+
+// perPostField opts a target into a reactions series per post. Every post
+// adds series of its own, so it is off by default.
+var perPostField = helpers.TargetField{Name: "db_id", Label: "Per-Post Series", Placeholder: "Leave empty for totals per source, or enter \"posts\" to also write each post", Optional: true}
+
+func init() {
+	targets.Register(targets.Provider{
+		Network: helpers.TargetNetwork{
+			Name:  "InfluxDB",
+			Color: "#22adf6",
+			Fields: []helpers.TargetField{
+				{Name: "host_url", Label: "Write URL", Placeholder: "https://influxdb.example.com/api/v2/write?org=me&bucket=rpsync"},
+				{Name: "api_token", Label: "API Token", Placeholder: "Token, or user:password for InfluxDB 1.x", Optional: true},
+				perPostField,
+			},
+		},
+		Target:           Series{newWriter: newInfluxWriter, overwrites: true},
+		IndependentSteps: true,
+	})
+	targets.Register(targets.Provider{
+		Network: helpers.TargetNetwork{
+			Name:  "Prometheus",
+			Color: "#e6522c",
+			Fields: []helpers.TargetField{
+				{Name: "host_url", Label: "Remote Write URL", Placeholder: "https://prometheus.example.com/api/v1/write"},
+				{Name: "api_token", Label: "Bearer Token", Placeholder: "Token, or user:password for basic auth", Optional: true},
+				perPostField,
+			},
+		},
+		Target:           Series{newWriter: newPrometheusWriter},
+		IndependentSteps: true,
+	})
+}
+
+// Series writes follower stats, reaction snapshots and website stats as time
+// series. Each source remembers the newest point written per series in
+// timeseries_watermarks, so a push only sends what is newer.
+type Series struct {
+	newWriter func(r targets.Run) (writer, error)
+	// overwrites is set when writing a point again replaces its value.
+	// Prometheus rejects a second value for the same timestamp instead.
+	overwrites bool
+}
+
+// writer sends points to a time series database, oldest first, in batches.
+// Batches with rejected points don't stop the later ones; the rejections
+// are returned together, wrapping errRejected, once every batch is sent.
+type writer interface {
+	write(points []point) error
+}
+
+func (Series) Initialize(r targets.Run) error {
+	u, err := url.Parse(r.Target.HostUrl.String)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return errors.New("a write URL starting with http:// or https:// is required")
+	}
+	if v := r.Target.DbID.String; v != "" && v != "posts" {
+		return errors.New(`per-post series must be empty or "posts"`)
+	}
+	return nil
+}
+
+// PushSources has nothing to write; sources only appear as labels.
+func (Series) PushSources(r targets.Run) error {
+	return nil
+}
+
+func (t Series) PushAnalytics(r targets.Run) error {
+	return t.push(r, siteStatsSeries)
+}
+
+func (t Series) PushStats(r targets.Run) error {
+	return t.push(r, followersSeries)
+}
+
+func (t Series) PushPosts(r targets.Run) error {
+	if err := t.push(r, reactionsSeries); err != nil {
+		return err
+	}
+	if r.Target.DbID.String == "posts" {
+		return t.push(r, postReactionsSeries)
+	}
+	return nil
+}
+
+// RemoveSource forgets the source's watermarks. Points already written stay
+// in the database, as neither protocol can delete them.
+func (Series) RemoveSource(r targets.Run, source database.Source) error {
+	return r.DB.DeleteTimeseriesWatermarksForSource(context.Background(), database.DeleteTimeseriesWatermarksForSourceParams{
+		TargetID: r.Target.ID,
+		SourceID: source.ID,
+	})
+}
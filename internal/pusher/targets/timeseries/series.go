@@ -0,0 +1,282 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package timeseries
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/pusher/targets"
+	"github.com/fluffyriot/rpsync/internal/pusher/targets/records"
+	"github.com/google/uuid"
+)
+
+type field struct {
+	name string
+	// value is an int64 or a float64.
+	value any
+}
+
+type point struct {
+	measurement string
+	tags        map[string]string
+	fields      []field
+	time        time.Time
+}
+
+// series is one kind of point, collected source by source.
+type series struct {
+	name string
+	// daily is set for series with one point per day, whose newest point
+	// changes until the day is over.
+	daily bool
+	// rewindDays is how far before the watermark points are written again,
+	// for databases that overwrite points with the same timestamp.
+	rewindDays int
+	// collect returns the source's points newer than since, which is the
+	// zero time on the first push.
+	collect func(r targets.Run, source database.Source, since time.Time) ([]point, error)
+}
+
+var (
+	// followersSeries includes the watermark's day, which is written again
+	// as it may have been written before the day was over.
+	followersSeries = series{name: "followers", daily: true, collect: func(r targets.Run, source database.Source, since time.Time) ([]point, error) {
+		stats, err := r.DB.GetSourceStatsSince(context.Background(), database.GetSourceStatsSinceParams{
+			SourceID: source.ID,
+			Date:     since,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var points []point
+		for _, stat := range stats {
+			var fields []field
+			fields = appendInt(fields, "followers_count", stat.FollowersCount)
+			fields = appendInt(fields, "following_count", stat.FollowingCount)
+			fields = appendInt(fields, "posts_count", stat.PostsCount)
+			fields = appendInt(fields, "followers_delta", stat.FollowersDelta)
+			fields = appendInt(fields, "following_delta", stat.FollowingDelta)
+			fields = appendInt(fields, "karma", stat.Karma)
+			fields = appendFloat(fields, "average_likes", stat.AverageLikes)
+			fields = appendFloat(fields, "average_reposts", stat.AverageReposts)
+			fields = appendFloat(fields, "average_views", stat.AverageViews)
+			if len(fields) > 0 {
+				points = append(points, point{measurement: "followers", tags: sourceTags(source), fields: fields, time: stat.Date})
+			}
+		}
+		return points, nil
+	}}
+
+	// reactionsSeries sums the day's reaction snapshots of a source's posts
+	// per post type, keeping one series per source however many posts it
+	// has.
+	reactionsSeries = series{name: "reactions", daily: true, collect: func(r targets.Run, source database.Source, since time.Time) ([]point, error) {
+		// since is the start of the newest day written, which is summed
+		// again in full.
+		if !since.IsZero() {
+			since = since.Add(-time.Microsecond)
+		}
+		history, err := r.DB.GetReactionsForSourceSince(context.Background(), database.GetReactionsForSourceSinceParams{
+			SourceID: source.ID,
+			SyncedAt: since,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		type key struct {
+			day      time.Time
+			postType string
+		}
+		totals := make(map[key]*[4]sql.NullInt64)
+		var keys []key
+		for _, h := range history {
+			k := key{h.SyncedAt.UTC().Truncate(24 * time.Hour), h.PostType}
+			sums, ok := totals[k]
+			if !ok {
+				sums = &[4]sql.NullInt64{}
+				totals[k] = sums
+				keys = append(keys, k)
+			}
+			for i, v := range []sql.NullInt64{h.Likes, h.Reposts, h.Views, h.Comments} {
+				if v.Valid {
+					sums[i] = sql.NullInt64{Int64: sums[i].Int64 + v.Int64, Valid: true}
+				}
+			}
+		}
+
+		var points []point
+		for _, k := range keys {
+			sums := totals[k]
+			var fields []field
+			fields = appendInt(fields, "likes", sums[0])
+			fields = appendInt(fields, "reposts", sums[1])
+			fields = appendInt(fields, "views", sums[2])
+			fields = appendInt(fields, "comments", sums[3])
+			if len(fields) == 0 {
+				continue
+			}
+			tags := sourceTags(source)
+			tags["post_type"] = k.postType
+			points = append(points, point{measurement: "reactions", tags: tags, fields: fields, time: k.day})
+		}
+		return points, nil
+	}}
+
+	// postReactionsSeries writes every snapshot as it was taken, with one
+	// series per post. Only targets that opt in get it.
+	postReactionsSeries = series{name: "post_reactions", collect: func(r targets.Run, source database.Source, since time.Time) ([]point, error) {
+		history, err := r.DB.GetReactionsForSourceSince(context.Background(), database.GetReactionsForSourceSinceParams{
+			SourceID: source.ID,
+			SyncedAt: since,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var points []point
+		for _, h := range history {
+			var fields []field
+			fields = appendInt(fields, "likes", h.Likes)
+			fields = appendInt(fields, "reposts", h.Reposts)
+			fields = appendInt(fields, "views", h.Views)
+			fields = appendInt(fields, "comments", h.Comments)
+			if len(fields) == 0 {
+				continue
+			}
+			tags := sourceTags(source)
+			tags["post_type"] = h.PostType
+			tags["post_id"] = h.PostID.String()
+			points = append(points, point{measurement: "post_reactions", tags: tags, fields: fields, time: h.SyncedAt})
+		}
+		return points, nil
+	}}
+
+	// siteStatsSeries rewinds as far as the record targets rewrite website
+	// stats.
+	siteStatsSeries = series{name: "site_stats", daily: true, rewindDays: records.AnalyticsUpdateDays, collect: func(r targets.Run, source database.Source, since time.Time) ([]point, error) {
+		stats, err := r.DB.GetAnalyticsSiteStatsBySourceAndRange(context.Background(), database.GetAnalyticsSiteStatsBySourceAndRangeParams{
+			SourceID: source.ID,
+			Date:     since,
+			Date_2:   time.Now(),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		points := make([]point, len(stats))
+		for i, stat := range stats {
+			fields := []field{
+				{"visitors", stat.Visitors},
+				{"avg_session_duration", stat.AvgSessionDuration},
+			}
+			fields = appendInt(fields, "impressions", stat.Impressions)
+			tags := sourceTags(source)
+			tags["analytics_type"] = stat.AnalyticsType
+			points[i] = point{measurement: "site_stats", tags: tags, fields: fields, time: stat.Date}
+		}
+		return points, nil
+	}}
+)
+
+func sourceTags(source database.Source) map[string]string {
+	return map[string]string{
+		"network":   source.Network,
+		"source":    source.UserName,
+		"source_id": source.ID.String(),
+	}
+}
+
+func appendInt(fields []field, name string, v sql.NullInt64) []field {
+	if !v.Valid {
+		return fields
+	}
+	return append(fields, field{name, v.Int64})
+}
+
+func appendFloat(fields []field, name string, v sql.NullFloat64) []field {
+	if !v.Valid {
+		return fields
+	}
+	return append(fields, field{name, v.Float64})
+}
+
+// push writes the series source by source and moves each source's watermark
+// once its points are written.
+func (t Series) push(r targets.Run, s series) error {
+	w, err := t.newWriter(r)
+	if err != nil {
+		return err
+	}
+
+	sources, err := r.DB.GetUserSources(context.Background(), r.Target.UserID)
+	if err != nil {
+		return fmt.Errorf("error fetching user sources: %w", err)
+	}
+
+	marks, err := r.DB.GetTimeseriesWatermarks(context.Background(), r.Target.ID)
+	if err != nil {
+		return fmt.Errorf("error fetching watermarks: %w", err)
+	}
+	since := make(map[uuid.UUID]time.Time)
+	for _, m := range marks {
+		if m.Series == s.name {
+			since[m.SourceID] = m.LastExportedAt
+		}
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	total := 0
+	for _, source := range sources {
+		from := since[source.ID]
+		if t.overwrites && !from.IsZero() {
+			from = from.AddDate(0, 0, -s.rewindDays)
+		}
+		points, err := s.collect(r, source, from)
+		if err != nil {
+			return fmt.Errorf("failed to read %s of %s: %w", s.name, source.UserName, err)
+		}
+
+		sort.SliceStable(points, func(i, j int) bool { return points[i].time.Before(points[j].time) })
+		// Databases that keep the first value written for a timestamp only
+		// get days that are over.
+		if s.daily && !t.overwrites {
+			n := sort.Search(len(points), func(i int) bool { return !points[i].time.Before(today) })
+			points = points[:n]
+		}
+		if len(points) == 0 {
+			continue
+		}
+
+		// A rejected point would be rejected again on every push, so the
+		// watermark still moves past it. Every batch has been sent by then,
+		// so no accepted points are skipped.
+		err = w.write(points)
+		if errors.Is(err, errRejected) {
+			log.Printf("Warning: %s rejected some %s points of %s: %v", r.Target.TargetType, s.name, source.UserName, err)
+		} else if err != nil {
+			return fmt.Errorf("failed to write %s of %s: %w", s.name, source.UserName, err)
+		}
+
+		if err := r.DB.SetTimeseriesWatermark(context.Background(), database.SetTimeseriesWatermarkParams{
+			TargetID:       r.Target.ID,
+			SourceID:       source.ID,
+			Series:         s.name,
+			LastExportedAt: points[len(points)-1].time,
+		}); err != nil {
+			return fmt.Errorf("failed to save watermark: %w", err)
+		}
+		total += len(points)
+	}
+
+	log.Printf("%s: wrote %d %s points", r.Target.TargetType, total, s.name)
+	return nil
+}
@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package warehouse
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/authhelp"
+	"github.com/fluffyriot/rpsync/internal/pusher/targets"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+// warehouseDir is where SQLite and DuckDB warehouses are written. Each target
+// gets its own directory below it, so a file name can neither overwrite an
+// export in outputs/ nor another target's warehouse.
+var warehouseDir = filepath.Join("outputs", "warehouse")
+
+// duckdbDriver is the name the DuckDB driver registered under. It stays
+// empty in builds without cgo, which the driver needs.
+var duckdbDriver string
+
+// dialect covers the differences between the databases a warehouse can live
+// in. SQLite has no date types, so dates and times are stored as ISO 8601
+// text, which sorts and compares correctly.
+type dialect struct {
+	types map[kind]string
+	// numbered is set when placeholders are written $1, $2, ... instead of ?.
+	numbered bool
+	// textTimes is set when timestamps are stored as RFC 3339 text.
+	textTimes bool
+	// noIndexes is set when only primary keys are created. DuckDB refuses
+	// upserts that assign to indexed columns, and scans fast enough without
+	// them.
+	noIndexes bool
+}
+
+var (
+	postgresDialect = dialect{
+		types: map[kind]string{
+			text:      "TEXT",
+			integer:   "BIGINT",
+			float:     "DOUBLE PRECISION",
+			boolean:   "BOOLEAN",
+			timestamp: "TIMESTAMPTZ",
+			date:      "DATE",
+		},
+		numbered: true,
+	}
+	sqliteDialect = dialect{
+		types: map[kind]string{
+			text:      "TEXT",
+			integer:   "INTEGER",
+			float:     "REAL",
+			boolean:   "INTEGER",
+			timestamp: "TEXT",
+			date:      "TEXT",
+		},
+		textTimes: true,
+	}
+	duckdbDialect = dialect{
+		types: map[kind]string{
+			text:      "TEXT",
+			integer:   "BIGINT",
+			float:     "DOUBLE",
+			boolean:   "BOOLEAN",
+			timestamp: "TIMESTAMPTZ",
+			date:      "DATE",
+		},
+		noIndexes: true,
+	}
+)
+
+// open connects to the warehouse named by the target's connection string:
+// a Postgres URL, or sqlite:<file> or duckdb:<file> for a file in the
+// target's directory under outputs/warehouse/.
+func open(r targets.Run) (*sql.DB, dialect, error) {
+	conn, _, _, err := authhelp.GetTargetToken(context.Background(), r.DB, r.EncryptionKey, r.Target.ID)
+	if err != nil {
+		return nil, dialect{}, fmt.Errorf("failed to get warehouse connection: %w", err)
+	}
+	conn = strings.TrimSpace(conn)
+
+	var db *sql.DB
+	var d dialect
+	switch {
+	case strings.HasPrefix(conn, "postgres://"), strings.HasPrefix(conn, "postgresql://"):
+		d = postgresDialect
+		db, err = sql.Open("postgres", conn)
+	case strings.HasPrefix(conn, "sqlite:"):
+		name, nameErr := warehouseFile(r.Target.ID, conn, "sqlite", "reporting.db")
+		if nameErr != nil {
+			return nil, dialect{}, nameErr
+		}
+		d = sqliteDialect
+		// WAL lets reporting tools read the file while a sync writes to it.
+		db, err = sql.Open("sqlite", name+"?_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)")
+	case strings.HasPrefix(conn, "duckdb:"):
+		if duckdbDriver == "" {
+			return nil, dialect{}, errors.New("DuckDB warehouses need a build with cgo enabled")
+		}
+		name, nameErr := warehouseFile(r.Target.ID, conn, "duckdb", "reporting.duckdb")
+		if nameErr != nil {
+			return nil, dialect{}, nameErr
+		}
+		d = duckdbDialect
+		db, err = sql.Open(duckdbDriver, name)
+	default:
+		return nil, dialect{}, errors.New("warehouse connection must start with postgres://, sqlite: or duckdb:")
+	}
+	if err != nil {
+		return nil, dialect{}, fmt.Errorf("failed to open warehouse: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, dialect{}, fmt.Errorf("failed to connect to warehouse: %w", err)
+	}
+	return db, d, nil
+}
+
+// warehouseFile returns the path of a scheme:<file> connection in the
+// target's directory, creating the directory if needed.
+func warehouseFile(targetID uuid.UUID, conn, scheme, example string) (string, error) {
+	name := strings.TrimPrefix(conn, scheme+":")
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\?`) {
+		return "", fmt.Errorf("invalid %s file name %q, give a plain file name such as %s:%s", scheme, name, scheme, example)
+	}
+	dir := filepath.Join(warehouseDir, targetID.String())
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create warehouse directory: %w", err)
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// rebind rewrites the ? placeholders of a query for the dialect. Queries
+// only ever hold identifiers and placeholders, never literal text.
+func (d dialect) rebind(query string) string {
+	if !d.numbered {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// encode converts a value for a column of the given kind.
+func (d dialect) encode(k kind, v any) any {
+	t, ok := v.(time.Time)
+	if !ok {
+		return v
+	}
+	switch {
+	case k == date:
+		return t.Format("2006-01-02")
+	case d.textTimes:
+		return t.UTC().Format(time.RFC3339)
+	}
+	return t
+}
@@ -0,0 +1,11 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+//go:build cgo
+
+package warehouse
+
+import _ "github.com/marcboeker/go-duckdb"
+
+func init() {
+	duckdbDriver = "duckdb"
+}
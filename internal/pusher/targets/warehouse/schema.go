@@ -0,0 +1,279 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package warehouse
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fluffyriot/rpsync/internal/pusher/targets"
+)
+
+type kind int
+
+const (
+	text kind = iota
+	integer
+	float
+	boolean
+	timestamp
+	date
+)
+
+type column struct {
+	name string
+	kind kind
+}
+
+// table is a reporting table. The first column is always the text id the row
+// has in the app.
+type table struct {
+	name    string
+	columns []column
+	// indexes lists the columns reports join and filter on.
+	indexes []string
+}
+
+// The reporting schema is documented in the README. Columns are only ever
+// added, so saved queries keep working.
+var (
+	sourcesTable = table{
+		name: "sources",
+		columns: []column{
+			{"id", text}, {"user_id", text}, {"network", text}, {"username", text}, {"url", text},
+			{"is_active", boolean}, {"sync_status", text}, {"last_synced", timestamp}, {"created_at", timestamp},
+		},
+		indexes: []string{"user_id"},
+	}
+	postsTable = table{
+		name: "posts",
+		columns: []column{
+			{"id", text}, {"source_id", text}, {"network", text}, {"network_internal_id", text},
+			{"post_type", text}, {"author", text}, {"content", text}, {"url", text}, {"is_archived", boolean},
+			{"created_at", timestamp}, {"likes", integer}, {"reposts", integer}, {"views", integer},
+			{"reactions_synced_at", timestamp},
+		},
+		indexes: []string{"source_id", "created_at"},
+	}
+	reactionsTable = table{
+		name: "post_reactions",
+		columns: []column{
+			{"id", text}, {"post_id", text}, {"synced_at", timestamp},
+			{"likes", integer}, {"reposts", integer}, {"views", integer}, {"comments", integer},
+		},
+		indexes: []string{"post_id", "synced_at"},
+	}
+	followerStatsTable = table{
+		name: "follower_stats",
+		columns: []column{
+			{"id", text}, {"source_id", text}, {"date", date},
+			{"followers_count", integer}, {"following_count", integer}, {"posts_count", integer},
+			{"followers_delta", integer}, {"following_delta", integer}, {"karma", integer},
+			{"average_likes", float}, {"average_reposts", float}, {"average_views", float},
+		},
+		indexes: []string{"source_id", "date"},
+	}
+	siteStatsTable = table{
+		name: "site_stats",
+		columns: []column{
+			{"id", text}, {"source_id", text}, {"date", date}, {"analytics_type", text},
+			{"visitors", integer}, {"avg_session_duration", float}, {"impressions", integer},
+		},
+		indexes: []string{"source_id", "date"},
+	}
+	pageStatsTable = table{
+		name: "page_stats",
+		columns: []column{
+			{"id", text}, {"source_id", text}, {"date", date}, {"analytics_type", text},
+			{"page_path", text}, {"views", integer}, {"impressions", integer},
+		},
+		indexes: []string{"source_id", "date"},
+	}
+	tagsTable = table{
+		name: "tags",
+		columns: []column{
+			{"id", text}, {"user_id", text}, {"name", text}, {"classification", text},
+			{"created_at", timestamp}, {"updated_at", timestamp},
+		},
+		indexes: []string{"user_id"},
+	}
+	postTagsTable = table{
+		name: "post_tags",
+		columns: []column{
+			{"id", text}, {"post_id", text}, {"tag_id", text}, {"created_at", timestamp},
+		},
+		indexes: []string{"post_id", "tag_id"},
+	}
+)
+
+// schemaVersion is stored in schema_info so reports can check what they
+// query. Bump it, with an upgrade step in migrate, when a column is added.
+const schemaVersion = 1
+
+var allTables = []table{sourcesTable, postsTable, reactionsTable, followerStatsTable, siteStatsTable, pageStatsTable, tagsTable, postTagsTable}
+
+func createTable(d dialect, t table) []string {
+	defs := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		defs[i] = c.name + " " + d.types[c.kind]
+	}
+	defs[0] += " PRIMARY KEY"
+
+	stmts := []string{fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", t.name, strings.Join(defs, ", "))}
+	if d.noIndexes {
+		return stmts
+	}
+	for _, c := range t.indexes {
+		stmts = append(stmts, fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_%s_idx ON %s (%s)", t.name, c, t.name, c))
+	}
+	return stmts
+}
+
+type session struct {
+	r  targets.Run
+	d  dialect
+	tx *sql.Tx
+}
+
+func (s *session) exec(query string, args ...any) error {
+	_, err := s.tx.Exec(s.d.rebind(query), args...)
+	return err
+}
+
+// migrate brings the schema up to the latest version.
+func (s *session) migrate() error {
+	if err := s.exec("CREATE TABLE IF NOT EXISTS schema_info (version INTEGER NOT NULL)"); err != nil {
+		return fmt.Errorf("failed to create schema_info: %w", err)
+	}
+
+	var version int
+	err := s.tx.QueryRow("SELECT version FROM schema_info").Scan(&version)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	switch version {
+	case schemaVersion:
+		return nil
+	case 0:
+		for _, t := range allTables {
+			for _, stmt := range createTable(s.d, t) {
+				if err := s.exec(stmt); err != nil {
+					return fmt.Errorf("failed to create table %s: %w", t.name, err)
+				}
+			}
+		}
+		return s.exec("INSERT INTO schema_info (version) VALUES (?)", schemaVersion)
+	default:
+		return fmt.Errorf("warehouse schema version %d is not supported, expected %d", version, schemaVersion)
+	}
+}
+
+// upsertBatchSize keeps the placeholders of a statement well under the limit
+// of both databases.
+const upsertBatchSize = 200
+
+func (s *session) upsert(t table, rows [][]any) error {
+	names := make([]string, len(t.columns))
+	var set []string
+	for i, c := range t.columns {
+		names[i] = c.name
+		if i > 0 {
+			set = append(set, c.name+" = excluded."+c.name)
+		}
+	}
+	row := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(t.columns)), ", ") + ")"
+
+	for start := 0; start < len(rows); start += upsertBatchSize {
+		chunk := rows[start:min(start+upsertBatchSize, len(rows))]
+
+		values := make([]string, len(chunk))
+		args := make([]any, 0, len(chunk)*len(t.columns))
+		for i, r := range chunk {
+			values[i] = row
+			for j, v := range r {
+				args = append(args, s.d.encode(t.columns[j].kind, v))
+			}
+		}
+
+		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s ON CONFLICT (id) DO UPDATE SET %s",
+			t.name, strings.Join(names, ", "), strings.Join(values, ", "), strings.Join(set, ", "))
+		if err := s.exec(query, args...); err != nil {
+			return fmt.Errorf("failed to upsert into %s: %w", t.name, err)
+		}
+	}
+	return nil
+}
+
+// stale returns the ids of rows matching where that are not in keep.
+func (s *session) stale(t table, where string, keep map[string]bool, args ...any) ([]string, error) {
+	rows, err := s.tx.Query(s.d.rebind("SELECT id FROM "+t.name+" WHERE "+where), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rows of %s: %w", t.name, err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		if !keep[id] {
+			ids = append(ids, id)
+		}
+	}
+	return ids, rows.Err()
+}
+
+const deleteBatchSize = 500
+
+// deleteIn runs query, which has a single %s for a list of placeholders, for
+// the ids in batches.
+func (s *session) deleteIn(query string, ids []string) error {
+	for start := 0; start < len(ids); start += deleteBatchSize {
+		chunk := ids[start:min(start+deleteBatchSize, len(ids))]
+		args := make([]any, len(chunk))
+		for i, id := range chunk {
+			args[i] = id
+		}
+		list := strings.TrimSuffix(strings.Repeat("?, ", len(chunk)), ", ")
+		if err := s.exec(fmt.Sprintf(query, list), args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteSources deletes sources along with their posts and stats.
+func (s *session) deleteSources(ids []string) error {
+	for _, query := range []string{
+		"DELETE FROM post_reactions WHERE post_id IN (SELECT id FROM posts WHERE source_id IN (%s))",
+		"DELETE FROM post_tags WHERE post_id IN (SELECT id FROM posts WHERE source_id IN (%s))",
+		"DELETE FROM posts WHERE source_id IN (%s)",
+		"DELETE FROM follower_stats WHERE source_id IN (%s)",
+		"DELETE FROM site_stats WHERE source_id IN (%s)",
+		"DELETE FROM page_stats WHERE source_id IN (%s)",
+		"DELETE FROM sources WHERE id IN (%s)",
+	} {
+		if err := s.deleteIn(query, ids); err != nil {
+			return fmt.Errorf("failed to delete sources: %w", err)
+		}
+	}
+	return nil
+}
+
+// deletePosts deletes posts along with their reactions and tags.
+func (s *session) deletePosts(ids []string) error {
+	for _, query := range []string{
+		"DELETE FROM post_reactions WHERE post_id IN (%s)",
+		"DELETE FROM post_tags WHERE post_id IN (%s)",
+		"DELETE FROM posts WHERE id IN (%s)",
+	} {
+		if err := s.deleteIn(query, ids); err != nil {
+			return fmt.Errorf("failed to delete posts: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,335 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package warehouse
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/helpers"
+)
+
+const (
+	userSources = "source_id IN (SELECT id FROM sources WHERE user_id = ?)"
+	userPosts   = "post_id IN (SELECT id FROM posts WHERE " + userSources + ")"
+
+	// statsUpdateDays is how far back stats are rewritten on an incremental
+	// sync; the analytics providers revise recent days for about a week.
+	statsUpdateDays = 9
+)
+
+// fullRefresh reports whether the whole reaction history and all stats
+// should be copied and checked for deleted rows. That happens on the first
+// sync of each day and the one after a failure; other syncs only copy what
+// changed recently.
+func fullRefresh(target database.Target, now time.Time) bool {
+	if target.SyncStatus != "Synced" || !target.LastSynced.Valid {
+		return true
+	}
+	y1, m1, d1 := target.LastSynced.Time.Date()
+	y2, m2, d2 := now.Date()
+	return y1 != y2 || m1 != m2 || d1 != d2
+}
+
+func nullInt(v sql.NullInt64) any {
+	if !v.Valid {
+		return nil
+	}
+	return v.Int64
+}
+
+func nullFloat(v sql.NullFloat64) any {
+	if !v.Valid {
+		return nil
+	}
+	return v.Float64
+}
+
+func nullTime(v sql.NullTime) any {
+	if !v.Valid {
+		return nil
+	}
+	return v.Time
+}
+
+func nullString(v sql.NullString) any {
+	if !v.Valid {
+		return nil
+	}
+	return v.String
+}
+
+func (s *session) syncSources() error {
+	sources, err := s.r.DB.GetUserSources(context.Background(), s.r.Target.UserID)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]any, len(sources))
+	keep := make(map[string]bool, len(sources))
+	for i, source := range sources {
+		url, _ := helpers.ConvNetworkToURL(source.Network, source.UserName)
+		rows[i] = []any{
+			source.ID.String(), source.UserID.String(), source.Network, source.UserName, url,
+			source.IsActive, source.SyncStatus, nullTime(source.LastSynced), source.CreatedAt,
+		}
+		keep[source.ID.String()] = true
+	}
+	if err := s.upsert(sourcesTable, rows); err != nil {
+		return err
+	}
+
+	removed, err := s.stale(sourcesTable, "user_id = ?", keep, s.r.Target.UserID.String())
+	if err != nil {
+		return err
+	}
+	if err := s.deleteSources(removed); err != nil {
+		return err
+	}
+
+	log.Printf("Warehouse: upserted %d sources, removed %d", len(rows), len(removed))
+	return nil
+}
+
+// syncPosts copies every post with its latest reaction counts, so edits and
+// archiving show up without waiting for a full refresh.
+func (s *session) syncPosts() error {
+	posts, err := s.r.DB.GetAllPostsWithTheLatestInfoForUser(context.Background(), s.r.Target.UserID)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]any, len(posts))
+	keep := make(map[string]bool, len(posts))
+	for i, post := range posts {
+		var url any
+		if u, err := helpers.ConvPostToURL(post.Network.String, post.Author, post.NetworkInternalID); err == nil {
+			url = u
+		}
+		rows[i] = []any{
+			post.ID.String(), post.SourceID.String(), nullString(post.Network), post.NetworkInternalID,
+			post.PostType, post.Author, nullString(post.Content), url, post.IsArchived,
+			post.CreatedAt, nullInt(post.Likes), nullInt(post.Reposts), nullInt(post.Views),
+			nullTime(post.ReactionsSyncedAt),
+		}
+		keep[post.ID.String()] = true
+	}
+	if err := s.upsert(postsTable, rows); err != nil {
+		return err
+	}
+
+	removed, err := s.stale(postsTable, userSources, keep, s.r.Target.UserID.String())
+	if err != nil {
+		return err
+	}
+	if err := s.deletePosts(removed); err != nil {
+		return err
+	}
+
+	log.Printf("Warehouse: upserted %d posts, removed %d", len(rows), len(removed))
+	return nil
+}
+
+// syncReactions appends the reaction history recorded since the last sync.
+// A full refresh copies the whole history and drops rows the app has
+// cleaned up.
+func (s *session) syncReactions() error {
+	now := time.Now()
+	full := fullRefresh(s.r.Target, now)
+
+	var history []database.PostsReactionsHistory
+	var err error
+	if full {
+		history, err = s.r.DB.BackupGetReactionsForUser(context.Background(), s.r.Target.UserID)
+	} else {
+		// Overlap the previous sync a little; rows are upserted by id.
+		history, err = s.r.DB.GetReactionsForUserSince(context.Background(), database.GetReactionsForUserSinceParams{
+			UserID:   s.r.Target.UserID,
+			SyncedAt: s.r.Target.LastSynced.Time.Add(-time.Hour),
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]any, len(history))
+	keep := make(map[string]bool, len(history))
+	for i, h := range history {
+		rows[i] = []any{
+			h.ID.String(), h.PostID.String(), h.SyncedAt,
+			nullInt(h.Likes), nullInt(h.Reposts), nullInt(h.Views), nullInt(h.Comments),
+		}
+		keep[h.ID.String()] = true
+	}
+	if err := s.upsert(reactionsTable, rows); err != nil {
+		return err
+	}
+
+	var removed []string
+	if full {
+		if removed, err = s.stale(reactionsTable, userPosts, keep, s.r.Target.UserID.String()); err != nil {
+			return err
+		}
+		if err := s.deleteIn("DELETE FROM post_reactions WHERE id IN (%s)", removed); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Warehouse: upserted %d reaction history rows, removed %d", len(rows), len(removed))
+	return nil
+}
+
+func (s *session) syncTags() error {
+	tags, err := s.r.DB.GetTagsForUser(context.Background(), s.r.Target.UserID)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]any, len(tags))
+	keep := make(map[string]bool, len(tags))
+	for i, tag := range tags {
+		rows[i] = []any{
+			tag.ID.String(), tag.UserID.String(), tag.Name, nullString(tag.ClassificationName),
+			tag.CreatedAt, tag.UpdatedAt,
+		}
+		keep[tag.ID.String()] = true
+	}
+	if err := s.upsert(tagsTable, rows); err != nil {
+		return err
+	}
+
+	postTags, err := s.r.DB.BackupGetPostTagsForUser(context.Background(), s.r.Target.UserID)
+	if err != nil {
+		return err
+	}
+
+	linkRows := make([][]any, len(postTags))
+	keepLinks := make(map[string]bool, len(postTags))
+	for i, pt := range postTags {
+		linkRows[i] = []any{pt.ID.String(), pt.PostID.String(), pt.TagID.String(), pt.CreatedAt}
+		keepLinks[pt.ID.String()] = true
+	}
+	if err := s.upsert(postTagsTable, linkRows); err != nil {
+		return err
+	}
+
+	// Post tags go first so none point at a removed tag.
+	removedLinks, err := s.stale(postTagsTable, userPosts, keepLinks, s.r.Target.UserID.String())
+	if err != nil {
+		return err
+	}
+	if err := s.deleteIn("DELETE FROM post_tags WHERE id IN (%s)", removedLinks); err != nil {
+		return err
+	}
+	removed, err := s.stale(tagsTable, "user_id = ?", keep, s.r.Target.UserID.String())
+	if err != nil {
+		return err
+	}
+	if err := s.deleteIn("DELETE FROM tags WHERE id IN (%s)", removed); err != nil {
+		return err
+	}
+
+	log.Printf("Warehouse: upserted %d tags and %d post tags", len(rows), len(linkRows))
+	return nil
+}
+
+// syncStats upserts the stats rows built by row and deletes rows the app no
+// longer has. Outside a full refresh only the last statsUpdateDays days are
+// touched; re-fetched website stats replace older rows under new ids there.
+func (s *session) syncStats(t table, dates []time.Time, row func(i int) []any) error {
+	now := time.Now()
+	full := fullRefresh(s.r.Target, now)
+	threshold := now.AddDate(0, 0, -statsUpdateDays)
+
+	var rows [][]any
+	keep := make(map[string]bool, len(dates))
+	for i, d := range dates {
+		if !full && d.Before(threshold) {
+			continue
+		}
+		r := row(i)
+		rows = append(rows, r)
+		keep[r[0].(string)] = true
+	}
+	if err := s.upsert(t, rows); err != nil {
+		return err
+	}
+
+	where := userSources
+	args := []any{s.r.Target.UserID.String()}
+	if !full {
+		where += " AND date >= ?"
+		args = append(args, s.d.encode(date, threshold))
+	}
+	removed, err := s.stale(t, where, keep, args...)
+	if err != nil {
+		return err
+	}
+	if err := s.deleteIn("DELETE FROM "+t.name+" WHERE id IN (%s)", removed); err != nil {
+		return err
+	}
+
+	log.Printf("Warehouse: upserted %d %s rows, removed %d", len(rows), t.name, len(removed))
+	return nil
+}
+
+func (s *session) syncFollowerStats() error {
+	stats, err := s.r.DB.BackupGetSourcesStatsForUser(context.Background(), s.r.Target.UserID)
+	if err != nil {
+		return err
+	}
+
+	dates := make([]time.Time, len(stats))
+	for i, stat := range stats {
+		dates[i] = stat.Date
+	}
+	return s.syncStats(followerStatsTable, dates, func(i int) []any {
+		stat := stats[i]
+		return []any{
+			stat.ID.String(), stat.SourceID.String(), stat.Date,
+			nullInt(stat.FollowersCount), nullInt(stat.FollowingCount), nullInt(stat.PostsCount),
+			nullInt(stat.FollowersDelta), nullInt(stat.FollowingDelta), nullInt(stat.Karma),
+			nullFloat(stat.AverageLikes), nullFloat(stat.AverageReposts), nullFloat(stat.AverageViews),
+		}
+	})
+}
+
+func (s *session) syncSiteStats() error {
+	stats, err := s.r.DB.BackupGetAnalyticsSiteStatsForUser(context.Background(), s.r.Target.UserID)
+	if err != nil {
+		return err
+	}
+
+	dates := make([]time.Time, len(stats))
+	for i, stat := range stats {
+		dates[i] = stat.Date
+	}
+	return s.syncStats(siteStatsTable, dates, func(i int) []any {
+		stat := stats[i]
+		return []any{
+			stat.ID.String(), stat.SourceID.String(), stat.Date, stat.AnalyticsType,
+			stat.Visitors, stat.AvgSessionDuration, nullInt(stat.Impressions),
+		}
+	})
+}
+
+func (s *session) syncPageStats() error {
+	stats, err := s.r.DB.BackupGetAnalyticsPageStatsForUser(context.Background(), s.r.Target.UserID)
+	if err != nil {
+		return err
+	}
+
+	dates := make([]time.Time, len(stats))
+	for i, stat := range stats {
+		dates[i] = stat.Date
+	}
+	return s.syncStats(pageStatsTable, dates, func(i int) []any {
+		stat := stats[i]
+		return []any{
+			stat.ID.String(), stat.SourceID.String(), stat.Date, stat.AnalyticsType,
+			stat.UrlPath, stat.Views, nullInt(stat.Impressions),
+		}
+	})
+}
@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/authhelp"
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/pusher/targets"
+	"github.com/google/uuid"
+)
+
+const (
+	maxAttempts = 5
+	// maxRetryDelay caps the Retry-After a receiver may ask for.
+	maxRetryDelay = time.Minute
+)
+
+// errRefused is returned when the receiver answered with a 4xx status other
+// than 429. Sending the event again would be refused the same way.
+var errRefused = errors.New("refused by the receiver")
+
+type client struct {
+	r          targets.Run
+	secret     []byte
+	milestones []int64
+}
+
+func newClient(r targets.Run) (*client, error) {
+	secret, _, _, err := authhelp.GetTargetToken(context.Background(), r.DB, r.EncryptionKey, r.Target.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signing secret: %w", err)
+	}
+	milestones, err := parseMilestones(r.Target.DbID.String)
+	if err != nil {
+		return nil, err
+	}
+	return &client{r: r, secret: []byte(secret), milestones: milestones}, nil
+}
+
+type event struct {
+	ID        uuid.UUID `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// send delivers one event and logs the delivery. It returns an error once
+// all attempts failed, so the event is sent again on the next sync. Refused
+// events return their ID along with an error wrapping errRefused.
+func (c *client) send(eventType string, data any) (uuid.UUID, error) {
+	e := event{ID: uuid.New(), Type: eventType, CreatedAt: time.Now().UTC(), Data: data}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("marshal %s event: %w", eventType, err)
+	}
+
+	attempts, code, postErr := c.post(e, payload)
+
+	delivery := database.CreateWebhookDeliveryParams{
+		ID:        e.ID,
+		TargetID:  c.r.Target.ID,
+		CreatedAt: e.CreatedAt,
+		EventType: eventType,
+		Payload:   payload,
+		Status:    "Delivered",
+		Attempts:  int32(attempts),
+	}
+	if code != 0 {
+		delivery.ResponseCode = sql.NullInt32{Int32: int32(code), Valid: true}
+	}
+	if postErr != nil {
+		delivery.Status = "Failed"
+		if errors.Is(postErr, errRefused) {
+			delivery.Status = "Refused"
+		}
+		delivery.Error = sql.NullString{String: postErr.Error(), Valid: true}
+	}
+	if _, err := c.r.DB.CreateWebhookDelivery(context.Background(), delivery); err != nil {
+		log.Printf("Warning: failed to log webhook delivery %s: %v", e.ID, err)
+	}
+
+	if errors.Is(postErr, errRefused) {
+		return e.ID, fmt.Errorf("failed to deliver %s event %s: %w", eventType, e.ID, postErr)
+	}
+	if postErr != nil {
+		return uuid.UUID{}, fmt.Errorf("failed to deliver %s event: %w", eventType, postErr)
+	}
+	return e.ID, nil
+}
+
+// post sends the payload, retrying network errors, 429 and 5xx responses
+// with exponential backoff. It returns the attempts made and the last status
+// code.
+func (c *client) post(e event, payload []byte) (int, int, error) {
+	timestamp := strconv.FormatInt(e.CreatedAt.Unix(), 10)
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	code := 0
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequest("POST", c.r.Target.HostUrl.String, bytes.NewReader(payload))
+		if err != nil {
+			return attempt, 0, fmt.Errorf("create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Rpsync-Event", e.Type)
+		req.Header.Set("X-Rpsync-Delivery", e.ID.String())
+		req.Header.Set("X-Rpsync-Timestamp", timestamp)
+		req.Header.Set("X-Rpsync-Signature", signature)
+
+		delay := time.Duration(1<<(attempt-1)) * time.Second
+		resp, err := c.r.Client.HTTPClient.Do(req)
+		if err != nil {
+			err = fmt.Errorf("send request: %w", err)
+		} else {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+			resp.Body.Close()
+			code = resp.StatusCode
+
+			switch {
+			case code >= 200 && code <= 299:
+				return attempt, code, nil
+			case code >= 400 && code < 500 && code != http.StatusTooManyRequests:
+				return attempt, code, fmt.Errorf("%w: status code %d, body: %s", errRefused, code, string(body))
+			case code < 400:
+				return attempt, code, fmt.Errorf("unexpected status code: %d, body: %s", code, string(body))
+			}
+			err = fmt.Errorf("unexpected status code: %d, body: %s", code, string(body))
+			if secs, perr := strconv.Atoi(resp.Header.Get("Retry-After")); perr == nil && secs > 0 {
+				delay = min(time.Duration(secs)*time.Second, maxRetryDelay)
+			}
+		}
+
+		if attempt == maxAttempts {
+			return attempt, code, err
+		}
+		log.Printf("Webhook delivery %s failed (%v), retrying in %s", e.ID, err, delay)
+		time.Sleep(delay)
+	}
+}
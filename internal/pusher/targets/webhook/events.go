@@ -0,0 +1,429 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/helpers"
+	"github.com/google/uuid"
+)
+
+const (
+	eventPostDiscovered    = "post.discovered"
+	eventReactionThreshold = "post.reaction_threshold"
+	eventFollowerMilestone = "source.follower_milestone"
+	eventDailySummary      = "stats.daily_summary"
+
+	metricFollowers = "followers"
+	metricLikes     = "likes"
+)
+
+// parseMilestones reads the comma separated milestones of the target. None
+// means the default 10, 25, 50, 100, 250, 500, ... ladder.
+func parseMilestones(s string) ([]int64, error) {
+	var milestones []int64
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid milestone %q, use positive numbers separated by commas", part)
+		}
+		milestones = append(milestones, n)
+	}
+	return milestones, nil
+}
+
+// reached returns the highest milestone at or below v, or 0.
+func (c *client) reached(v int64) int64 {
+	var best int64
+	if len(c.milestones) == 0 {
+		for base := int64(10); base <= v; base *= 10 {
+			for _, m := range []int64{base, base * 5 / 2, base * 5} {
+				if m <= v {
+					best = m
+				}
+			}
+		}
+		return best
+	}
+	for _, m := range c.milestones {
+		if m <= v && m > best {
+			best = m
+		}
+	}
+	return best
+}
+
+type milestoneKey struct {
+	subject uuid.UUID
+	metric  string
+}
+
+func (c *client) loadMilestones() (map[milestoneKey]int64, error) {
+	rows, err := c.r.DB.GetWebhookMilestones(context.Background(), c.r.Target.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching milestones: %w", err)
+	}
+	marks := make(map[milestoneKey]int64, len(rows))
+	for _, m := range rows {
+		marks[milestoneKey{m.SubjectID, m.Metric}] = m.Milestone
+	}
+	return marks, nil
+}
+
+func (c *client) setMilestone(subject uuid.UUID, metric string, milestone int64) error {
+	return c.r.DB.SetWebhookMilestone(context.Background(), database.SetWebhookMilestoneParams{
+		TargetID:  c.r.Target.ID,
+		SubjectID: subject,
+		Metric:    metric,
+		Milestone: milestone,
+	})
+}
+
+type sourceData struct {
+	ID       uuid.UUID `json:"id"`
+	Network  string    `json:"network"`
+	Username string    `json:"username"`
+	URL      string    `json:"url,omitempty"`
+}
+
+func newSourceData(source database.Source) sourceData {
+	url, _ := helpers.ConvNetworkToURL(source.Network, source.UserName)
+	return sourceData{ID: source.ID, Network: source.Network, Username: source.UserName, URL: url}
+}
+
+type postData struct {
+	ID         uuid.UUID  `json:"id"`
+	Source     sourceData `json:"source"`
+	PostType   string     `json:"post_type"`
+	Author     string     `json:"author"`
+	Content    string     `json:"content"`
+	URL        string     `json:"url,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	IsArchived bool       `json:"is_archived"`
+	Likes      *int64     `json:"likes"`
+	Reposts    *int64     `json:"reposts"`
+	Views      *int64     `json:"views"`
+}
+
+func nullInt(v sql.NullInt64) *int64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Int64
+}
+
+func newPostData(post database.GetAllPostsWithTheLatestInfoForUserRow, source database.Source) postData {
+	url, _ := helpers.ConvPostToURL(post.Network.String, post.Author, post.NetworkInternalID)
+	return postData{
+		ID:         post.ID,
+		Source:     newSourceData(source),
+		PostType:   post.PostType,
+		Author:     post.Author,
+		Content:    post.Content.String,
+		URL:        url,
+		CreatedAt:  post.CreatedAt,
+		IsArchived: post.IsArchived,
+		Likes:      nullInt(post.Likes),
+		Reposts:    nullInt(post.Reposts),
+		Views:      nullInt(post.Views),
+	}
+}
+
+// skipRefused lets a sync go on past an event the receiver refused. The
+// failed delivery is logged and the event is recorded as sent, as it would
+// be refused again on every sync.
+func skipRefused(err error) error {
+	if errors.Is(err, errRefused) {
+		log.Printf("Warning: %v", err)
+		return nil
+	}
+	return err
+}
+
+// sendFollowerMilestones announces sources whose follower count reached a
+// new milestone. The first time a source is seen its current milestone is
+// only recorded.
+func (c *client) sendFollowerMilestones() error {
+	sources, err := c.r.DB.GetUserSources(context.Background(), c.r.Target.UserID)
+	if err != nil {
+		return fmt.Errorf("error fetching user sources: %w", err)
+	}
+	marks, err := c.loadMilestones()
+	if err != nil {
+		return err
+	}
+
+	for _, source := range sources {
+		stats, err := c.r.DB.GetSourceStatsSince(context.Background(), database.GetSourceStatsSinceParams{
+			SourceID: source.ID,
+			Date:     time.Now().AddDate(0, 0, -7),
+		})
+		if err != nil {
+			return err
+		}
+		var followers sql.NullInt64
+		for _, stat := range stats {
+			if stat.FollowersCount.Valid {
+				followers = stat.FollowersCount
+			}
+		}
+		if !followers.Valid {
+			continue
+		}
+
+		reached := c.reached(followers.Int64)
+		prev, seen := marks[milestoneKey{source.ID, metricFollowers}]
+		if seen && reached <= prev {
+			continue
+		}
+		if seen {
+			if _, err := c.send(eventFollowerMilestone, map[string]any{
+				"source":          newSourceData(source),
+				"milestone":       reached,
+				"followers_count": followers.Int64,
+			}); skipRefused(err) != nil {
+				return err
+			}
+		}
+		if err := c.setMilestone(source.ID, metricFollowers, reached); err != nil {
+			return fmt.Errorf("failed to save milestone: %w", err)
+		}
+	}
+	return nil
+}
+
+// sendPostEvents announces posts not seen before and posts whose likes
+// reached a new milestone. A source's first synced posts are only recorded,
+// so adding an account doesn't announce its whole history.
+func (c *client) sendPostEvents() error {
+	sources, err := c.r.DB.GetUserSources(context.Background(), c.r.Target.UserID)
+	if err != nil {
+		return fmt.Errorf("error fetching user sources: %w", err)
+	}
+	mappedSources, err := c.r.DB.GetTargetSources(context.Background(), c.r.Target.ID)
+	if err != nil {
+		return fmt.Errorf("error fetching mapped sources: %w", err)
+	}
+	known := make(map[uuid.UUID]bool, len(mappedSources))
+	for _, m := range mappedSources {
+		known[m.SourceID] = true
+	}
+	// Sources are only known once they have been synced; until then they
+	// have no posts to record.
+	byID := make(map[uuid.UUID]database.Source, len(sources))
+	for _, source := range sources {
+		if source.LastSynced.Valid {
+			byID[source.ID] = source
+		}
+	}
+
+	posts, err := c.r.DB.GetAllPostsWithTheLatestInfoForUser(context.Background(), c.r.Target.UserID)
+	if err != nil {
+		return err
+	}
+	mappedPosts, err := c.r.DB.GetPostsPreviouslySynced(context.Background(), c.r.Target.ID)
+	if err != nil {
+		return fmt.Errorf("error fetching mapped posts: %w", err)
+	}
+	announced := make(map[uuid.UUID]bool, len(mappedPosts))
+	for _, m := range mappedPosts {
+		if !m.PostID.Valid {
+			if err := c.r.DB.DeletePostOnTarget(context.Background(), m.ID); err != nil {
+				log.Printf("Warning: Failed to delete posts_on_target mapping: %v", err)
+			}
+			continue
+		}
+		announced[m.PostID.UUID] = true
+	}
+	marks, err := c.loadMilestones()
+	if err != nil {
+		return err
+	}
+
+	// Oldest first, so receivers get new posts in the order they were made.
+	sort.SliceStable(posts, func(i, j int) bool { return posts[i].CreatedAt.Before(posts[j].CreatedAt) })
+
+	sent := 0
+	for _, post := range posts {
+		source, ok := byID[post.SourceID]
+		if !ok {
+			continue
+		}
+		reached := c.reached(post.Likes.Int64)
+
+		if !announced[post.ID] {
+			// The mapping holds the id of the post.discovered event, or
+			// nothing for posts recorded silently.
+			var eventID string
+			if known[source.ID] {
+				id, err := c.send(eventPostDiscovered, newPostData(post, source))
+				if skipRefused(err) != nil {
+					return err
+				}
+				eventID = id.String()
+				sent++
+			}
+			if _, err := c.r.DB.AddPostToTarget(context.Background(), database.AddPostToTargetParams{
+				ID:            uuid.New(),
+				FirstSyncedAt: time.Now(),
+				PostID:        uuid.NullUUID{UUID: post.ID, Valid: true},
+				TargetID:      c.r.Target.ID,
+				TargetPostID:  eventID,
+			}); err != nil {
+				return fmt.Errorf("failed to map post: %w", err)
+			}
+			if err := c.setMilestone(post.ID, metricLikes, reached); err != nil {
+				return fmt.Errorf("failed to save milestone: %w", err)
+			}
+			continue
+		}
+
+		prev, seen := marks[milestoneKey{post.ID, metricLikes}]
+		if seen && reached <= prev {
+			continue
+		}
+		if seen {
+			if _, err := c.send(eventReactionThreshold, map[string]any{
+				"post":      newPostData(post, source),
+				"metric":    metricLikes,
+				"milestone": reached,
+			}); skipRefused(err) != nil {
+				return err
+			}
+			sent++
+		}
+		if err := c.setMilestone(post.ID, metricLikes, reached); err != nil {
+			return fmt.Errorf("failed to save milestone: %w", err)
+		}
+	}
+
+	for id := range byID {
+		if known[id] {
+			continue
+		}
+		if _, err := c.r.DB.AddSourceToTarget(context.Background(), database.AddSourceToTargetParams{
+			ID:             uuid.New(),
+			SourceID:       id,
+			TargetID:       c.r.Target.ID,
+			TargetSourceID: id.String(),
+		}); err != nil {
+			return fmt.Errorf("failed to map source: %w", err)
+		}
+	}
+
+	log.Printf("Webhook: sent %d post events", sent)
+	return nil
+}
+
+type summarySource struct {
+	sourceData
+	FollowersCount *int64 `json:"followers_count"`
+	FollowingCount *int64 `json:"following_count"`
+	PostsCount     *int64 `json:"posts_count"`
+	FollowersDelta *int64 `json:"followers_delta"`
+	NewPosts       int    `json:"new_posts"`
+	Visitors       *int64 `json:"visitors,omitempty"`
+	Impressions    *int64 `json:"impressions,omitempty"`
+}
+
+// sendDailySummary sends yesterday's stats once a day, with the first sync
+// after midnight.
+func (c *client) sendDailySummary() error {
+	now := time.Now()
+	last, err := c.r.DB.GetLastSentWebhookEvent(context.Background(), database.GetLastSentWebhookEventParams{
+		TargetID:  c.r.Target.ID,
+		EventType: eventDailySummary,
+	})
+	switch {
+	case err == nil:
+		y1, m1, d1 := last.CreatedAt.Local().Date()
+		y2, m2, d2 := now.Date()
+		if y1 == y2 && m1 == m2 && d1 == d2 {
+			return nil
+		}
+	case !errors.Is(err, sql.ErrNoRows):
+		return err
+	}
+
+	y, m, d := now.AddDate(0, 0, -1).Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+	sources, err := c.r.DB.GetUserSources(context.Background(), c.r.Target.UserID)
+	if err != nil {
+		return fmt.Errorf("error fetching user sources: %w", err)
+	}
+	posts, err := c.r.DB.GetAllPostsWithTheLatestInfoForUser(context.Background(), c.r.Target.UserID)
+	if err != nil {
+		return err
+	}
+	newPosts := make(map[uuid.UUID]int)
+	for _, post := range posts {
+		py, pm, pd := post.CreatedAt.Date()
+		if py == y && pm == m && pd == d {
+			newPosts[post.SourceID]++
+		}
+	}
+
+	summary := make([]summarySource, 0, len(sources))
+	for _, source := range sources {
+		s := summarySource{sourceData: newSourceData(source), NewPosts: newPosts[source.ID]}
+
+		stat, err := c.r.DB.GetSourceStatsByDate(context.Background(), database.GetSourceStatsByDateParams{
+			SourceID: source.ID,
+			Date:     day,
+		})
+		switch {
+		case err == nil:
+			s.FollowersCount = nullInt(stat.FollowersCount)
+			s.FollowingCount = nullInt(stat.FollowingCount)
+			s.PostsCount = nullInt(stat.PostsCount)
+			s.FollowersDelta = nullInt(stat.FollowersDelta)
+		case !errors.Is(err, sql.ErrNoRows):
+			return err
+		}
+
+		siteStats, err := c.r.DB.GetAnalyticsSiteStatsBySourceAndRange(context.Background(), database.GetAnalyticsSiteStatsBySourceAndRangeParams{
+			SourceID: source.ID,
+			Date:     day,
+			Date_2:   day,
+		})
+		if err != nil {
+			return err
+		}
+		for _, site := range siteStats {
+			visitors := site.Visitors
+			if s.Visitors != nil {
+				visitors += *s.Visitors
+			}
+			s.Visitors = &visitors
+			if site.Impressions.Valid {
+				impressions := site.Impressions.Int64
+				if s.Impressions != nil {
+					impressions += *s.Impressions
+				}
+				s.Impressions = &impressions
+			}
+		}
+
+		summary = append(summary, s)
+	}
+
+	_, err = c.send(eventDailySummary, map[string]any{
+		"date":    day.Format("2006-01-02"),
+		"sources": summary,
+	})
+	return skipRefused(err)
+}
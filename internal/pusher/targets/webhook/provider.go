@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/fluffyriot/rpsync/internal/helpers"
+	"github.com/fluffyriot/rpsync/internal/pusher/targets"
+)
+
+func init() {
+	targets.Register(targets.Provider{
+		Network: helpers.TargetNetwork{
+			Name:  "Webhook",
+			Color: "#6b7280",
+			Fields: []helpers.TargetField{
+				{Name: "host_url", Label: "Webhook URL", Placeholder: "https://n8n.example.com/webhook/rpsync"},
+				{Name: "api_token", Label: "Signing Secret", Placeholder: "openssl rand -hex 32"},
+				{Name: "db_id", Label: "Milestones", Placeholder: "100, 1000, 10000 (default: 10, 25, 50, 100, 250, ...)", Optional: true},
+			},
+		},
+		Target: Webhook{},
+		// Moved posts keep their mappings and milestones, so a merge does
+		// not announce them again.
+		KeepsMergedPosts: true,
+	})
+}
+
+// deliveryRetention is how long the delivery history is kept.
+const deliveryRetention = 30 * 24 * time.Hour
+
+// Webhook POSTs a signed JSON event for each new post, post and follower
+// milestone and daily summary. What was announced is kept in the post and
+// source mappings and in webhook_milestones; every attempt is logged in
+// webhook_deliveries.
+type Webhook struct{}
+
+func (Webhook) Initialize(r targets.Run) error {
+	u, err := url.Parse(r.Target.HostUrl.String)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return errors.New("a webhook URL starting with http:// or https:// is required")
+	}
+	if _, err := parseMilestones(r.Target.DbID.String); err != nil {
+		return err
+	}
+
+	if err := r.DB.DeleteWebhookDeliveriesBefore(context.Background(), database.DeleteWebhookDeliveriesBeforeParams{
+		TargetID:  r.Target.ID,
+		CreatedAt: time.Now().UTC().Add(-deliveryRetention),
+	}); err != nil {
+		log.Printf("Warning: failed to delete old webhook deliveries: %v", err)
+	}
+	return nil
+}
+
+// PushSources has nothing to send; sources are announced through their
+// posts and milestones.
+func (Webhook) PushSources(r targets.Run) error {
+	return nil
+}
+
+// PushAnalytics has nothing to send; website stats are part of the daily
+// summary.
+func (Webhook) PushAnalytics(r targets.Run) error {
+	return nil
+}
+
+func (Webhook) PushStats(r targets.Run) error {
+	c, err := newClient(r)
+	if err != nil {
+		return err
+	}
+	return c.sendFollowerMilestones()
+}
+
+func (Webhook) PushPosts(r targets.Run) error {
+	c, err := newClient(r)
+	if err != nil {
+		return err
+	}
+	if err := c.sendPostEvents(); err != nil {
+		return err
+	}
+	return c.sendDailySummary()
+}
+
+func (Webhook) RemoveSource(r targets.Run, source database.Source) error {
+	if err := r.DB.DeleteWebhookMilestonesForSource(context.Background(), database.DeleteWebhookMilestonesForSourceParams{
+		TargetID:  r.Target.ID,
+		SubjectID: source.ID,
+	}); err != nil {
+		return fmt.Errorf("failed to delete milestones: %w", err)
+	}
+	if err := r.DB.DeletePostsOnTargetAndSource(context.Background(), database.DeletePostsOnTargetAndSourceParams{
+		TargetID: r.Target.ID,
+		SourceID: source.ID,
+	}); err != nil {
+		return err
+	}
+	return r.DB.DeleteSourceTarget(context.Background(), database.DeleteSourceTargetParams{
+		TargetID: r.Target.ID,
+		SourceID: source.ID,
+	})
+}
@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package targets
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// maxSheetRows is the number of rows an Excel sheet holds, header included.
+const maxSheetRows = 1048576
+
+// writeWorkbook writes an XLSX workbook with one sheet per dataset. Numbers
+// and booleans are typed cells and timestamps are UTC date cells.
+func writeWorkbook(w io.Writer, tables []table) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	timeFormat, dateFormat := "yyyy-mm-dd hh:mm:ss", "yyyy-mm-dd"
+	timeStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &timeFormat})
+	if err != nil {
+		return err
+	}
+	dateStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &dateFormat})
+	if err != nil {
+		return err
+	}
+
+	for i, s := range tables {
+		if len(s.rows) >= maxSheetRows {
+			return fmt.Errorf("%s has %d rows, more than an XLSX sheet holds", s.d.label, len(s.rows))
+		}
+
+		name := s.d.label
+		if i == 0 {
+			err = f.SetSheetName("Sheet1", name)
+		} else {
+			_, err = f.NewSheet(name)
+		}
+		if err != nil {
+			return err
+		}
+
+		sw, err := f.NewStreamWriter(name)
+		if err != nil {
+			return err
+		}
+
+		cells := make([]any, len(s.cols))
+		for j, c := range s.cols {
+			cells[j] = s.d.columns[c].name
+		}
+		if err := sw.SetRow("A1", cells); err != nil {
+			return err
+		}
+
+		for r, row := range s.rows {
+			for j, c := range s.cols {
+				v := row[c]
+				if t, ok := v.(time.Time); ok {
+					style := timeStyle
+					if s.d.columns[c].kind == kindDate {
+						style = dateStyle
+					}
+					v = excelize.Cell{StyleID: style, Value: t.UTC()}
+				}
+				cells[j] = v
+			}
+			cell, err := excelize.CoordinatesToCellName(1, r+2)
+			if err != nil {
+				return err
+			}
+			if err := sw.SetRow(cell, cells); err != nil {
+				return err
+			}
+		}
+
+		if err := sw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return f.Write(w)
+}
@@ -124,6 +124,7 @@ type SummaryChart struct {
 type DashboardSummary struct {
 	Engagement SummaryChart `json:"engagement"`
 	Followers  SummaryChart `json:"followers"`
+	Supporters SummaryChart `json:"supporters"`
 }
 
 func GetDashboardSummary(dbQueries *database.Queries, userID uuid.UUID) (*DashboardSummary, error) {
@@ -132,8 +133,9 @@ func GetDashboardSummary(dbQueries *database.Queries, userID uuid.UUID) (*Dashbo
 	startDate := now.AddDate(0, 0, -13)
 
 	var (
-		engStats      []database.GetTotalDailyEngagementStatsRow
-		followerStats []database.GetTotalDailyFollowerStatsRow
+		engStats       []database.GetTotalDailyEngagementStatsRow
+		followerStats  []database.GetTotalDailyFollowerStatsRow
+		supporterStats []database.GetTotalDailySupporterStatsRow
 	)
 
 	g, ctx := errgroup.WithContext(ctx)
@@ -158,6 +160,16 @@ func GetDashboardSummary(dbQueries *database.Queries, userID uuid.UUID) (*Dashbo
 		return err
 	})
 
+	g.Go(func() error {
+		var err error
+		supporterStats, err = dbQueries.GetTotalDailySupporterStats(ctx, database.GetTotalDailySupporterStatsParams{
+			UserID:  userID,
+			Column2: startDate,
+			Column3: now,
+		})
+		return err
+	})
+
 	if err := g.Wait(); err != nil {
 		return nil, err
 	}
@@ -171,6 +183,10 @@ func GetDashboardSummary(dbQueries *database.Queries, userID uuid.UUID) (*Dashbo
 			CurrentPeriod:  make([]ChartPoint, 0),
 			PreviousPeriod: make([]ChartPoint, 0),
 		},
+		Supporters: SummaryChart{
+			CurrentPeriod:  make([]ChartPoint, 0),
+			PreviousPeriod: make([]ChartPoint, 0),
+		},
 	}
 
 	for i, stat := range engStats {
@@ -197,5 +213,17 @@ func GetDashboardSummary(dbQueries *database.Queries, userID uuid.UUID) (*Dashbo
 		}
 	}
 
+	for i, stat := range supporterStats {
+		point := ChartPoint{
+			Date:  stat.PeriodDate.Format("2006-01-02"),
+			Value: int64(stat.TotalSupporters),
+		}
+		if i < 7 {
+			summary.Supporters.PreviousPeriod = append(summary.Supporters.PreviousPeriod, point)
+		} else {
+			summary.Supporters.CurrentPeriod = append(summary.Supporters.CurrentPeriod, point)
+		}
+	}
+
 	return summary, nil
 }
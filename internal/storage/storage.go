@@ -0,0 +1,231 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+// Package storage uploads export files and backups to a remote destination
+// set up by the user: an S3-compatible bucket or a WebDAV share.
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/authhelp"
+	"github.com/fluffyriot/rpsync/internal/database"
+	"github.com/google/uuid"
+)
+
+const (
+	TypeS3     = "S3"
+	TypeWebDAV = "WebDAV"
+)
+
+// outputsDir is where exports and backups are written before they are
+// uploaded, and where kept local copies stay.
+const outputsDir = "outputs"
+
+// store is one kind of remote destination. Keys are slash separated paths
+// below the destination's base URL.
+type store interface {
+	baseURL() string
+	put(ctx context.Context, key string, body io.ReadSeeker, size int64) error
+	get(ctx context.Context, key string) (io.ReadCloser, error)
+	delete(ctx context.Context, key string) error
+}
+
+// Destination is the remote storage of one user.
+type Destination struct {
+	cfg   database.ExportStorage
+	store store
+}
+
+// New builds a destination from its settings and decrypted secret.
+func New(cfg database.ExportStorage, secret string) (*Destination, error) {
+	if _, err := parseEndpoint(cfg.Endpoint); err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: 30 * time.Minute}
+	d := &Destination{cfg: cfg}
+	switch cfg.StorageType {
+	case TypeS3:
+		if cfg.Bucket == "" {
+			return nil, errors.New("a bucket is required")
+		}
+		d.store = newS3Store(client, cfg, secret)
+	case TypeWebDAV:
+		d.store = newWebDAVStore(client, cfg, secret)
+	default:
+		return nil, fmt.Errorf("storage type %q not supported", cfg.StorageType)
+	}
+	return d, nil
+}
+
+// Load returns the destination of a user, or nil when none is set up.
+func Load(ctx context.Context, db *database.Queries, encryptionKey []byte, userID uuid.UUID) (*Destination, error) {
+	cfg, err := db.GetExportStorage(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remote storage: %w", err)
+	}
+
+	secret, err := authhelp.DecryptSecret(cfg.EncryptedSecret, cfg.Nonce, encryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt remote storage secret: %w", err)
+	}
+	return New(cfg, secret)
+}
+
+func (d *Destination) url(key string) string {
+	return d.store.baseURL() + encodePath(key)
+}
+
+// Key returns the key of a download URL pointing into the destination.
+func (d *Destination) Key(downloadURL string) (string, bool) {
+	rest, ok := strings.CutPrefix(downloadURL, d.store.baseURL())
+	if !ok || rest == "" {
+		return "", false
+	}
+	key, err := decodePath(rest)
+	if err != nil {
+		return "", false
+	}
+	return key, true
+}
+
+func (d *Destination) key(name string) string {
+	prefix := strings.Trim(d.cfg.PathPrefix, "/")
+	if prefix == "" {
+		return name
+	}
+	return path.Join(prefix, name)
+}
+
+// Upload copies a local file to the destination and returns its URL. The
+// local copy is removed unless the user keeps local copies.
+func (d *Destination) Upload(ctx context.Context, localPath string) (string, error) {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	key := d.key(filepath.Base(localPath))
+	if err := d.store.put(ctx, key, file, info.Size()); err != nil {
+		return "", fmt.Errorf("upload %s: %w", key, err)
+	}
+
+	if !d.cfg.KeepLocal {
+		file.Close()
+		if err := os.Remove(localPath); err != nil {
+			log.Printf("Warning: failed to remove uploaded export %s: %v", localPath, err)
+		}
+	}
+	return d.url(key), nil
+}
+
+// Open streams a file from the destination.
+func (d *Destination) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return d.store.get(ctx, key)
+}
+
+// Delete removes a file from the destination along with its local copy, if
+// one was kept. Missing files are not an error.
+func (d *Destination) Delete(ctx context.Context, key string) error {
+	local := filepath.Join(outputsDir, path.Base(key))
+	if err := os.Remove(local); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Printf("Warning: failed to remove local copy %s: %v", local, err)
+	}
+	return d.store.delete(ctx, key)
+}
+
+// Test writes and deletes a small file to check the settings.
+func (d *Destination) Test(ctx context.Context) error {
+	key := d.key("rpsync-connection-test.txt")
+	body := strings.NewReader("rpsync connection test\n")
+	if err := d.store.put(ctx, key, body, body.Size()); err != nil {
+		return fmt.Errorf("test upload failed: %w", err)
+	}
+	if err := d.store.delete(ctx, key); err != nil {
+		return fmt.Errorf("test delete failed: %w", err)
+	}
+	return nil
+}
+
+// Prune deletes uploaded exports older than the retention period, both the
+// files and the export log. Exports that fail to delete are logged and kept
+// for the next run.
+func (d *Destination) Prune(ctx context.Context, db *database.Queries) error {
+	if d.cfg.RetentionDays <= 0 {
+		return nil
+	}
+
+	old, err := db.GetExportsWithDownloadPrefixBefore(ctx, database.GetExportsWithDownloadPrefixBeforeParams{
+		UserID:    d.cfg.UserID,
+		CreatedAt: time.Now().AddDate(0, 0, -int(d.cfg.RetentionDays)),
+		UrlPrefix: d.store.baseURL(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get expired exports: %w", err)
+	}
+
+	for _, export := range old {
+		if key, ok := d.Key(export.DownloadUrl.String); ok {
+			if err := d.Delete(ctx, key); err != nil {
+				log.Printf("Warning: failed to delete expired export %s: %v", export.ID, err)
+				continue
+			}
+		}
+		if err := db.DeleteExportById(ctx, export.ID); err != nil {
+			log.Printf("Warning: failed to delete export log %s: %v", export.ID, err)
+		}
+	}
+	return nil
+}
+
+// Publish uploads a finished export when the user has remote storage and
+// returns the download URL to record: the remote URL, or localPath when
+// there is no destination. On error localPath is returned with it, so the
+// export stays downloadable.
+func Publish(db *database.Queries, encryptionKey []byte, userID uuid.UUID, localPath string) (string, error) {
+	if localPath == "" {
+		return "", nil
+	}
+
+	ctx := context.Background()
+	d, err := Load(ctx, db, encryptionKey, userID)
+	if err != nil || d == nil {
+		return localPath, err
+	}
+
+	url, err := d.Upload(ctx, localPath)
+	if err != nil {
+		return localPath, err
+	}
+	if err := d.Prune(ctx, db); err != nil {
+		log.Printf("Warning: failed to apply export retention: %v", err)
+	}
+	return url, nil
+}
+
+// IsRemote reports whether a download URL points to remote storage rather
+// than the outputs directory.
+func IsRemote(downloadURL string) bool {
+	return strings.HasPrefix(downloadURL, "https://") || strings.HasPrefix(downloadURL, "http://")
+}
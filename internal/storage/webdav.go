@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/fluffyriot/rpsync/internal/database"
+)
+
+// webdavStore uploads to a WebDAV share such as Nextcloud, creating the
+// folders of the path prefix on first use.
+type webdavStore struct {
+	client   *http.Client
+	base     string
+	username string
+	password string
+	folders  map[string]bool
+}
+
+func newWebDAVStore(client *http.Client, cfg database.ExportStorage, secret string) *webdavStore {
+	return &webdavStore{
+		client:   client,
+		base:     strings.TrimRight(cfg.Endpoint, "/") + "/",
+		username: cfg.AccessKey,
+		password: secret,
+		folders:  map[string]bool{},
+	}
+}
+
+func (s *webdavStore) baseURL() string { return s.base }
+
+func (s *webdavStore) put(ctx context.Context, key string, body io.ReadSeeker, size int64) error {
+	if err := s.mkdirAll(ctx, path.Dir(key)); err != nil {
+		return err
+	}
+
+	resp, err := s.do(ctx, http.MethodPut, encodePath(key), body, size)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *webdavStore) get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, encodePath(key), nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *webdavStore) delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, encodePath(key), nil, 0)
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// mkdirAll creates each folder of dir. Servers answer 405 for folders that
+// already exist.
+func (s *webdavStore) mkdirAll(ctx context.Context, dir string) error {
+	if dir == "." || dir == "/" || s.folders[dir] {
+		return nil
+	}
+
+	current := ""
+	for _, part := range strings.Split(dir, "/") {
+		current = path.Join(current, part)
+		if s.folders[current] {
+			continue
+		}
+		resp, err := s.do(ctx, "MKCOL", encodePath(current)+"/", nil, 0)
+		if err != nil {
+			if se, ok := err.(*statusError); !ok || se.code != http.StatusMethodNotAllowed {
+				return err
+			}
+		} else {
+			resp.Body.Close()
+		}
+		s.folders[current] = true
+	}
+	return nil
+}
+
+func (s *webdavStore) do(ctx context.Context, method, escapedPath string, body io.Reader, size int64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.base+escapedPath, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	if s.username != "" || s.password != "" {
+		req.SetBasicAuth(s.username, s.password)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/fetcher"
+	"github.com/google/uuid"
+)
+
+const (
+	livePollInterval    = 1 * time.Minute
+	offlinePollInterval = 5 * time.Minute
+)
+
+func (w *Worker) spawnLivePoller() {
+	ticker := time.NewTicker(livePollInterval)
+	defer ticker.Stop()
+
+	lastPolled := make(map[uuid.UUID]time.Time)
+	live := make(map[uuid.UUID]bool)
+
+	for {
+		select {
+		case <-ticker.C:
+			w.pollLiveSources(lastPolled, live)
+		case <-w.StopChan:
+			return
+		}
+	}
+}
+
+func (w *Worker) pollLiveSources(lastPolled map[uuid.UUID]time.Time, live map[uuid.UUID]bool) {
+	ctx := context.Background()
+
+	for _, network := range fetcher.LiveNetworks {
+		sources, err := w.DB.GetActiveSourcesByNetwork(ctx, network)
+		if err != nil {
+			log.Printf("Worker: failed to get %s sources for live polling: %v", network, err)
+			continue
+		}
+
+		for _, source := range sources {
+			if !live[source.ID] && time.Since(lastPolled[source.ID]) < offlinePollInterval {
+				continue
+			}
+			lastPolled[source.ID] = time.Now()
+
+			isLive, err := fetcher.PollLiveBySource(source, w.DB, w.Fetcher, w.Config.TokenEncryptionKey)
+			if err != nil {
+				log.Printf("Worker: live poll failed (source=%s): %v", source.ID, err)
+				continue
+			}
+			live[source.ID] = isLive
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/fluffyriot/rpsync/internal/fetcher"
+)
+
+const storyPollInterval = 15 * time.Minute
+
+func (w *Worker) spawnStoryPoller() {
+	ticker := time.NewTicker(storyPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.pollStorySources()
+		case <-w.StopChan:
+			return
+		}
+	}
+}
+
+func (w *Worker) pollStorySources() {
+	ctx := context.Background()
+
+	for _, network := range fetcher.StoryNetworks {
+		sources, err := w.DB.GetActiveSourcesByNetwork(ctx, network)
+		if err != nil {
+			log.Printf("Worker: failed to get %s sources for story polling: %v", network, err)
+			continue
+		}
+
+		for _, source := range sources {
+			if err := fetcher.PollStoriesBySource(source, w.DB, w.Fetcher, w.Config.InstagramAPIVersion, w.Config.TokenEncryptionKey); err != nil {
+				log.Printf("Worker: story poll failed (source=%s): %v", source.ID, err)
+			}
+		}
+	}
+}
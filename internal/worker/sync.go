@@ -6,6 +6,7 @@ import (
 	"crypto/rand"
 	"database/sql"
 	"encoding/binary"
+	"errors"
 	"log"
 	"sync"
 	"time"
@@ -127,13 +128,18 @@ func syncSourceInternal(sid uuid.UUID, db *database.Queries, f *fetcher_common.C
 				return err
 			}
 
+			if errors.Is(err, fetcher_common.ErrLayoutChanged) {
+				log.Printf("Worker Source sync FAILED, not retrying (source=%s): %v", sid, err)
+				return err
+			}
+
 			delay := backoffWithJitter(attempt)
 			log.Printf("Worker Source sync error (source=%s attempt=%d). Retrying in %s: %v", sid, attempt+1, delay, err)
 			time.Sleep(delay)
 			return err
 		}()
 
-		if err == nil {
+		if err == nil || errors.Is(err, fetcher_common.ErrLayoutChanged) {
 			return
 		}
 	}
@@ -80,6 +80,9 @@ func (w *Worker) Start() {
 		}
 	}()
 
+	go w.spawnLivePoller()
+	go w.spawnStoryPoller()
+
 	log.Println("Background worker system started")
 }
 
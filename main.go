@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path"
 	"strings"
 	"syscall"
 	"time"
@@ -21,6 +22,7 @@ import (
 	"github.com/fluffyriot/rpsync/internal/cli"
 	"github.com/fluffyriot/rpsync/internal/config"
 	fetcher_common "github.com/fluffyriot/rpsync/internal/fetcher/common"
+	"github.com/fluffyriot/rpsync/internal/fetcher/fixtures"
 	"github.com/fluffyriot/rpsync/internal/middleware"
 	"github.com/fluffyriot/rpsync/internal/pusher/common"
 	"github.com/fluffyriot/rpsync/internal/updater"
@@ -41,8 +43,17 @@ func main() {
 	resetPwdFlag := flag.Bool("reset-password", false, "Reset user password")
 	reset2FAFlag := flag.Bool("reset-2fa", false, "Reset 2FA (TOTP) for a user")
 	resetUserFlag := flag.String("username", "", "Username (required for --reset-password and --reset-2fa)")
+	serveFixturesFlag := flag.String("serve-fixtures", "", "Serve recorded fetcher fixtures from this directory instead of starting the app")
+	fixturesAddrFlag := flag.String("fixtures-addr", "localhost:8089", "Listen address for --serve-fixtures")
+	recordFixturesFlag := flag.String("record-fixtures", "", "Record every fetcher response as a fixture in this directory")
+	checkFixturesFlag := flag.String("check-fixtures", "", "Sync every source in this fixture directory's sources.json against its fixtures, then exit")
 	flag.Parse()
 
+	if *serveFixturesFlag != "" {
+		cli.HandleServeFixtures(*serveFixturesFlag, *fixturesAddrFlag)
+		return
+	}
+
 	var pv projectVersion
 	if err := json.Unmarshal(versionFile, &pv); err != nil {
 		log.Printf("Error unmarshalling version.json: %v", err)
@@ -56,7 +67,14 @@ func main() {
 		log.Fatal(err)
 	}
 
-	clientFetch := fetcher_common.NewClient(600 * time.Second)
+	clientFetch := fetcher_common.NewClient(600*time.Second, fetcher_common.NewBrowserPool(cfg.BrowserPoolSize))
+	if *recordFixturesFlag != "" {
+		log.Printf("Recording fetcher responses to %s", *recordFixturesFlag)
+		recorder := fixtures.NewRecorder(*recordFixturesFlag, nil)
+		clientFetch.WrapTransport(func(rt http.RoundTripper) http.RoundTripper {
+			return recorder.WithBase(rt)
+		})
+	}
 	clientPull := common.NewClient(600 * time.Second)
 
 	if cfg.GinMode != "" {
@@ -92,6 +110,7 @@ func main() {
 			return a + b
 		},
 		"contains": strings.Contains,
+		"base":     path.Base,
 	})
 
 	r.LoadHTMLGlob("templates/*.html")
@@ -129,6 +148,14 @@ func main() {
 		return
 	}
 
+	if *checkFixturesFlag != "" {
+		if dbConn == nil {
+			log.Fatal("Database connection failed, cannot check fixtures")
+		}
+		cli.HandleCheckFixtures(dbQueries, clientFetch, cfg.TokenEncryptionKey, *checkFixturesFlag)
+		return
+	}
+
 	w := worker.NewWorker(dbQueries, clientFetch, clientPull, cfg)
 
 	upd := updater.NewUpdater(config.AppVersion)
@@ -163,6 +190,8 @@ func main() {
 	r.POST("/login", h.LoginSubmitHandler)
 	r.POST("/logout", h.LogoutHandler)
 
+	r.POST("/webhooks/kofi/:source_id", h.KofiWebhookHandler)
+
 	r.GET("/register", h.UserSetupViewHandler)
 	r.POST("/register", h.UserSetupHandler)
 	r.POST("/register/restore", h.BackupRestoreHandler)
@@ -216,6 +245,8 @@ func main() {
 
 	authorized.POST("/backup/export", h.BackupExportHandler)
 	authorized.POST("/backup/import", h.BackupImportHandler)
+	authorized.POST("/settings/storage", h.UpdateExportStorageHandler)
+	authorized.POST("/settings/storage/delete", h.DeleteExportStorageHandler)
 
 	authorized.GET("/outputs/*filepath", h.DownloadExportHandler)
 
@@ -227,12 +258,21 @@ func main() {
 	authorized.POST("/sources/deactivate", h.DeactivateSourceHandler)
 	authorized.POST("/sources/activate", h.ActivateSourceHandler)
 	authorized.POST("/sources/delete", h.DeleteSourceHandler)
+	authorized.POST("/sources/rename", h.RenameSourceHandler)
+	authorized.POST("/sources/merge", h.MergeSourceHandler)
 	authorized.POST("/sources/sync", h.SyncSourceHandler)
 	authorized.POST("/sources/token", h.UpdateSourceTokenHandler)
 	authorized.GET("/sources/cookies/export", h.HandleExportCookies)
 	authorized.POST("/sources/cookies/import", h.HandleImportCookies)
+	authorized.POST("/sources/archive/import", h.ImportSourceArchiveHandler)
 	authorized.PUT("/sources/:source_id/channels", h.UpdateSourceChannelsHandler)
 	authorized.GET("/sources/:source_id/channels", h.GetSourceChannelsHandler)
+	authorized.GET("/sources/:source_id/endpoints", h.GetSourceEndpointsHandler)
+	authorized.PUT("/sources/:source_id/endpoints", h.UpdateSourceEndpointsHandler)
+	authorized.GET("/sources/:source_id/network", h.GetSourceNetworkSettingsHandler)
+	authorized.PUT("/sources/:source_id/network", h.UpdateSourceNetworkSettingsHandler)
+	authorized.GET("/sources/:source_id/cookies", h.GetSourceCookiesHandler)
+	authorized.POST("/sources/:source_id/cookies/test", h.TestSourceCookiesHandler)
 
 	authorized.POST("/syncAll", h.TriggerSyncHandler)
 
@@ -242,6 +282,8 @@ func main() {
 	authorized.POST("/targets/activate", h.ActivateTargetHandler)
 	authorized.POST("/targets/delete", h.DeleteTargetHandler)
 	authorized.POST("/targets/sync", h.SyncTargetHandler)
+	authorized.GET("/targets/:target_id/files", h.GetFileSettingsHandler)
+	authorized.PUT("/targets/:target_id/files", h.UpdateFileSettingsHandler)
 
 	authorized.GET("/analytics/engagement", h.AnalyticsEngagementHandler)
 	authorized.GET("/analytics/website", h.AnalyticsWebsiteHandler)
@@ -258,6 +300,14 @@ func main() {
 	authorized.GET("/analytics/data/site", h.AnalyticsSiteStatsHandler)
 	authorized.GET("/analytics/data/gsc/site", h.AnalyticsGSCSiteStatsHandler)
 	authorized.GET("/analytics/data/gsc/pages", h.AnalyticsGSCTopPagesHandler)
+	authorized.GET("/analytics/data/youtube/channel", h.AnalyticsYouTubeChannelHandler)
+	authorized.GET("/analytics/data/youtube/videos", h.AnalyticsYouTubeTopVideosHandler)
+	authorized.GET("/analytics/data/youtube/videos/:post_id", h.AnalyticsYouTubeVideoHandler)
+	authorized.GET("/analytics/data/instagram/insights", h.AnalyticsInstagramInsightsHandler)
+	authorized.GET("/analytics/data/instagram/stories/:post_id", h.AnalyticsInstagramStoryHandler)
+	authorized.GET("/analytics/data/reddit/subreddits", h.AnalyticsRedditSubredditsHandler)
+	authorized.GET("/analytics/data/reactions", h.AnalyticsReactionsHandler)
+	authorized.GET("/analytics/data/reactions/:post_id", h.AnalyticsPostReactionsHandler)
 	authorized.GET("/analytics/data/consistency", h.AnalyticsPostingConsistencyHandler)
 	authorized.GET("/analytics/data/engagement-rate", h.AnalyticsEngagementRateHandler)
 	authorized.GET("/analytics/data/follow-ratio", h.AnalyticsFollowRatioHandler)
@@ -336,6 +386,7 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		slog.Error("Server forced to shutdown", "error", err)
 	}
+	clientFetch.Browsers.Close()
 
 	slog.Info("Server exiting")
 }
@@ -69,6 +69,31 @@ JOIN sources s ON ass.source_id = s.id
 WHERE s.user_id = $1
 ORDER BY ass.date;
 
+-- name: BackupGetStreamSessionsForUser :many
+SELECT ss.* FROM stream_sessions ss
+JOIN sources s ON ss.source_id = s.id
+WHERE s.user_id = $1
+ORDER BY ss.started_at;
+
+-- name: BackupGetStreamViewerSamplesForUser :many
+SELECT svs.* FROM stream_viewer_samples svs
+JOIN stream_sessions ss ON svs.session_id = ss.id
+JOIN sources s ON ss.source_id = s.id
+WHERE s.user_id = $1
+ORDER BY svs.sampled_at;
+
+-- name: BackupGetSupporterEventsForUser :many
+SELECT se.* FROM supporter_events se
+JOIN sources s ON se.source_id = s.id
+WHERE s.user_id = $1
+ORDER BY se.occurred_at;
+
+-- name: BackupGetSupporterTierStatsForUser :many
+SELECT sts.* FROM supporter_tier_stats sts
+JOIN sources s ON sts.source_id = s.id
+WHERE s.user_id = $1
+ORDER BY sts.date;
+
 -- name: BackupDeletePostTagsForUser :exec
 DELETE FROM post_tags WHERE id IN (
     SELECT pt.id FROM post_tags pt
@@ -196,3 +221,10 @@ DELETE FROM logs WHERE source_id IN (
 ) OR target_id IN (
     SELECT t.id FROM targets t WHERE t.user_id = $1
 );
+
+-- name: BackupCreateStreamSession :exec
+INSERT INTO stream_sessions (
+    id, source_id, post_id, stream_id, title, category, started_at,
+    ended_at, last_seen_at, peak_viewers, average_viewers, samples_count
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12);
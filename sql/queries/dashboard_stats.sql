@@ -66,6 +66,7 @@ SELECT calendar.date::date as period_date,
                     FROM sources_stats ss
                         JOIN sources s ON ss.source_id = s.id
                     WHERE s.user_id = $1
+                        AND s.network NOT IN ('Patreon', 'Ko-fi')
                         AND ss.date >= $2 - INTERVAL '1 day'
                         AND ss.date < calendar.date + INTERVAL '1 day'
                     ORDER BY ss.source_id,
@@ -80,6 +81,31 @@ FROM generate_series(
         '1 day'::interval
     ) as calendar (date)
 ORDER BY calendar.date ASC;
+-- name: GetTotalDailySupporterStats :many
+SELECT calendar.date::date as period_date,
+    COALESCE(
+        (
+            SELECT SUM(COALESCE(followers_count, 0))
+            FROM (
+                    SELECT DISTINCT ON (ss.source_id) ss.followers_count
+                    FROM sources_stats ss
+                        JOIN sources s ON ss.source_id = s.id
+                    WHERE s.user_id = $1
+                        AND s.network IN ('Patreon', 'Ko-fi')
+                        AND ss.date >= $2 - INTERVAL '1 day'
+                        AND ss.date < calendar.date + INTERVAL '1 day'
+                    ORDER BY ss.source_id,
+                        ss.date DESC
+                ) as distinct_sources
+        ),
+        0
+    )::BIGINT as total_supporters
+FROM generate_series(
+        date_trunc('day', $2::timestamp),
+        date_trunc('day', $3::timestamp),
+        '1 day'::interval
+    ) as calendar (date)
+ORDER BY calendar.date ASC;
 -- name: GetTopSources :many
 SELECT s.id,
     s.user_name,
@@ -0,0 +1,36 @@
+-- name: GetExportStorage :one
+SELECT * FROM export_storage WHERE user_id = $1;
+
+-- name: UpsertExportStorage :exec
+INSERT INTO
+    export_storage (
+        user_id,
+        updated_at,
+        storage_type,
+        endpoint,
+        bucket,
+        region,
+        path_prefix,
+        access_key,
+        encrypted_secret,
+        nonce,
+        retention_days,
+        keep_local
+    )
+VALUES ($1, NOW(), $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+ON CONFLICT (user_id) DO UPDATE
+SET
+    updated_at = NOW(),
+    storage_type = EXCLUDED.storage_type,
+    endpoint = EXCLUDED.endpoint,
+    bucket = EXCLUDED.bucket,
+    region = EXCLUDED.region,
+    path_prefix = EXCLUDED.path_prefix,
+    access_key = EXCLUDED.access_key,
+    encrypted_secret = EXCLUDED.encrypted_secret,
+    nonce = EXCLUDED.nonce,
+    retention_days = EXCLUDED.retention_days,
+    keep_local = EXCLUDED.keep_local;
+
+-- name: DeleteExportStorage :exec
+DELETE FROM export_storage WHERE user_id = $1;
@@ -52,4 +52,16 @@ SELECT * FROM exports where user_id = $1 ORDER BY created_at DESC;
 DELETE FROM exports WHERE user_id = $1;
 
 -- name: GetExportById :one
-SELECT * FROM exports WHERE id = $1;
\ No newline at end of file
+SELECT * FROM exports WHERE id = $1;
+
+-- name: GetExportsWithDownloadPrefixBefore :many
+SELECT *
+FROM exports
+WHERE
+    user_id = $1
+    AND created_at < $2
+    AND starts_with(download_url, sqlc.arg(url_prefix)::text)
+ORDER BY created_at;
+
+-- name: DeleteExportById :exec
+DELETE FROM exports WHERE id = $1;
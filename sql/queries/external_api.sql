@@ -62,7 +62,7 @@ FROM (
 
 -- name: GetSourceStatusCounts :one
 SELECT
-    COUNT(*) FILTER (WHERE is_active = TRUE AND sync_status NOT IN ('Failed', 'Deactivated'))::BIGINT AS healthy_count,
+    COUNT(*) FILTER (WHERE is_active = TRUE AND sync_status NOT IN ('Failed', 'Layout Changed', 'Deactivated'))::BIGINT AS healthy_count,
     COUNT(*) FILTER (WHERE is_active = TRUE)::BIGINT AS enabled_count,
     COUNT(*) FILTER (WHERE is_active = FALSE)::BIGINT AS disabled_count
 FROM sources
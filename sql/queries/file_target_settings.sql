@@ -0,0 +1,19 @@
+-- name: GetFileTargetSettings :one
+SELECT * FROM file_target_settings WHERE target_id = $1;
+
+-- name: UpsertFileTargetSettings :exec
+INSERT INTO
+    file_target_settings (
+        target_id,
+        updated_at,
+        columns,
+        zip_archive,
+        formats
+    )
+VALUES ($1, NOW(), $2, $3, $4)
+ON CONFLICT (target_id) DO UPDATE
+SET
+    updated_at = NOW(),
+    columns = EXCLUDED.columns,
+    zip_archive = EXCLUDED.zip_archive,
+    formats = EXCLUDED.formats;
@@ -0,0 +1,101 @@
+-- name: UpsertInstagramAccountInsights :exec
+INSERT INTO
+    instagram_account_insights (
+        id,
+        date,
+        source_id,
+        updated_at,
+        reach,
+        profile_views,
+        website_clicks,
+        accounts_engaged,
+        follower_demographics
+    )
+VALUES (
+        $1,
+        $2,
+        $3,
+        $4,
+        $5,
+        $6,
+        $7,
+        $8,
+        $9
+    )
+ON CONFLICT (source_id, date) DO UPDATE
+SET
+    updated_at = EXCLUDED.updated_at,
+    reach = EXCLUDED.reach,
+    profile_views = EXCLUDED.profile_views,
+    website_clicks = EXCLUDED.website_clicks,
+    accounts_engaged = EXCLUDED.accounts_engaged,
+    follower_demographics = CASE
+        WHEN EXCLUDED.follower_demographics = '{}'::jsonb THEN instagram_account_insights.follower_demographics
+        ELSE EXCLUDED.follower_demographics
+    END;
+
+-- name: UpsertInstagramOnlineFollowers :exec
+INSERT INTO
+    instagram_online_followers (
+        id,
+        date,
+        source_id,
+        hour_of_day,
+        followers
+    )
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (source_id, date, hour_of_day) DO UPDATE
+SET
+    followers = EXCLUDED.followers;
+
+-- name: GetInstagramAccountInsights :many
+SELECT TO_CHAR(iai.date, 'YYYY-MM-DD') AS date_str,
+    SUM(iai.reach)::BIGINT AS reach,
+    SUM(iai.profile_views)::BIGINT AS profile_views,
+    SUM(iai.website_clicks)::BIGINT AS website_clicks,
+    SUM(iai.accounts_engaged)::BIGINT AS accounts_engaged
+FROM instagram_account_insights iai
+    JOIN sources s ON iai.source_id = s.id
+WHERE s.user_id = $1
+    AND (
+        sqlc.narg('start_date')::date IS NULL
+        OR iai.date >= sqlc.narg('start_date')::date
+    )
+    AND (
+        sqlc.narg('end_date')::date IS NULL
+        OR iai.date <= sqlc.narg('end_date')::date
+    )
+GROUP BY iai.date
+ORDER BY iai.date ASC;
+
+-- name: GetInstagramOnlineFollowersByHour :many
+SELECT EXTRACT(
+        DOW
+        FROM iof.date
+    )::BIGINT AS day_of_week,
+    iof.hour_of_day::BIGINT AS hour_of_day,
+    AVG(iof.followers)::FLOAT AS avg_online_followers
+FROM instagram_online_followers iof
+    JOIN sources s ON iof.source_id = s.id
+WHERE s.user_id = $1
+    AND (
+        sqlc.narg('start_date')::date IS NULL
+        OR iof.date >= sqlc.narg('start_date')::date
+    )
+    AND (
+        sqlc.narg('end_date')::date IS NULL
+        OR iof.date <= sqlc.narg('end_date')::date
+    )
+GROUP BY day_of_week, iof.hour_of_day
+ORDER BY day_of_week, iof.hour_of_day;
+
+-- name: GetLatestInstagramFollowerDemographics :many
+SELECT DISTINCT ON (iai.source_id) iai.source_id,
+    s.user_name,
+    TO_CHAR(iai.date, 'YYYY-MM-DD') AS date_str,
+    iai.follower_demographics
+FROM instagram_account_insights iai
+    JOIN sources s ON iai.source_id = s.id
+WHERE s.user_id = $1
+    AND iai.follower_demographics <> '{}'::jsonb
+ORDER BY iai.source_id, iai.date DESC;
@@ -0,0 +1,45 @@
+-- name: CreateInstagramStorySnapshot :exec
+INSERT INTO
+    instagram_story_snapshots (
+        id,
+        synced_at,
+        post_id,
+        reach,
+        views,
+        replies,
+        shares,
+        total_interactions,
+        taps_forward,
+        taps_back,
+        exits
+    )
+VALUES (
+        $1,
+        $2,
+        $3,
+        $4,
+        $5,
+        $6,
+        $7,
+        $8,
+        $9,
+        $10,
+        $11
+    );
+
+-- name: GetInstagramStorySnapshots :many
+SELECT iss.synced_at,
+    iss.reach,
+    iss.views,
+    iss.replies,
+    iss.shares,
+    iss.total_interactions,
+    iss.taps_forward,
+    iss.taps_back,
+    iss.exits
+FROM instagram_story_snapshots iss
+    JOIN posts p ON iss.post_id = p.id
+    JOIN sources s ON p.source_id = s.id
+WHERE iss.post_id = $1
+    AND s.user_id = $2
+ORDER BY iss.synced_at ASC;
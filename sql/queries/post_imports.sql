@@ -0,0 +1,40 @@
+-- name: AdoptImportedPost :one
+UPDATE posts
+SET
+    network_internal_id = sqlc.arg('network_internal_id')
+WHERE
+    id = (
+        SELECT p.id
+        FROM posts p
+            JOIN post_imports pi ON pi.post_id = p.id
+        WHERE
+            p.source_id = sqlc.arg('source_id')
+            AND p.network_internal_id LIKE 'archive-%'
+            AND p.created_at BETWEEN sqlc.arg('from_time') AND sqlc.arg('to_time')
+        ORDER BY p.created_at ASC
+        LIMIT 1
+    )
+RETURNING
+    id;
+
+-- name: CreatePostImport :exec
+INSERT INTO
+    post_imports (
+        post_id,
+        imported_at,
+        archive_format
+    )
+SELECT id, last_synced_at, $2
+FROM posts
+WHERE
+    id = $1
+ON CONFLICT (post_id) DO NOTHING;
+
+-- name: GetPostIdBySourceAndTimeRange :one
+SELECT id
+FROM posts
+WHERE
+    source_id = $1
+    AND created_at BETWEEN sqlc.arg('from_time') AND sqlc.arg('to_time')
+ORDER BY created_at ASC
+LIMIT 1;
@@ -138,4 +138,20 @@ SET
     is_archived = true
 WHERE
     source_id = $1
-    AND last_synced_at < $2;
\ No newline at end of file
+    AND last_synced_at < $2
+    AND post_type <> 'story'
+    AND NOT EXISTS (
+        SELECT 1
+        FROM post_imports pi
+        WHERE
+            pi.post_id = posts.id
+            AND posts.last_synced_at <= pi.imported_at
+    );
+
+-- name: RenamePostAuthor :exec
+UPDATE posts
+SET
+    author = @new_author
+WHERE
+    source_id = @source_id
+    AND author = @old_author;
@@ -0,0 +1,65 @@
+-- name: SyncReactionBreakdown :exec
+INSERT INTO
+    post_reaction_breakdown (
+        id,
+        synced_at,
+        post_id,
+        reaction_key,
+        count
+    )
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (
+    post_id,
+    reaction_key,
+    (CAST(synced_at AS DATE))
+) DO
+UPDATE
+SET
+    count = EXCLUDED.count,
+    synced_at = EXCLUDED.synced_at;
+
+-- name: GetTopReactionsBySource :many
+SELECT s.id as source_id,
+    s.network,
+    s.user_name,
+    prb.reaction_key,
+    COALESCE(SUM(prb.count), 0)::BIGINT as total_count,
+    COUNT(DISTINCT prb.post_id)::BIGINT as posts_count
+FROM (
+        SELECT DISTINCT
+            ON (post_id, reaction_key) post_id, reaction_key, count
+        FROM post_reaction_breakdown
+        ORDER BY post_id, reaction_key, synced_at DESC
+    ) prb
+    JOIN posts p ON prb.post_id = p.id
+    JOIN sources s ON p.source_id = s.id
+WHERE s.user_id = @user_id
+    AND prb.count > 0
+    AND p.is_archived = false
+    AND (sqlc.narg('start_date')::date IS NULL OR p.created_at::date >= sqlc.narg('start_date')::date)
+    AND (sqlc.narg('end_date')::date IS NULL OR p.created_at::date <= sqlc.narg('end_date')::date)
+GROUP BY s.id,
+    s.network,
+    s.user_name,
+    prb.reaction_key
+ORDER BY s.network ASC,
+    total_count DESC;
+
+-- name: GetLatestReactionBreakdownForPost :many
+SELECT DISTINCT
+    ON (reaction_key) reaction_key, count
+FROM post_reaction_breakdown
+WHERE post_id = $1
+ORDER BY reaction_key, synced_at DESC;
+
+-- name: GetPostReactionBreakdown :many
+SELECT TO_CHAR(prb.synced_at, 'YYYY-MM-DD') as date_str,
+    prb.reaction_key,
+    prb.count
+FROM post_reaction_breakdown prb
+    JOIN posts p ON prb.post_id = p.id
+    JOIN sources s ON p.source_id = s.id
+WHERE prb.post_id = $1
+    AND s.user_id = $2
+ORDER BY prb.synced_at ASC,
+    prb.count DESC;
@@ -6,9 +6,10 @@ INSERT INTO
         post_id,
         likes,
         reposts,
-        views
+        views,
+        comments
     )
-VALUES ($1, $2, $3, $4, $5, $6)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
 ON CONFLICT (
     post_id,
     (CAST(synced_at AS DATE))
@@ -18,6 +19,7 @@ SET
     likes = EXCLUDED.likes,
     reposts = EXCLUDED.reposts,
     views = EXCLUDED.views,
+    comments = EXCLUDED.comments,
     synced_at = EXCLUDED.synced_at
 RETURNING
     *;
@@ -52,7 +54,31 @@ GROUP BY
     DATE (p.created_at)
 ORDER BY s.id, date ASC;
 
+-- name: GetSourceReactionMaxima :one
+SELECT
+    COALESCE(MAX(prh.likes), 0)::BIGINT AS max_likes,
+    COALESCE(MAX(prh.views), 0)::BIGINT AS max_views
+FROM
+    posts_reactions_history prh
+    JOIN posts p ON prh.post_id = p.id
+WHERE
+    p.source_id = $1;
+
 -- name: DeleteOldStats :exec
 DELETE from posts_reactions_history
 where
-    synced_at < now() - INTERVAL '365 days';
\ No newline at end of file
+    synced_at < now() - INTERVAL '365 days';
+
+-- name: GetReactionsForUserSince :many
+SELECT prh.* FROM posts_reactions_history prh
+JOIN posts p ON prh.post_id = p.id
+JOIN sources s ON p.source_id = s.id
+WHERE s.user_id = $1 AND prh.synced_at >= $2
+ORDER BY prh.synced_at;
+
+-- name: GetReactionsForSourceSince :many
+SELECT prh.*, p.post_type
+FROM posts_reactions_history prh
+JOIN posts p ON prh.post_id = p.id
+WHERE p.source_id = $1 AND prh.synced_at > $2
+ORDER BY prh.synced_at;
@@ -0,0 +1,40 @@
+-- name: UpsertRedditPostDetails :exec
+INSERT INTO
+    reddit_post_details (
+        post_id,
+        updated_at,
+        subreddit,
+        upvote_ratio,
+        num_crossposts
+    )
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (post_id) DO
+UPDATE
+SET
+    updated_at = EXCLUDED.updated_at,
+    subreddit = EXCLUDED.subreddit,
+    upvote_ratio = EXCLUDED.upvote_ratio,
+    num_crossposts = EXCLUDED.num_crossposts;
+
+-- name: GetRedditSubredditBreakdown :many
+SELECT rpd.subreddit,
+    COUNT(p.id)::BIGINT as posts_count,
+    COALESCE(SUM(prh.likes), 0)::BIGINT as total_score,
+    COALESCE(SUM(prh.comments), 0)::BIGINT as total_comments,
+    COALESCE(SUM(rpd.num_crossposts), 0)::BIGINT as total_crossposts,
+    COALESCE(AVG(rpd.upvote_ratio), 0)::FLOAT as avg_upvote_ratio
+FROM reddit_post_details rpd
+    JOIN posts p ON rpd.post_id = p.id
+    JOIN sources s ON p.source_id = s.id
+    LEFT JOIN (
+        SELECT DISTINCT
+            ON (post_id) post_id, likes, comments
+        FROM posts_reactions_history
+        ORDER BY post_id, synced_at DESC
+    ) prh ON p.id = prh.post_id
+WHERE s.user_id = @user_id
+    AND p.is_archived = false
+    AND (sqlc.narg('start_date')::date IS NULL OR p.created_at::date >= sqlc.narg('start_date')::date)
+    AND (sqlc.narg('end_date')::date IS NULL OR p.created_at::date <= sqlc.narg('end_date')::date)
+GROUP BY rpd.subreddit
+ORDER BY total_score DESC;
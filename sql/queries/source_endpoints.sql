@@ -0,0 +1,18 @@
+-- name: GetSourceEndpoints :many
+SELECT default_base_url, base_url
+FROM source_endpoints
+WHERE source_id = $1
+ORDER BY default_base_url;
+
+-- name: CreateSourceEndpoint :exec
+INSERT INTO
+    source_endpoints (
+        id,
+        source_id,
+        default_base_url,
+        base_url
+    )
+VALUES ($1, $2, $3, $4);
+
+-- name: DeleteSourceEndpoints :exec
+DELETE FROM source_endpoints WHERE source_id = $1;
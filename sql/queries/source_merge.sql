@@ -0,0 +1,310 @@
+-- Merging moves one source's history into another source of the same
+-- network. Rows the surviving source already has for the same post, date or
+-- external ID are kept and the merged source's copies are dropped with it.
+
+-- name: MergeSourceAnalyticsPageStats :exec
+UPDATE analytics_page_stats
+SET
+    source_id = @into_source_id
+WHERE
+    source_id = @from_source_id
+    AND NOT EXISTS (
+        SELECT 1
+        FROM analytics_page_stats existing
+        WHERE
+            existing.source_id = @into_source_id
+            AND existing.date = analytics_page_stats.date
+            AND existing.url_path = analytics_page_stats.url_path
+    );
+
+-- name: MergeSourceAnalyticsSiteStats :exec
+UPDATE analytics_site_stats
+SET
+    source_id = @into_source_id
+WHERE
+    source_id = @from_source_id
+    AND NOT EXISTS (
+        SELECT 1
+        FROM analytics_site_stats existing
+        WHERE
+            existing.source_id = @into_source_id
+            AND existing.date = analytics_site_stats.date
+    );
+
+-- name: MergeSourceDuplicateInstagramStorySnapshots :exec
+UPDATE instagram_story_snapshots
+SET
+    post_id = existing.id
+FROM posts dup
+    JOIN posts existing ON existing.network_internal_id = dup.network_internal_id
+    AND existing.source_id = @into_source_id
+WHERE
+    dup.source_id = @from_source_id
+    AND instagram_story_snapshots.post_id = dup.id
+    AND NOT EXISTS (
+        SELECT 1
+        FROM instagram_story_snapshots kept
+        WHERE
+            kept.post_id = existing.id
+            AND kept.synced_at = instagram_story_snapshots.synced_at
+    );
+
+-- name: MergeSourceDuplicatePostTags :exec
+UPDATE post_tags
+SET
+    post_id = existing.id
+FROM posts dup
+    JOIN posts existing ON existing.network_internal_id = dup.network_internal_id
+    AND existing.source_id = @into_source_id
+WHERE
+    dup.source_id = @from_source_id
+    AND post_tags.post_id = dup.id
+    AND NOT EXISTS (
+        SELECT 1
+        FROM post_tags kept
+        WHERE
+            kept.post_id = existing.id
+            AND kept.tag_id = post_tags.tag_id
+    );
+
+-- name: MergeSourceDuplicateReactionBreakdown :exec
+UPDATE post_reaction_breakdown
+SET
+    post_id = existing.id
+FROM posts dup
+    JOIN posts existing ON existing.network_internal_id = dup.network_internal_id
+    AND existing.source_id = @into_source_id
+WHERE
+    dup.source_id = @from_source_id
+    AND post_reaction_breakdown.post_id = dup.id
+    AND NOT EXISTS (
+        SELECT 1
+        FROM post_reaction_breakdown kept
+        WHERE
+            kept.post_id = existing.id
+            AND kept.reaction_key = post_reaction_breakdown.reaction_key
+            AND kept.synced_at::DATE = post_reaction_breakdown.synced_at::DATE
+    );
+
+-- name: MergeSourceDuplicateReactionHistory :exec
+UPDATE posts_reactions_history
+SET
+    post_id = existing.id
+FROM posts dup
+    JOIN posts existing ON existing.network_internal_id = dup.network_internal_id
+    AND existing.source_id = @into_source_id
+WHERE
+    dup.source_id = @from_source_id
+    AND posts_reactions_history.post_id = dup.id
+    AND NOT EXISTS (
+        SELECT 1
+        FROM posts_reactions_history kept
+        WHERE
+            kept.post_id = existing.id
+            AND kept.synced_at::DATE = posts_reactions_history.synced_at::DATE
+    );
+
+-- name: MergeSourceDuplicateRedditPostDetails :exec
+UPDATE reddit_post_details
+SET
+    post_id = existing.id
+FROM posts dup
+    JOIN posts existing ON existing.network_internal_id = dup.network_internal_id
+    AND existing.source_id = @into_source_id
+WHERE
+    dup.source_id = @from_source_id
+    AND reddit_post_details.post_id = dup.id
+    AND NOT EXISTS (
+        SELECT 1
+        FROM reddit_post_details kept
+        WHERE
+            kept.post_id = existing.id
+    );
+
+-- name: MergeSourceDuplicateStreamSessionPosts :exec
+UPDATE stream_sessions
+SET
+    post_id = existing.id
+FROM posts dup
+    JOIN posts existing ON existing.network_internal_id = dup.network_internal_id
+    AND existing.source_id = @into_source_id
+WHERE
+    dup.source_id = @from_source_id
+    AND stream_sessions.post_id = dup.id;
+
+-- name: MergeSourceDuplicateYoutubeVideoDailyStats :exec
+UPDATE youtube_video_daily_stats
+SET
+    post_id = existing.id
+FROM posts dup
+    JOIN posts existing ON existing.network_internal_id = dup.network_internal_id
+    AND existing.source_id = @into_source_id
+WHERE
+    dup.source_id = @from_source_id
+    AND youtube_video_daily_stats.post_id = dup.id
+    AND NOT EXISTS (
+        SELECT 1
+        FROM youtube_video_daily_stats kept
+        WHERE
+            kept.post_id = existing.id
+            AND kept.date = youtube_video_daily_stats.date
+    );
+
+-- name: MergeSourceDetachPostsFromTarget :exec
+UPDATE posts_on_target
+SET
+    post_id = NULL
+FROM posts
+WHERE
+    posts_on_target.post_id = posts.id
+    AND posts_on_target.target_id = @target_id
+    AND posts.source_id = @from_source_id;
+
+-- name: MergeSourceExclusions :exec
+UPDATE exclusions
+SET
+    source_id = @into_source_id
+WHERE
+    source_id = @from_source_id
+    AND NOT EXISTS (
+        SELECT 1
+        FROM exclusions existing
+        WHERE
+            existing.source_id = @into_source_id
+            AND existing.network_internal_id = exclusions.network_internal_id
+    );
+
+-- name: MergeSourceInstagramAccountInsights :exec
+UPDATE instagram_account_insights
+SET
+    source_id = @into_source_id
+WHERE
+    source_id = @from_source_id
+    AND NOT EXISTS (
+        SELECT 1
+        FROM instagram_account_insights existing
+        WHERE
+            existing.source_id = @into_source_id
+            AND existing.date = instagram_account_insights.date
+    );
+
+-- name: MergeSourceInstagramOnlineFollowers :exec
+UPDATE instagram_online_followers
+SET
+    source_id = @into_source_id
+WHERE
+    source_id = @from_source_id
+    AND NOT EXISTS (
+        SELECT 1
+        FROM instagram_online_followers existing
+        WHERE
+            existing.source_id = @into_source_id
+            AND existing.date = instagram_online_followers.date
+            AND existing.hour_of_day = instagram_online_followers.hour_of_day
+    );
+
+-- name: MergeSourceLogs :exec
+UPDATE logs
+SET
+    source_id = @into_source_id
+WHERE
+    source_id = @from_source_id;
+
+-- name: MergeSourcePosts :exec
+UPDATE posts
+SET
+    source_id = @into_source_id
+WHERE
+    source_id = @from_source_id
+    AND NOT EXISTS (
+        SELECT 1
+        FROM posts existing
+        WHERE
+            existing.source_id = @into_source_id
+            AND existing.network_internal_id = posts.network_internal_id
+    );
+
+-- name: MergeSourceRedirects :exec
+UPDATE redirects
+SET
+    source_id = @into_source_id
+WHERE
+    source_id = @from_source_id
+    AND NOT EXISTS (
+        SELECT 1
+        FROM redirects existing
+        WHERE
+            existing.source_id = @into_source_id
+            AND existing.from_path = redirects.from_path
+    );
+
+-- name: MergeSourceStats :exec
+UPDATE sources_stats
+SET
+    source_id = @into_source_id
+WHERE
+    source_id = @from_source_id
+    AND NOT EXISTS (
+        SELECT 1
+        FROM sources_stats existing
+        WHERE
+            existing.source_id = @into_source_id
+            AND existing.date = sources_stats.date
+    );
+
+-- name: MergeSourceStreamSessions :exec
+UPDATE stream_sessions
+SET
+    source_id = @into_source_id
+WHERE
+    source_id = @from_source_id
+    AND NOT EXISTS (
+        SELECT 1
+        FROM stream_sessions existing
+        WHERE
+            existing.source_id = @into_source_id
+            AND existing.stream_id = stream_sessions.stream_id
+    );
+
+-- name: MergeSourceSupporterEvents :exec
+UPDATE supporter_events
+SET
+    source_id = @into_source_id
+WHERE
+    source_id = @from_source_id
+    AND NOT EXISTS (
+        SELECT 1
+        FROM supporter_events existing
+        WHERE
+            existing.source_id = @into_source_id
+            AND existing.external_id = supporter_events.external_id
+    );
+
+-- name: MergeSourceSupporterTierStats :exec
+UPDATE supporter_tier_stats
+SET
+    source_id = @into_source_id
+WHERE
+    source_id = @from_source_id
+    AND NOT EXISTS (
+        SELECT 1
+        FROM supporter_tier_stats existing
+        WHERE
+            existing.source_id = @into_source_id
+            AND existing.tier_id = supporter_tier_stats.tier_id
+            AND existing.date = supporter_tier_stats.date
+    );
+
+-- name: MergeSourceYoutubeChannelDailyStats :exec
+UPDATE youtube_channel_daily_stats
+SET
+    source_id = @into_source_id
+WHERE
+    source_id = @from_source_id
+    AND NOT EXISTS (
+        SELECT 1
+        FROM youtube_channel_daily_stats existing
+        WHERE
+            existing.source_id = @into_source_id
+            AND existing.date = youtube_channel_daily_stats.date
+    );
\ No newline at end of file
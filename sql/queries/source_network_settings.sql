@@ -0,0 +1,22 @@
+-- name: GetSourceNetworkSettings :one
+SELECT * FROM source_network_settings WHERE source_id = $1;
+
+-- name: UpsertSourceNetworkSettings :exec
+INSERT INTO
+    source_network_settings (
+        source_id,
+        updated_at,
+        user_agent,
+        encrypted_settings,
+        nonce
+    )
+VALUES ($1, NOW(), $2, $3, $4)
+ON CONFLICT (source_id) DO UPDATE
+SET
+    updated_at = NOW(),
+    user_agent = EXCLUDED.user_agent,
+    encrypted_settings = EXCLUDED.encrypted_settings,
+    nonce = EXCLUDED.nonce;
+
+-- name: DeleteSourceNetworkSettings :exec
+DELETE FROM source_network_settings WHERE source_id = $1;
@@ -9,7 +9,10 @@ INSERT INTO
         posts_count,
         average_likes,
         average_reposts,
-        average_views
+        average_views,
+        karma,
+        followers_delta,
+        following_delta
     )
 VALUES (
         $1,
@@ -20,7 +23,10 @@ VALUES (
         $6,
         $7,
         $8,
-        $9
+        $9,
+        $10,
+        $11,
+        $12
     )
 RETURNING
     *;
@@ -33,10 +39,13 @@ SET
     posts_count = $3,
     average_likes = $4,
     average_reposts = $5,
-    average_views = $6
+    average_views = $6,
+    karma = $7,
+    followers_delta = $8,
+    following_delta = $9
 WHERE
-    source_id = $7
-    AND date = $8
+    source_id = $10
+    AND date = $11
 RETURNING
     *;
 
@@ -48,6 +57,14 @@ WHERE
     AND date = $2
 LIMIT 1;
 
+-- name: GetSourceStatsSince :many
+SELECT *
+FROM sources_stats
+WHERE
+    source_id = $1
+    AND date >= $2
+ORDER BY date;
+
 -- name: GetSourceTotals :one
 SELECT
     COUNT(DISTINCT p.id)::BIGINT AS total_posts,
@@ -62,4 +79,12 @@ FROM posts p
         ORDER BY post_id, synced_at DESC
     ) prh ON p.id = prh.post_id
 WHERE
-    p.source_id = $1;
\ No newline at end of file
+    p.source_id = $1;
+-- name: GetLatestSourceStatBeforeDate :one
+SELECT *
+FROM sources_stats
+WHERE
+    source_id = $1
+    AND date < $2
+ORDER BY date DESC
+LIMIT 1;
@@ -1,5 +1,5 @@
 -- name: GetAllSourcesStatsForUser :many
-SELECT ss.id, ss.date, ss.source_id, ss.followers_count, ss.following_count, ss.posts_count, ss.average_likes, ss.average_reposts, ss.average_views
+SELECT ss.id, ss.date, ss.source_id, ss.followers_count, ss.following_count, ss.posts_count, ss.average_likes, ss.average_reposts, ss.average_views, ss.karma, ss.followers_delta, ss.following_delta
 FROM sources_stats ss
     LEFT JOIN sources s ON ss.source_id = s.id
 WHERE
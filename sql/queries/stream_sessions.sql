@@ -0,0 +1,59 @@
+-- name: CreateStreamSession :one
+INSERT INTO
+    stream_sessions (
+        id,
+        source_id,
+        post_id,
+        stream_id,
+        title,
+        category,
+        started_at,
+        last_seen_at
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (source_id, stream_id) DO UPDATE
+SET
+    ended_at = NULL
+RETURNING *;
+
+-- name: GetOpenStreamSessionBySource :one
+SELECT *
+FROM stream_sessions
+WHERE
+    source_id = $1
+    AND ended_at IS NULL
+ORDER BY started_at DESC
+LIMIT 1;
+
+-- name: GetStreamSessionsBySource :many
+SELECT *
+FROM stream_sessions
+WHERE
+    source_id = $1
+ORDER BY started_at DESC;
+
+-- name: RecordStreamSessionSample :one
+UPDATE stream_sessions
+SET
+    title = @title,
+    category = @category,
+    last_seen_at = @last_seen_at,
+    peak_viewers = GREATEST(peak_viewers, @viewer_count::BIGINT),
+    average_viewers = (average_viewers * samples_count + @viewer_count::BIGINT) / (samples_count + 1),
+    samples_count = samples_count + 1
+WHERE
+    id = @id
+RETURNING *;
+
+-- name: EndStreamSession :exec
+UPDATE stream_sessions SET ended_at = $2 WHERE id = $1;
+
+-- name: CreateStreamViewerSample :exec
+INSERT INTO
+    stream_viewer_samples (
+        id,
+        session_id,
+        sampled_at,
+        viewer_count
+    )
+VALUES ($1, $2, $3, $4);
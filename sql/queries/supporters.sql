@@ -0,0 +1,65 @@
+-- name: UpsertSupporterTierStat :exec
+INSERT INTO
+    supporter_tier_stats (
+        id,
+        date,
+        source_id,
+        tier_id,
+        tier_title,
+        amount_cents,
+        supporters_count
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (source_id, tier_id, date) DO
+UPDATE
+SET
+    tier_title = EXCLUDED.tier_title,
+    amount_cents = EXCLUDED.amount_cents,
+    supporters_count = EXCLUDED.supporters_count;
+
+-- name: CreateSupporterEvent :exec
+INSERT INTO
+    supporter_events (
+        id,
+        created_at,
+        occurred_at,
+        source_id,
+        external_id,
+        event_type,
+        from_name,
+        from_email,
+        message,
+        amount_cents,
+        currency,
+        is_public,
+        is_subscription,
+        tier_name
+    )
+VALUES (
+        $1,
+        $2,
+        $3,
+        $4,
+        $5,
+        $6,
+        $7,
+        $8,
+        $9,
+        $10,
+        $11,
+        $12,
+        $13,
+        $14
+    )
+ON CONFLICT (source_id, external_id) DO NOTHING;
+
+-- name: GetSupporterEventTotalsBySource :one
+SELECT
+    COUNT(*)::BIGINT AS total_events,
+    COUNT(
+        DISTINCT COALESCE(LOWER(from_email), external_id)
+    )::BIGINT AS total_supporters,
+    COALESCE(SUM(amount_cents), 0)::BIGINT AS total_amount_cents
+FROM supporter_events
+WHERE
+    source_id = $1;
@@ -0,0 +1,20 @@
+-- name: GetTimeseriesWatermarks :many
+SELECT *
+FROM timeseries_watermarks
+WHERE target_id = $1;
+
+-- name: SetTimeseriesWatermark :exec
+INSERT INTO
+    timeseries_watermarks (
+        target_id,
+        source_id,
+        series,
+        last_exported_at
+    )
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (target_id, source_id, series) DO UPDATE
+SET last_exported_at = EXCLUDED.last_exported_at;
+
+-- name: DeleteTimeseriesWatermarksForSource :exec
+DELETE FROM timeseries_watermarks
+WHERE target_id = $1 AND source_id = $2;
@@ -45,6 +45,9 @@ RETURNING
 -- name: EmptyUsers :exec
 DELETE FROM users;
 
+-- name: DeleteUserByID :exec
+DELETE FROM users WHERE id = $1;
+
 -- name: GetAllUsers :many
 SELECT * FROM users;
 
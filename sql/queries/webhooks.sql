@@ -0,0 +1,64 @@
+-- name: CreateWebhookDelivery :one
+INSERT INTO
+    webhook_deliveries (
+        id,
+        target_id,
+        created_at,
+        event_type,
+        payload,
+        status,
+        attempts,
+        response_code,
+        error
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING
+    *;
+
+-- name: GetLastSentWebhookEvent :one
+SELECT *
+FROM webhook_deliveries
+WHERE
+    target_id = $1
+    AND event_type = $2
+    AND status IN ('Delivered', 'Refused')
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: GetRecentWebhookDeliveriesForUser :many
+SELECT wd.*
+FROM webhook_deliveries wd
+JOIN targets t ON wd.target_id = t.id
+WHERE t.user_id = $1
+ORDER BY wd.created_at DESC
+LIMIT 50;
+
+-- name: DeleteWebhookDeliveriesBefore :exec
+DELETE FROM webhook_deliveries
+WHERE target_id = $1 AND created_at < $2;
+
+-- name: GetWebhookMilestones :many
+SELECT *
+FROM webhook_milestones
+WHERE target_id = $1;
+
+-- name: SetWebhookMilestone :exec
+INSERT INTO
+    webhook_milestones (
+        target_id,
+        subject_id,
+        metric,
+        milestone
+    )
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (target_id, subject_id, metric) DO UPDATE
+SET milestone = EXCLUDED.milestone;
+
+-- name: DeleteWebhookMilestonesForSource :exec
+DELETE FROM webhook_milestones
+WHERE
+    target_id = $1
+    AND (
+        subject_id = $2
+        OR subject_id IN (SELECT id FROM posts WHERE source_id = $2)
+    );
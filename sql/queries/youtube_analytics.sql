@@ -0,0 +1,114 @@
+-- name: CountYouTubeChannelDailyStatsBySource :one
+SELECT COUNT(*) FROM youtube_channel_daily_stats WHERE source_id = $1;
+
+-- name: UpsertYouTubeChannelDailyStat :exec
+-- A NULL split keeps the stored one, so a failed split query does not wipe
+-- the days it already covered.
+INSERT INTO
+    youtube_channel_daily_stats (
+        id,
+        date,
+        source_id,
+        views,
+        estimated_minutes_watched,
+        average_view_duration,
+        subscribers_gained,
+        subscribers_lost,
+        shorts_views,
+        long_form_views
+    )
+VALUES (
+        @id,
+        @date,
+        @source_id,
+        @views,
+        @estimated_minutes_watched,
+        @average_view_duration,
+        @subscribers_gained,
+        @subscribers_lost,
+        COALESCE(sqlc.narg('shorts_views')::BIGINT, 0),
+        COALESCE(sqlc.narg('long_form_views')::BIGINT, 0)
+    )
+ON CONFLICT (source_id, date) DO
+UPDATE
+SET
+    views = EXCLUDED.views,
+    estimated_minutes_watched = EXCLUDED.estimated_minutes_watched,
+    average_view_duration = EXCLUDED.average_view_duration,
+    subscribers_gained = EXCLUDED.subscribers_gained,
+    subscribers_lost = EXCLUDED.subscribers_lost,
+    shorts_views = COALESCE(sqlc.narg('shorts_views')::BIGINT, youtube_channel_daily_stats.shorts_views),
+    long_form_views = COALESCE(sqlc.narg('long_form_views')::BIGINT, youtube_channel_daily_stats.long_form_views);
+
+-- name: UpsertYouTubeVideoDailyStat :exec
+INSERT INTO
+    youtube_video_daily_stats (
+        id,
+        date,
+        post_id,
+        views,
+        estimated_minutes_watched,
+        average_view_duration,
+        subscribers_gained,
+        subscribers_lost
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (post_id, date) DO
+UPDATE
+SET
+    views = EXCLUDED.views,
+    estimated_minutes_watched = EXCLUDED.estimated_minutes_watched,
+    average_view_duration = EXCLUDED.average_view_duration,
+    subscribers_gained = EXCLUDED.subscribers_gained,
+    subscribers_lost = EXCLUDED.subscribers_lost;
+
+-- name: GetYouTubeChannelDailyStats :many
+SELECT TO_CHAR(ycs.date, 'YYYY-MM-DD') as date_str,
+    COALESCE(SUM(ycs.views), 0)::BIGINT as total_views,
+    COALESCE(SUM(ycs.estimated_minutes_watched), 0)::BIGINT as total_minutes_watched,
+    COALESCE(AVG(ycs.average_view_duration), 0)::FLOAT as avg_view_duration,
+    COALESCE(SUM(ycs.subscribers_gained), 0)::BIGINT as subscribers_gained,
+    COALESCE(SUM(ycs.subscribers_lost), 0)::BIGINT as subscribers_lost,
+    COALESCE(SUM(ycs.shorts_views), 0)::BIGINT as shorts_views,
+    COALESCE(SUM(ycs.long_form_views), 0)::BIGINT as long_form_views
+FROM youtube_channel_daily_stats ycs
+    JOIN sources s ON ycs.source_id = s.id
+WHERE s.user_id = @user_id
+    AND (sqlc.narg('start_date')::date IS NULL OR ycs.date >= sqlc.narg('start_date')::date)
+    AND (sqlc.narg('end_date')::date IS NULL OR ycs.date <= sqlc.narg('end_date')::date)
+GROUP BY ycs.date
+ORDER BY ycs.date ASC;
+
+-- name: GetYouTubeTopVideosByNetSubscribers :many
+SELECT p.id,
+    p.network_internal_id,
+    p.content,
+    COALESCE(SUM(yvs.views), 0)::BIGINT as total_views,
+    COALESCE(SUM(yvs.estimated_minutes_watched), 0)::BIGINT as total_minutes_watched,
+    COALESCE(SUM(yvs.subscribers_gained - yvs.subscribers_lost), 0)::BIGINT as net_subscribers
+FROM youtube_video_daily_stats yvs
+    JOIN posts p ON yvs.post_id = p.id
+    JOIN sources s ON p.source_id = s.id
+WHERE s.user_id = @user_id
+    AND (sqlc.narg('start_date')::date IS NULL OR yvs.date >= sqlc.narg('start_date')::date)
+    AND (sqlc.narg('end_date')::date IS NULL OR yvs.date <= sqlc.narg('end_date')::date)
+GROUP BY p.id,
+    p.network_internal_id,
+    p.content
+ORDER BY net_subscribers DESC,
+    total_views DESC
+LIMIT 25;
+
+-- name: GetYouTubeVideoDailyStats :many
+SELECT yvs.date,
+    yvs.views,
+    yvs.estimated_minutes_watched,
+    yvs.average_view_duration,
+    yvs.subscribers_gained,
+    yvs.subscribers_lost
+FROM youtube_video_daily_stats yvs
+    JOIN posts p ON yvs.post_id = p.id
+    JOIN sources s ON p.source_id = s.id
+WHERE yvs.post_id = $1
+    AND s.user_id = $2
+ORDER BY yvs.date ASC;
@@ -0,0 +1,100 @@
+-- +goose Up
+ALTER TABLE sources DROP CONSTRAINT network_check;
+
+ALTER TABLE sources
+ADD CONSTRAINT network_check CHECK (
+    network IN (
+        'Instagram',
+        'Twitter',
+        'Threads',
+        'Bluesky',
+        'Murrtube',
+        'BadPups',
+        'TikTok',
+        'Mastodon',
+        'Reddit',
+        'Telegram',
+        'Discord',
+        'Twitch',
+        'YouTube',
+        'DeviantArt',
+        'e621',
+        'Weasyl',
+        'FurTrack',
+        'FurAffinity',
+        'Patreon',
+        'Ko-fi',
+        'Google Analytics',
+        'Google Search Console'
+    )
+);
+
+ALTER TABLE posts_reactions_history ADD COLUMN comments BIGINT;
+
+CREATE TABLE supporter_tier_stats (
+    id UUID PRIMARY KEY,
+    date TIMESTAMP NOT NULL,
+    source_id UUID NOT NULL,
+    tier_id TEXT NOT NULL,
+    tier_title TEXT NOT NULL,
+    amount_cents BIGINT NOT NULL,
+    supporters_count BIGINT NOT NULL,
+    CONSTRAINT fk_supporter_tier_stats_source FOREIGN KEY (source_id) REFERENCES sources (id) ON DELETE CASCADE,
+    CONSTRAINT unique_supporter_tier_stat UNIQUE (source_id, tier_id, date)
+);
+
+CREATE TABLE supporter_events (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    source_id UUID NOT NULL,
+    external_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    from_name TEXT,
+    from_email TEXT,
+    message TEXT,
+    amount_cents BIGINT NOT NULL,
+    currency TEXT NOT NULL,
+    is_public BOOLEAN NOT NULL,
+    is_subscription BOOLEAN NOT NULL,
+    tier_name TEXT,
+    CONSTRAINT fk_supporter_events_source FOREIGN KEY (source_id) REFERENCES sources (id) ON DELETE CASCADE,
+    CONSTRAINT unique_supporter_event UNIQUE (source_id, external_id)
+);
+
+CREATE INDEX idx_supporter_events_source_occurred ON supporter_events (source_id, occurred_at);
+
+-- +goose Down
+DROP TABLE supporter_events;
+
+DROP TABLE supporter_tier_stats;
+
+ALTER TABLE posts_reactions_history DROP COLUMN comments;
+
+ALTER TABLE sources DROP CONSTRAINT network_check;
+
+ALTER TABLE sources
+ADD CONSTRAINT network_check CHECK (
+    network IN (
+        'Instagram',
+        'Twitter',
+        'Threads',
+        'Bluesky',
+        'Murrtube',
+        'BadPups',
+        'TikTok',
+        'Mastodon',
+        'Reddit',
+        'Telegram',
+        'Discord',
+        'Twitch',
+        'YouTube',
+        'DeviantArt',
+        'e621',
+        'Weasyl',
+        'FurTrack',
+        'FurAffinity',
+        'Google Analytics',
+        'Google Search Console'
+    )
+);
@@ -0,0 +1,35 @@
+-- +goose Up
+
+CREATE TABLE youtube_channel_daily_stats (
+    id UUID PRIMARY KEY,
+    date TIMESTAMP NOT NULL,
+    source_id UUID NOT NULL,
+    views BIGINT NOT NULL DEFAULT 0,
+    estimated_minutes_watched BIGINT NOT NULL DEFAULT 0,
+    average_view_duration FLOAT NOT NULL DEFAULT 0.0,
+    subscribers_gained BIGINT NOT NULL DEFAULT 0,
+    subscribers_lost BIGINT NOT NULL DEFAULT 0,
+    shorts_views BIGINT NOT NULL DEFAULT 0,
+    long_form_views BIGINT NOT NULL DEFAULT 0,
+    CONSTRAINT fk_youtube_channel_daily_source FOREIGN KEY (source_id) REFERENCES sources (id) ON DELETE CASCADE,
+    CONSTRAINT unique_youtube_channel_daily_stat UNIQUE (source_id, date)
+);
+
+CREATE TABLE youtube_video_daily_stats (
+    id UUID PRIMARY KEY,
+    date TIMESTAMP NOT NULL,
+    post_id UUID NOT NULL,
+    views BIGINT NOT NULL DEFAULT 0,
+    estimated_minutes_watched BIGINT NOT NULL DEFAULT 0,
+    average_view_duration FLOAT NOT NULL DEFAULT 0.0,
+    subscribers_gained BIGINT NOT NULL DEFAULT 0,
+    subscribers_lost BIGINT NOT NULL DEFAULT 0,
+    CONSTRAINT fk_youtube_video_daily_post FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    CONSTRAINT unique_youtube_video_daily_stat UNIQUE (post_id, date)
+);
+
+-- +goose Down
+
+DROP TABLE youtube_video_daily_stats;
+
+DROP TABLE youtube_channel_daily_stats;
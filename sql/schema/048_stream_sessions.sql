@@ -0,0 +1,35 @@
+-- +goose Up
+
+CREATE TABLE stream_sessions (
+    id UUID PRIMARY KEY,
+    source_id UUID NOT NULL,
+    post_id UUID,
+    stream_id TEXT NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    category TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP,
+    last_seen_at TIMESTAMP NOT NULL,
+    peak_viewers BIGINT NOT NULL DEFAULT 0,
+    average_viewers FLOAT NOT NULL DEFAULT 0.0,
+    samples_count BIGINT NOT NULL DEFAULT 0,
+    CONSTRAINT fk_stream_sessions_source FOREIGN KEY (source_id) REFERENCES sources (id) ON DELETE CASCADE,
+    CONSTRAINT fk_stream_sessions_post FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE SET NULL,
+    CONSTRAINT unique_stream_session UNIQUE (source_id, stream_id)
+);
+
+CREATE TABLE stream_viewer_samples (
+    id UUID PRIMARY KEY,
+    session_id UUID NOT NULL,
+    sampled_at TIMESTAMP NOT NULL,
+    viewer_count BIGINT NOT NULL,
+    CONSTRAINT fk_stream_viewer_samples_session FOREIGN KEY (session_id) REFERENCES stream_sessions (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_stream_viewer_samples_session ON stream_viewer_samples (session_id, sampled_at);
+
+-- +goose Down
+
+DROP TABLE stream_viewer_samples;
+
+DROP TABLE stream_sessions;
@@ -0,0 +1,20 @@
+-- +goose Up
+
+ALTER TABLE sources_stats ADD COLUMN karma BIGINT;
+
+CREATE TABLE reddit_post_details (
+    post_id UUID PRIMARY KEY,
+    updated_at TIMESTAMP NOT NULL,
+    subreddit TEXT NOT NULL,
+    upvote_ratio FLOAT NOT NULL DEFAULT 0.0,
+    num_crossposts BIGINT NOT NULL DEFAULT 0,
+    CONSTRAINT fk_reddit_post_details_post FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_reddit_post_details_subreddit ON reddit_post_details (subreddit);
+
+-- +goose Down
+
+DROP TABLE reddit_post_details;
+
+ALTER TABLE sources_stats DROP COLUMN karma;
@@ -0,0 +1,11 @@
+-- +goose Up
+
+ALTER TABLE sources_stats ADD COLUMN followers_delta BIGINT;
+
+ALTER TABLE sources_stats ADD COLUMN following_delta BIGINT;
+
+-- +goose Down
+
+ALTER TABLE sources_stats DROP COLUMN following_delta;
+
+ALTER TABLE sources_stats DROP COLUMN followers_delta;
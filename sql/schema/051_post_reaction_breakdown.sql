@@ -0,0 +1,20 @@
+-- +goose Up
+
+CREATE TABLE post_reaction_breakdown (
+    id UUID PRIMARY KEY,
+    synced_at TIMESTAMP NOT NULL,
+    post_id UUID NOT NULL,
+    reaction_key TEXT NOT NULL,
+    count BIGINT NOT NULL,
+    CONSTRAINT fk_post_reaction_breakdown_post FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX post_reaction_breakdown_post_key_date_idx ON post_reaction_breakdown (
+    post_id,
+    reaction_key,
+    (synced_at::DATE)
+);
+
+-- +goose Down
+
+DROP TABLE post_reaction_breakdown;
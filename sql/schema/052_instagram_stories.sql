@@ -0,0 +1,22 @@
+-- +goose Up
+
+CREATE TABLE instagram_story_snapshots (
+    id UUID PRIMARY KEY,
+    synced_at TIMESTAMP NOT NULL,
+    post_id UUID NOT NULL,
+    reach BIGINT NOT NULL DEFAULT 0,
+    views BIGINT NOT NULL DEFAULT 0,
+    replies BIGINT NOT NULL DEFAULT 0,
+    shares BIGINT NOT NULL DEFAULT 0,
+    total_interactions BIGINT NOT NULL DEFAULT 0,
+    taps_forward BIGINT NOT NULL DEFAULT 0,
+    taps_back BIGINT NOT NULL DEFAULT 0,
+    exits BIGINT NOT NULL DEFAULT 0,
+    CONSTRAINT fk_instagram_story_snapshots_post FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_instagram_story_snapshots_post ON instagram_story_snapshots (post_id, synced_at);
+
+-- +goose Down
+
+DROP TABLE instagram_story_snapshots;
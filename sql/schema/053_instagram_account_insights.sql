@@ -0,0 +1,31 @@
+-- +goose Up
+
+CREATE TABLE instagram_account_insights (
+    id UUID PRIMARY KEY,
+    date DATE NOT NULL,
+    source_id UUID NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    reach BIGINT NOT NULL DEFAULT 0,
+    profile_views BIGINT NOT NULL DEFAULT 0,
+    website_clicks BIGINT NOT NULL DEFAULT 0,
+    accounts_engaged BIGINT NOT NULL DEFAULT 0,
+    follower_demographics JSONB NOT NULL DEFAULT '{}',
+    CONSTRAINT fk_instagram_account_insights_source FOREIGN KEY (source_id) REFERENCES sources (id) ON DELETE CASCADE,
+    CONSTRAINT uq_instagram_account_insights_source_date UNIQUE (source_id, date)
+);
+
+CREATE TABLE instagram_online_followers (
+    id UUID PRIMARY KEY,
+    date DATE NOT NULL,
+    source_id UUID NOT NULL,
+    hour_of_day INT NOT NULL,
+    followers BIGINT NOT NULL DEFAULT 0,
+    CONSTRAINT fk_instagram_online_followers_source FOREIGN KEY (source_id) REFERENCES sources (id) ON DELETE CASCADE,
+    CONSTRAINT uq_instagram_online_followers_source_date_hour UNIQUE (source_id, date, hour_of_day)
+);
+
+-- +goose Down
+
+DROP TABLE instagram_online_followers;
+
+DROP TABLE instagram_account_insights;
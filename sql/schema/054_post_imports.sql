@@ -0,0 +1,12 @@
+-- +goose Up
+
+CREATE TABLE post_imports (
+    post_id UUID PRIMARY KEY,
+    imported_at TIMESTAMP NOT NULL,
+    archive_format TEXT NOT NULL,
+    CONSTRAINT fk_post_imports_post FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE
+);
+
+-- +goose Down
+
+DROP TABLE post_imports;
@@ -0,0 +1,14 @@
+-- +goose Up
+
+CREATE TABLE source_endpoints (
+    id UUID PRIMARY KEY,
+    source_id UUID NOT NULL,
+    default_base_url TEXT NOT NULL,
+    base_url TEXT NOT NULL,
+    CONSTRAINT fk_source_endpoints_source FOREIGN KEY (source_id) REFERENCES sources (id) ON DELETE CASCADE,
+    CONSTRAINT uq_source_endpoints_source_default UNIQUE (source_id, default_base_url)
+);
+
+-- +goose Down
+
+DROP TABLE source_endpoints;
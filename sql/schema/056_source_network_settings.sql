@@ -0,0 +1,16 @@
+-- +goose Up
+
+-- The proxy URL (which may carry credentials) and extra headers (often API
+-- keys or cookies) are kept in one AES-GCM encrypted JSON document.
+CREATE TABLE source_network_settings (
+    source_id UUID PRIMARY KEY,
+    updated_at TIMESTAMP NOT NULL,
+    user_agent TEXT NOT NULL DEFAULT '',
+    encrypted_settings BYTEA NOT NULL,
+    nonce BYTEA NOT NULL,
+    CONSTRAINT fk_source_network_settings_source FOREIGN KEY (source_id) REFERENCES sources (id) ON DELETE CASCADE
+);
+
+-- +goose Down
+
+DROP TABLE source_network_settings;
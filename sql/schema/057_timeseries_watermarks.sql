@@ -0,0 +1,15 @@
+-- +goose Up
+
+CREATE TABLE timeseries_watermarks (
+    target_id UUID NOT NULL,
+    source_id UUID NOT NULL,
+    series TEXT NOT NULL,
+    last_exported_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (target_id, source_id, series),
+    CONSTRAINT fk_timeseries_watermarks_target FOREIGN KEY (target_id) REFERENCES targets (id) ON DELETE CASCADE,
+    CONSTRAINT fk_timeseries_watermarks_source FOREIGN KEY (source_id) REFERENCES sources (id) ON DELETE CASCADE
+);
+
+-- +goose Down
+
+DROP TABLE timeseries_watermarks;
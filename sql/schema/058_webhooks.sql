@@ -0,0 +1,30 @@
+-- +goose Up
+
+CREATE TABLE webhook_deliveries (
+    id UUID PRIMARY KEY,
+    target_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    event_type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    status TEXT NOT NULL,
+    attempts INTEGER NOT NULL,
+    response_code INTEGER,
+    error TEXT,
+    CONSTRAINT fk_webhook_deliveries_target FOREIGN KEY (target_id) REFERENCES targets (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_webhook_deliveries_target_created ON webhook_deliveries (target_id, created_at DESC);
+
+CREATE TABLE webhook_milestones (
+    target_id UUID NOT NULL,
+    subject_id UUID NOT NULL,
+    metric TEXT NOT NULL,
+    milestone BIGINT NOT NULL,
+    PRIMARY KEY (target_id, subject_id, metric),
+    CONSTRAINT fk_webhook_milestones_target FOREIGN KEY (target_id) REFERENCES targets (id) ON DELETE CASCADE
+);
+
+-- +goose Down
+
+DROP TABLE webhook_milestones;
+DROP TABLE webhook_deliveries;
@@ -0,0 +1,13 @@
+-- +goose Up
+
+CREATE TABLE file_target_settings (
+    target_id UUID PRIMARY KEY,
+    updated_at TIMESTAMP NOT NULL,
+    columns JSONB NOT NULL DEFAULT '{}',
+    zip_archive BOOLEAN NOT NULL DEFAULT FALSE,
+    CONSTRAINT fk_file_target_settings_target FOREIGN KEY (target_id) REFERENCES targets (id) ON DELETE CASCADE
+);
+
+-- +goose Down
+
+DROP TABLE file_target_settings;
@@ -0,0 +1,7 @@
+-- +goose Up
+
+ALTER TABLE file_target_settings ADD COLUMN formats JSONB NOT NULL DEFAULT '["csv"]';
+
+-- +goose Down
+
+ALTER TABLE file_target_settings DROP COLUMN formats;
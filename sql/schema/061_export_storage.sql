@@ -0,0 +1,23 @@
+-- +goose Up
+
+CREATE TABLE export_storage (
+    user_id UUID PRIMARY KEY,
+    updated_at TIMESTAMP NOT NULL,
+    storage_type TEXT NOT NULL,
+    CONSTRAINT export_storage_type_check
+        CHECK (storage_type IN ('S3', 'WebDAV')),
+    endpoint TEXT NOT NULL,
+    bucket TEXT NOT NULL DEFAULT '',
+    region TEXT NOT NULL DEFAULT '',
+    path_prefix TEXT NOT NULL DEFAULT '',
+    access_key TEXT NOT NULL DEFAULT '',
+    encrypted_secret BYTEA NOT NULL,
+    nonce BYTEA NOT NULL,
+    retention_days INTEGER NOT NULL DEFAULT 0,
+    keep_local BOOLEAN NOT NULL DEFAULT FALSE,
+    CONSTRAINT fk_export_storage_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+-- +goose Down
+
+DROP TABLE export_storage;
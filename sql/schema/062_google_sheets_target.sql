@@ -0,0 +1,26 @@
+-- +goose Up
+ALTER TABLE targets DROP CONSTRAINT type_check;
+
+ALTER TABLE targets
+ADD CONSTRAINT type_check CHECK (
+    target_type IN (
+        'NocoDB',
+        'Notion',
+        'CSV',
+        'None',
+        'Google Sheets'
+    )
+);
+
+-- +goose Down
+ALTER TABLE targets DROP CONSTRAINT type_check;
+
+ALTER TABLE targets
+ADD CONSTRAINT type_check CHECK (
+    target_type IN (
+        'NocoDB',
+        'Notion',
+        'CSV',
+        'None'
+    )
+);
@@ -0,0 +1,29 @@
+-- +goose Up
+ALTER TABLE targets DROP CONSTRAINT type_check;
+
+ALTER TABLE targets
+ADD CONSTRAINT type_check CHECK (
+    target_type IN (
+        'NocoDB',
+        'Notion',
+        'CSV',
+        'None',
+        'Google Sheets',
+        'Baserow',
+        'Grist'
+    )
+);
+
+-- +goose Down
+ALTER TABLE targets DROP CONSTRAINT type_check;
+
+ALTER TABLE targets
+ADD CONSTRAINT type_check CHECK (
+    target_type IN (
+        'NocoDB',
+        'Notion',
+        'CSV',
+        'None',
+        'Google Sheets'
+    )
+);
@@ -67,6 +67,9 @@ document.addEventListener("DOMContentLoaded", function () {
                 loadEngagementRate();
                 loadFollowRatio();
                 loadCollaborations();
+                loadTopReactions();
+                loadRedditSubreddits();
+                loadInstagramInsights();
                 break;
             case 'timing':
                 loadTiming();
@@ -433,6 +436,78 @@ document.addEventListener("DOMContentLoaded", function () {
             });
     }
 
+    function loadTopReactions() {
+        fetch(getFilteredUrl('/analytics/data/reactions'))
+            .then(res => res.json())
+            .then(data => {
+                if (!data || !Array.isArray(data) || data.length === 0) return;
+                document.getElementById('topReactionsCard').classList.remove('hidden');
+
+                const networks = [...new Set(data.map(d => d.network))];
+                const totals = {};
+                data.forEach(d => { totals[d.reaction_key] = (totals[d.reaction_key] || 0) + d.total_count; });
+                const keys = Object.keys(totals).sort((a, b) => totals[b] - totals[a]).slice(0, 20);
+
+                createChart('topReactionsChart', 'bar', {
+                    labels: keys.map(k => k.length > 24 ? k.substring(0, 24) + '…' : k),
+                    datasets: networks.map((network, i) => ({
+                        label: network,
+                        data: keys.map(k => data
+                            .filter(d => d.network === network && d.reaction_key === k)
+                            .reduce((sum, d) => sum + d.total_count, 0)),
+                        backgroundColor: colors.highContrast[i % colors.highContrast.length]
+                    }))
+                }, {
+                    scales: { x: { stacked: true }, y: { stacked: true } },
+                    plugins: {
+                        legend: { labels: { usePointStyle: true, padding: 20 } }
+                    }
+                });
+            });
+    }
+
+    function loadRedditSubreddits() {
+        fetch(getFilteredUrl('/analytics/data/reddit/subreddits'))
+            .then(res => res.json())
+            .then(data => {
+                if (!data || !Array.isArray(data) || data.length === 0) return;
+                document.getElementById('redditSubredditsCard').classList.remove('hidden');
+                const top = data.slice(0, 20);
+                createChart('redditSubredditsChart', 'bar', {
+                    labels: top.map(d => 'r/' + d.subreddit),
+                    datasets: [{
+                        label: 'Score',
+                        data: top.map(d => d.total_score),
+                        backgroundColor: colors.primary,
+                        yAxisID: 'y',
+                        order: 2
+                    }, {
+                        label: 'Comments',
+                        data: top.map(d => d.total_comments),
+                        backgroundColor: colors.primaryLight,
+                        yAxisID: 'y',
+                        order: 3
+                    }, {
+                        label: 'Avg Upvote Ratio (%)',
+                        data: top.map(d => Math.round(d.avg_upvote_ratio * 100)),
+                        borderColor: colors.accent,
+                        backgroundColor: colors.accent,
+                        type: 'line',
+                        yAxisID: 'y1',
+                        order: 1
+                    }]
+                }, {
+                    scales: {
+                        y: { position: 'left', title: { display: true, text: 'Total' } },
+                        y1: { position: 'right', min: 0, max: 100, title: { display: true, text: 'Upvote Ratio (%)' }, grid: { drawOnChartArea: false } }
+                    },
+                    plugins: {
+                        legend: { labels: { usePointStyle: true, padding: 20 } }
+                    }
+                });
+            });
+    }
+
     function loadTiming() {
         fetch(getFilteredUrl('/analytics/data/time'))
             .then(res => res.json())
@@ -441,7 +516,59 @@ document.addEventListener("DOMContentLoaded", function () {
                 const engKey = isViewsMode() ? 'avg_views' : 'avg_likes';
                 const header = document.getElementById('timingCardHeader');
                 if (header) header.textContent = `Best Time to Post (${engagementLabel()})`;
-                renderHeatmap(data, engKey);
+                renderHeatmap(data.posts, engKey);
+
+                const online = data.online_followers || [];
+                const onlineCard = document.getElementById('onlineFollowersCard');
+                if (onlineCard && online.length > 0) {
+                    onlineCard.classList.remove('hidden');
+                    renderHeatmap(online, 'avg_online_followers', 'online-heatmap-container', 'Avg Online Followers');
+                }
+            });
+    }
+
+    function loadInstagramInsights() {
+        fetch(getFilteredUrl('/analytics/data/instagram/insights'))
+            .then(res => res.json())
+            .then(data => {
+                if (!data || !Array.isArray(data.daily) || data.daily.length === 0) return;
+                document.getElementById('instagramInsightsCard').classList.remove('hidden');
+                createChart('instagramInsightsChart', 'line', {
+                    labels: data.daily.map(d => d.date_str),
+                    datasets: [{
+                        label: 'Reach',
+                        data: data.daily.map(d => d.reach),
+                        borderColor: colors.primary,
+                        backgroundColor: colors.primary,
+                        yAxisID: 'y'
+                    }, {
+                        label: 'Accounts Engaged',
+                        data: data.daily.map(d => d.accounts_engaged),
+                        borderColor: colors.primaryLight,
+                        backgroundColor: colors.primaryLight,
+                        yAxisID: 'y'
+                    }, {
+                        label: 'Profile Views',
+                        data: data.daily.map(d => d.profile_views),
+                        borderColor: colors.accent,
+                        backgroundColor: colors.accent,
+                        yAxisID: 'y1'
+                    }, {
+                        label: 'Website Clicks',
+                        data: data.daily.map(d => d.website_clicks),
+                        borderColor: colors.highContrast[3],
+                        backgroundColor: colors.highContrast[3],
+                        yAxisID: 'y1'
+                    }]
+                }, {
+                    scales: {
+                        y: { position: 'left', title: { display: true, text: 'Accounts' } },
+                        y1: { position: 'right', title: { display: true, text: 'Views / Clicks' }, grid: { drawOnChartArea: false } }
+                    },
+                    plugins: {
+                        legend: { labels: { usePointStyle: true, padding: 20 } }
+                    }
+                });
             });
     }
 
@@ -469,8 +596,8 @@ document.addEventListener("DOMContentLoaded", function () {
         tip.style.display = 'none';
     }
 
-    function renderHeatmap(data, engKey = 'avg_likes') {
-        const container = document.getElementById('heatmap-container');
+    function renderHeatmap(data, engKey = 'avg_likes', containerId = 'heatmap-container', label = null) {
+        const container = document.getElementById(containerId);
         container.replaceChildren();
         if (!data || data.length === 0) { showEmptyState(container); return; }
 
@@ -503,7 +630,7 @@ document.addEventListener("DOMContentLoaded", function () {
         const maxVal = Math.max(...data.map(d => d[engKey]));
         const dataMap = {};
         data.forEach(d => { dataMap[`${d.day_of_week}-${d.hour_of_day}`] = d[engKey]; });
-        const tooltipLabel = engKey === 'avg_views' ? 'Avg Views' : 'Avg Likes';
+        const tooltipLabel = label || (engKey === 'avg_views' ? 'Avg Views' : 'Avg Likes');
 
         for (let d = 0; d < 7; d++) {
             const row = document.createElement('div');
@@ -659,8 +786,10 @@ document.addEventListener("DOMContentLoaded", function () {
             fetch(getFilteredUrl('/analytics/data/site')).then(r => r.json()),
             fetch(getFilteredUrl('/analytics/data/pages')).then(r => r.json()),
             fetch(getFilteredUrl('/analytics/data/gsc/site')).then(r => r.json()),
-            fetch(getFilteredUrl('/analytics/data/gsc/pages')).then(r => r.json())
-        ]).then(([siteData, pagesData, gscSiteData, gscPagesData]) => {
+            fetch(getFilteredUrl('/analytics/data/gsc/pages')).then(r => r.json()),
+            fetch(getFilteredUrl('/analytics/data/youtube/channel')).then(r => r.json()),
+            fetch(getFilteredUrl('/analytics/data/youtube/videos')).then(r => r.json())
+        ]).then(([siteData, pagesData, gscSiteData, gscPagesData, ytChannelData, ytVideosData]) => {
             if (!siteData || !Array.isArray(siteData) || siteData.length === 0) {
                 emptyChartState('siteStatsChart');
             } else {
@@ -759,6 +888,91 @@ document.addEventListener("DOMContentLoaded", function () {
                     }
                 });
             }
+
+            if (!ytChannelData || !Array.isArray(ytChannelData) || ytChannelData.length === 0) {
+                emptyChartState('ytChannelChart');
+                emptyChartState('ytSubscribersChart');
+                emptyChartState('ytFormatChart');
+            } else {
+                const ytLabels = ytChannelData.map(d => d.date_str);
+                createChart('ytChannelChart', 'line', {
+                    labels: ytLabels,
+                    datasets: [{
+                        label: 'Views',
+                        data: ytChannelData.map(d => d.total_views),
+                        borderColor: colors.primary,
+                        backgroundColor: 'rgba(145, 103, 228, 0.1)',
+                        fill: true,
+                        yAxisID: 'y'
+                    }, {
+                        label: 'Watch Time (min)',
+                        data: ytChannelData.map(d => d.total_minutes_watched),
+                        borderColor: colors.primaryLight,
+                        borderDash: [5, 5],
+                        yAxisID: 'y1'
+                    }]
+                }, {
+                    scales: {
+                        y: { position: 'left', title: { display: true, text: 'Views' } },
+                        y1: { position: 'right', title: { display: true, text: 'Minutes' }, grid: { drawOnChartArea: false } }
+                    },
+                    plugins: {
+                        legend: { labels: { usePointStyle: true, padding: 20 } }
+                    }
+                });
+                createChart('ytSubscribersChart', 'bar', {
+                    labels: ytLabels,
+                    datasets: [{
+                        label: 'Gained',
+                        data: ytChannelData.map(d => d.subscribers_gained),
+                        backgroundColor: colors.highContrast[3]
+                    }, {
+                        label: 'Lost',
+                        data: ytChannelData.map(d => -d.subscribers_lost),
+                        backgroundColor: colors.highContrast[1]
+                    }]
+                }, {
+                    scales: { x: { stacked: true }, y: { stacked: true } },
+                    plugins: {
+                        legend: { labels: { usePointStyle: true, padding: 20 } }
+                    }
+                });
+                createChart('ytFormatChart', 'bar', {
+                    labels: ytLabels,
+                    datasets: [{
+                        label: 'Shorts',
+                        data: ytChannelData.map(d => d.shorts_views),
+                        backgroundColor: colors.primary
+                    }, {
+                        label: 'Long-form',
+                        data: ytChannelData.map(d => d.long_form_views),
+                        backgroundColor: colors.primaryLight
+                    }]
+                }, {
+                    scales: { x: { stacked: true }, y: { stacked: true } },
+                    plugins: {
+                        legend: { labels: { usePointStyle: true, padding: 20 } }
+                    }
+                });
+            }
+
+            if (!ytVideosData || !Array.isArray(ytVideosData) || ytVideosData.length === 0) {
+                emptyChartState('ytTopVideosChart');
+            } else {
+                createChart('ytTopVideosChart', 'bar', {
+                    labels: ytVideosData.slice(0, 15).map(d => ((d.content && d.content.String) || d.network_internal_id).split('\n')[0].substring(0, 60)),
+                    datasets: [{
+                        label: 'Net Subscribers',
+                        data: ytVideosData.slice(0, 15).map(d => d.net_subscribers),
+                        backgroundColor: colors.primary
+                    }]
+                }, {
+                    indexAxis: 'y',
+                    plugins: {
+                        legend: { labels: { usePointStyle: true, padding: 20 } }
+                    }
+                });
+            }
         });
     }
 
@@ -308,6 +308,34 @@ document.addEventListener('DOMContentLoaded', function () {
     }
   }
 
+  function renderCookieWarnings(warnings) {
+    const card = document.getElementById('cookie-warnings');
+    const list = document.getElementById('cookie-warnings-list');
+    if (!card || !list || !warnings || warnings.length === 0) return;
+
+    list.innerHTML = '';
+    warnings.forEach(w => {
+      const li = document.createElement('li');
+      li.className = 'p-2';
+
+      const expires = new Date(w.expires);
+      const text = document.createElement('span');
+      text.className = w.expired ? 'text-danger' : '';
+      text.textContent = w.expired
+        ? `${w.network} (${w.username}): login session expired on ${expires.toLocaleString()}. `
+        : `${w.network} (${w.username}): login session expires on ${expires.toLocaleString()}. `;
+
+      const link = document.createElement('a');
+      link.href = '/sources';
+      link.className = 'stat-link';
+      link.textContent = 'Renew cookies \u2192';
+
+      li.append(text, link);
+      list.appendChild(li);
+    });
+    card.classList.remove('hidden');
+  }
+
   function renderRecentLogs(logs) {
     const content = document.getElementById('recent-logs-content');
     if (!content) return;
@@ -493,6 +521,7 @@ document.addEventListener('DOMContentLoaded', function () {
       setStatValue('stat-total-page-views', data.total_page_views);
       setStatValue('stat-avg-session', data.average_website_session, 's');
       renderTopSources(data.top_sources, colors);
+      renderCookieWarnings(data.cookie_warnings);
     })
     .catch(err => {
       console.error('Error fetching dashboard stats:', err);
@@ -505,6 +534,13 @@ document.addEventListener('DOMContentLoaded', function () {
     .then(data => {
       if (data.engagement) renderComparisonChart('engagementComparisonChart', data.engagement.current_period, data.engagement.previous_period, '#9167e4');
       if (data.followers) renderComparisonChart('followersComparisonChart', data.followers.current_period, data.followers.previous_period, '#9167e4');
+      if (data.supporters) {
+        const points = data.supporters.current_period.concat(data.supporters.previous_period);
+        if (points.some(p => p.value > 0)) {
+          document.getElementById('supportersComparisonCard').classList.remove('hidden');
+          renderComparisonChart('supportersComparisonChart', data.supporters.current_period, data.supporters.previous_period, '#ff424d');
+        }
+      }
     })
     .catch(err => console.error('Error fetching dashboard summary:', err));
 